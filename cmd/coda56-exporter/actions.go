@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// registerActionHandlers exposes the actions that drive the modem itself.
+// They need a token with the actions scope, so they are refused outright
+// while the API is open.
+func registerActionHandlers(mux *http.ServeMux, client func(name string) *hitron.ModemClient, auth *APIAuth) {
+	mux.Handle("POST /actions/reboot", auth.Require(ScopeActions, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !auth.Enabled() {
+			writeJSONError(w, http.StatusForbidden, errors.New("rebooting the modem requires API tokens to be configured"))
+			return
+		}
+		name := r.URL.Query().Get("modem")
+		c := client(name)
+		if c == nil {
+			writeJSONError(w, http.StatusNotFound, fmt.Errorf("unknown modem %q", name))
+			return
+		}
+		slog.Info("Reboot requested through the API", "modem", c.BaseURL(), "client", r.RemoteAddr)
+		if err := c.Reboot(r.Context()); err != nil {
+			writeJSONError(w, http.StatusBadGateway, err)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "rebooting", "modem": c.BaseURL()})
+	})))
+}
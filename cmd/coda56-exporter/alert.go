@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// Webhook payload formats.
+const (
+	alertFormatGeneric = "generic"
+	alertFormatSlack   = "slack"
+	alertFormatDiscord = "discord"
+)
+
+// discordMaxContent is the longest message Discord accepts.
+const discordMaxContent = 2000
+
+// alertThresholds are the signal limits checked after every poll. Zero
+// UncorrectablesPerMinute disables the uncorrectables check.
+type alertThresholds struct {
+	DownstreamPowerMin      float64 `yaml:"downstream_power_min"`
+	DownstreamPowerMax      float64 `yaml:"downstream_power_max"`
+	SNRMin                  float64 `yaml:"snr_min"`
+	UpstreamPowerMax        float64 `yaml:"upstream_power_max"`
+	UncorrectablesPerMinute float64 `yaml:"uncorrectables_per_minute"`
+}
+
+func init() {
+	registerSink("alert_webhook", sinkType{
+		fromFlags: func(cfg *Config) (sink, error) {
+			if *alertWebhookURL == "" {
+				return nil, nil
+			}
+			s := defaultAlertSettings()
+			s.URL = *alertWebhookURL
+			sink, err := s.newSink(cfg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid alert settings: %w", err)
+			}
+			return sink, nil
+		},
+		fromConfig: func(cfg *Config, node *yaml.Node) (sink, error) {
+			s := defaultAlertSettings()
+			if err := decodeSinkSettings(node, &s); err != nil {
+				return nil, err
+			}
+			if s.URL == "" {
+				return nil, fmt.Errorf("url is required")
+			}
+			return s.newSink(cfg)
+		},
+	})
+}
+
+// alertSettings configure an alert webhook, as with the -alert.* flags.
+type alertSettings struct {
+	URL             string `yaml:"url"`
+	Format          string `yaml:"format"`
+	alertThresholds `yaml:",inline"`
+}
+
+// defaultAlertSettings are the flag settings other than the URL.
+func defaultAlertSettings() alertSettings {
+	return alertSettings{
+		Format: *alertWebhookFormat,
+		alertThresholds: alertThresholds{
+			DownstreamPowerMin:      *alertDsPowerMin,
+			DownstreamPowerMax:      *alertDsPowerMax,
+			SNRMin:                  *alertSNRMin,
+			UpstreamPowerMax:        *alertUsPowerMax,
+			UncorrectablesPerMinute: *alertUncorrectables,
+		},
+	}
+}
+
+func (s alertSettings) newSink(cfg *Config) (sink, error) {
+	return newAlertSink(s.URL, s.Format, s.alertThresholds, cfg.Timeout)
+}
+
+// alert is one crossed threshold, on a channel or, for uncorrectables, on
+// the modem as a whole.
+type alert struct {
+	Status    string            `json:"status"`
+	Rule      string            `json:"rule"`
+	Channel   string            `json:"channel,omitempty"`
+	Value     float64           `json:"value"`
+	Threshold string            `json:"threshold"`
+	Message   string            `json:"message"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Time      time.Time         `json:"time"`
+}
+
+// alertState is what alertSink remembers about one modem between polls.
+type alertState struct {
+	// firing are the alerts last posted as firing. They only change once
+	// the webhook accepted a post, so changes that failed to be delivered
+	// are posted again after the next poll.
+	firing         map[string]alert
+	uncorrectables float64
+	lastPoll       time.Time
+}
+
+// alertSink checks every poll against the thresholds and posts to a webhook
+// when an alert starts or clears, for setups without Alertmanager. A post
+// that fails is retried with the next poll's changes, which leave out an
+// alert that cleared before it was ever delivered. A page
+// that failed keeps its previous data in the snapshot, so its alerts stay
+// as they were rather than clearing.
+type alertSink struct {
+	url        string
+	format     string
+	thresholds alertThresholds
+	client     *http.Client
+
+	mu     sync.Mutex
+	modems map[string]*alertState
+}
+
+func newAlertSink(url, format string, thresholds alertThresholds, timeout time.Duration) (*alertSink, error) {
+	switch format {
+	case alertFormatGeneric, alertFormatSlack, alertFormatDiscord:
+	default:
+		return nil, fmt.Errorf("unknown webhook format %q (want %s, %s or %s)", format, alertFormatGeneric, alertFormatSlack, alertFormatDiscord)
+	}
+	if thresholds.DownstreamPowerMin > thresholds.DownstreamPowerMax {
+		return nil, fmt.Errorf("-alert.downstream-power-min must not be above -alert.downstream-power-max")
+	}
+	return &alertSink{
+		url:        url,
+		format:     format,
+		thresholds: thresholds,
+		client:     &http.Client{Timeout: timeout},
+		modems:     map[string]*alertState{},
+	}, nil
+}
+
+func (s *alertSink) Name() string { return "alert webhook" }
+
+func (s *alertSink) Write(snap *hitron.Snapshot, tags map[string]string) error {
+	changes, commit := s.evaluate(snap, tags)
+	if len(changes) > 0 {
+		if err := s.post(changes); err != nil {
+			return err
+		}
+	}
+	commit()
+	return nil
+}
+
+// evaluate returns the alerts that started or cleared since the changes
+// last delivered for the modem, and a function that records them as
+// delivered.
+func (s *alertSink) evaluate(snap *hitron.Snapshot, tags map[string]string) ([]alert, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := fmt.Sprint(tags)
+	state, ok := s.modems[key]
+	if !ok {
+		state = &alertState{firing: map[string]alert{}}
+		s.modems[key] = state
+	}
+
+	current := map[string]alert{}
+	add := func(rule, channel string, value float64, threshold, message string) {
+		current[rule+"/"+channel] = alert{
+			Status:    "firing",
+			Rule:      rule,
+			Channel:   channel,
+			Value:     value,
+			Threshold: threshold,
+			Message:   message,
+			Labels:    tags,
+			Time:      snap.Time,
+		}
+	}
+
+	t := s.thresholds
+	var uncorrectables float64
+	for _, p := range snapshotPoints(snap) {
+		if !pointLocked(p) {
+			// An unused OFDM receiver reports zeros rather than a signal
+			continue
+		}
+		channel := pointChannel(p)
+		for _, f := range p.fields {
+			switch {
+			case f.key == "power_dbmv" && (p.measurement == "downstream" || p.measurement == "ofdm_downstream"):
+				if f.value < t.DownstreamPowerMin || f.value > t.DownstreamPowerMax {
+					add("downstream_power", channel, f.value, fmt.Sprintf("%g to %g dBmV", t.DownstreamPowerMin, t.DownstreamPowerMax),
+						fmt.Sprintf("%s power %g dBmV is outside %g to %g dBmV", channel, f.value, t.DownstreamPowerMin, t.DownstreamPowerMax))
+				}
+			case f.key == "snr_db":
+				if f.value < t.SNRMin {
+					add("snr", channel, f.value, fmt.Sprintf("%g dB", t.SNRMin),
+						fmt.Sprintf("%s SNR %g dB is below %g dB", channel, f.value, t.SNRMin))
+				}
+			case f.key == "power_dbmv" && (p.measurement == "upstream" || p.measurement == "ofdm_upstream"):
+				if f.value > t.UpstreamPowerMax {
+					add("upstream_power", channel, f.value, fmt.Sprintf("%g dBmV", t.UpstreamPowerMax),
+						fmt.Sprintf("%s power %g dBmV is above %g dBmV", channel, f.value, t.UpstreamPowerMax))
+				}
+			case f.key == "uncorrectables":
+				uncorrectables += f.value
+			}
+		}
+	}
+
+	// A spike is judged against the previous poll; a lower total means the
+	// counters were reset, which says nothing about the signal.
+	if t.UncorrectablesPerMinute > 0 && !state.lastPoll.IsZero() && uncorrectables >= state.uncorrectables {
+		if minutes := snap.Time.Sub(state.lastPoll).Minutes(); minutes > 0 {
+			rate := (uncorrectables - state.uncorrectables) / minutes
+			if rate > t.UncorrectablesPerMinute {
+				add("uncorrectables", "", rate, fmt.Sprintf("%g/min", t.UncorrectablesPerMinute),
+					fmt.Sprintf("uncorrectable codewords are growing by %.0f/min, above %g/min", rate, t.UncorrectablesPerMinute))
+			}
+		}
+	}
+	state.uncorrectables, state.lastPoll = uncorrectables, snap.Time
+
+	var changes []alert
+	for k, a := range current {
+		if _, ok := state.firing[k]; !ok {
+			changes = append(changes, a)
+		}
+	}
+	for k, a := range state.firing {
+		if _, ok := current[k]; !ok {
+			a.Status = "resolved"
+			a.Time = snap.Time
+			changes = append(changes, a)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Status != changes[j].Status {
+			return changes[i].Status == "firing"
+		}
+		return changes[i].Message < changes[j].Message
+	})
+	return changes, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		// Keep the values of ongoing alerts current for the resolved
+		// message.
+		state.firing = current
+	}
+}
+
+// pointLocked reports whether the channel of p carries a signal. Only OFDM
+// downstream points say; others are taken as locked.
+func pointLocked(p point) bool {
+	for _, f := range p.fields {
+		if f.key == "plc_lock" {
+			return f.value == 1
+		}
+	}
+	return true
+}
+
+// pointChannel names the channel a point describes, such as
+// "downstream channel 12".
+func pointChannel(p point) string {
+	name := strings.ReplaceAll(p.measurement, "_", " ")
+	name = strings.Replace(name, "ofdm", "OFDM", 1)
+	for _, t := range p.tags {
+		if identityTags[t.key] {
+			return name + " channel " + t.value
+		}
+	}
+	return name
+}
+
+func (s *alertSink) post(alerts []alert) error {
+	var payload any
+	switch s.format {
+	case alertFormatSlack:
+		payload = map[string]string{"text": alertText(alerts)}
+	case alertFormatDiscord:
+		text := alertText(alerts)
+		if len(text) > discordMaxContent {
+			// Cut on a rune boundary, so the message stays valid UTF-8.
+			cut := discordMaxContent - len("...")
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+			text = text[:cut] + "..."
+		}
+		payload = map[string]string{"content": text}
+	default:
+		payload = map[string]any{"alerts": alerts}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post alert webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("alert webhook failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// alertText renders alerts as chat lines, such as
+// "[FIRING] modem=home: downstream channel 12 SNR 31.2 dB is below 33 dB".
+func alertText(alerts []alert) string {
+	var b strings.Builder
+	for _, a := range alerts {
+		fmt.Fprintf(&b, "[%s] ", strings.ToUpper(a.Status))
+		if len(a.Labels) > 0 {
+			keys := make([]string, 0, len(a.Labels))
+			for k := range a.Labels {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for i, k := range keys {
+				if i > 0 {
+					b.WriteString(",")
+				}
+				fmt.Fprintf(&b, "%s=%s", k, a.Labels[k])
+			}
+			b.WriteString(": ")
+		}
+		b.WriteString(a.Message)
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
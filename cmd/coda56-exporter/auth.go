@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// API token scopes.
+const (
+	ScopeRead    = "read"
+	ScopeActions = "actions"
+	ScopeIngest  = "ingest"
+)
+
+type apiToken struct {
+	name   string
+	secret string
+	scopes map[string]bool
+}
+
+// APIAuth checks bearer tokens on the JSON API and action endpoints. It is
+// independent of any authentication protecting the Prometheus scrape path.
+// Until tokens are configured every request is allowed.
+type APIAuth struct {
+	mu     sync.RWMutex
+	tokens []apiToken
+}
+
+func NewAPIAuth() *APIAuth {
+	return &APIAuth{}
+}
+
+// SetTokens replaces the accepted tokens, e.g. after a config reload.
+func (a *APIAuth) SetTokens(tokens []apiToken) {
+	a.mu.Lock()
+	a.tokens = tokens
+	a.mu.Unlock()
+}
+
+// Enabled reports whether any tokens are configured.
+func (a *APIAuth) Enabled() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.tokens) > 0
+}
+
+func newAPIToken(name, secret string, scopes []string) (apiToken, error) {
+	tok := apiToken{name: name, secret: secret, scopes: map[string]bool{}}
+	if name == "" || secret == "" {
+		return tok, fmt.Errorf("token name and secret are required")
+	}
+	for _, scope := range scopes {
+		if scope != ScopeRead && scope != ScopeActions && scope != ScopeIngest {
+			return tok, fmt.Errorf("unknown scope %q", scope)
+		}
+		tok.scopes[scope] = true
+	}
+	return tok, nil
+}
+
+// LoadAPITokens reads a token file with one token per line in the form
+//
+//	<name> <token> <scope>[,<scope>...]
+//
+// Blank lines and lines starting with # are ignored.
+func LoadAPITokens(path string) ([]apiToken, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open API token file: %w", err)
+	}
+	defer f.Close()
+
+	var tokens []apiToken
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("API token file line %d: expected \"<name> <token> <scopes>\"", lineNo)
+		}
+		tok, err := newAPIToken(fields[0], fields[1], strings.Split(fields[2], ","))
+		if err != nil {
+			return nil, fmt.Errorf("API token file line %d: %w", lineNo, err)
+		}
+		tokens = append(tokens, tok)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read API token file: %w", err)
+	}
+	return tokens, nil
+}
+
+func (a *APIAuth) lookup(secret string) *apiToken {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	var found *apiToken
+	// Compare against every token so timing doesn't reveal which one matched
+	for i := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(a.tokens[i].secret), []byte(secret)) == 1 {
+			tok := a.tokens[i]
+			found = &tok
+		}
+	}
+	return found
+}
+
+// Require wraps h so that it is only served to bearer tokens holding scope.
+func (a *APIAuth) Require(scope string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.Enabled() {
+			h.ServeHTTP(w, r)
+			return
+		}
+		secret, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="coda56-exporter"`)
+			writeJSONError(w, http.StatusUnauthorized, fmt.Errorf("missing bearer token"))
+			return
+		}
+		tok := a.lookup(strings.TrimSpace(secret))
+		if tok == nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="coda56-exporter", error="invalid_token"`)
+			writeJSONError(w, http.StatusUnauthorized, fmt.Errorf("invalid bearer token"))
+			return
+		}
+		if !tok.scopes[scope] {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="coda56-exporter", error="insufficient_scope", scope=%q`, scope))
+			writeJSONError(w, http.StatusForbidden, fmt.Errorf("token %q lacks the %q scope", tok.name, scope))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
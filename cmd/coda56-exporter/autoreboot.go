@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// RebootPolicy reboots the modem when its signal stays degraded, which often
+// clears a modem stuck in a bad state before the ISP has to be called. The
+// zero value never reboots.
+type RebootPolicy struct {
+	// UncorrectablesPerMinute marks a poll as degraded when the downstream
+	// channels' uncorrectable codewords grew faster than this since the
+	// previous poll; 0 disables the check.
+	UncorrectablesPerMinute float64
+	// OFDMLockLoss marks a poll as degraded when the modem reports OFDM
+	// channels but none of them is locked.
+	OFDMLockLoss bool
+	// Polls is how many degraded polls in a row trigger a reboot.
+	Polls int
+	// Cooldown is the minimum time between two reboots.
+	Cooldown time.Duration
+}
+
+func (p RebootPolicy) enabled() bool {
+	return p.UncorrectablesPerMinute > 0 || p.OFDMLockLoss
+}
+
+// rebootPolicyCollector applies a RebootPolicy to every poll and counts the
+// reboots it triggered.
+type rebootPolicyCollector struct {
+	policy RebootPolicy
+	state  *rebootPolicyState
+
+	rebootsDesc *prometheus.Desc
+}
+
+// rebootPolicyState is what a reboot policy remembers about a modem. It is
+// kept in the counterStore, so that a configuration reload neither ends the
+// cooldown nor forgets the run of degraded polls.
+type rebootPolicyState struct {
+	mu sync.Mutex
+	// uncorrectables is the total from the last poll, taken at lastPoll.
+	uncorrectables float64
+	lastPoll       time.Time
+	degraded       int
+	lastReboot     time.Time
+	reboots        float64
+}
+
+func newRebootPolicyCollector(policy RebootPolicy) *rebootPolicyCollector {
+	return &rebootPolicyCollector{
+		policy: policy,
+		state:  &rebootPolicyState{},
+		rebootsDesc: prometheus.NewDesc(
+			"auto_reboots_total",
+			"Modem reboots triggered by the automatic reboot policy",
+			nil, nil,
+		),
+	}
+}
+
+// update checks one poll's downstream pages. Pages that failed are passed
+// as nil with their error; a poll without downstream data neither counts as
+// degraded nor breaks a run of degraded polls, since an unreachable modem
+// can't be helped by a reboot request it won't receive.
+func (c *rebootPolicyCollector) update(client *hitron.ModemClient, now time.Time,
+	ds []hitron.DownstreamInfo, dsErr error, ofdm []hitron.OFDMDownstreamInfo, ofdmErr error) {
+	if !c.policy.enabled() || dsErr != nil {
+		return
+	}
+
+	s := c.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var reasons []string
+	total := totalUncorrectables(ds, ofdm)
+	if c.policy.UncorrectablesPerMinute > 0 && !s.lastPoll.IsZero() && total >= s.uncorrectables {
+		if minutes := now.Sub(s.lastPoll).Minutes(); minutes > 0 {
+			if rate := (total - s.uncorrectables) / minutes; rate > c.policy.UncorrectablesPerMinute {
+				reasons = append(reasons, "uncorrectables "+strconv.FormatFloat(rate, 'f', 0, 64)+"/min")
+			}
+		}
+	}
+	s.uncorrectables, s.lastPoll = total, now
+	if c.policy.OFDMLockLoss && ofdmErr == nil && len(ofdm) > 0 && !anyOFDMLocked(ofdm) {
+		reasons = append(reasons, "no OFDM channel locked")
+	}
+
+	if len(reasons) == 0 {
+		s.degraded = 0
+		return
+	}
+	s.degraded++
+	slog.Info("Modem signal degraded", "modem", client.BaseURL(), "reasons", strings.Join(reasons, ", "),
+		"polls", s.degraded, "threshold", c.policy.Polls)
+	if s.degraded < c.policy.Polls {
+		return
+	}
+	if !s.lastReboot.IsZero() && now.Sub(s.lastReboot) < c.policy.Cooldown {
+		slog.Info("Not rebooting the modem during the cooldown", "modem", client.BaseURL(),
+			"last_reboot", s.lastReboot, "cooldown", c.policy.Cooldown)
+		return
+	}
+
+	s.degraded = 0
+	s.lastReboot = now
+	s.reboots++
+	slog.Warn("Rebooting modem after sustained signal degradation", "modem", client.BaseURL(), "reasons", strings.Join(reasons, ", "))
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := client.Reboot(ctx); err != nil {
+			slog.Error("Automatic reboot failed", "modem", client.BaseURL(), "err", err)
+		}
+	}()
+}
+
+// totalUncorrectables sums the uncorrectable codewords of every downstream
+// channel. Values that don't parse are left out.
+func totalUncorrectables(ds []hitron.DownstreamInfo, ofdm []hitron.OFDMDownstreamInfo) float64 {
+	var total float64
+	for _, ch := range ds {
+		if v, err := strconv.ParseFloat(strings.TrimSpace(ch.Uncorrect), 64); err == nil {
+			total += v
+		}
+	}
+	for _, ch := range ofdm {
+		if v, err := strconv.ParseFloat(strings.TrimSpace(ch.Uncorrect), 64); err == nil {
+			total += v
+		}
+	}
+	return total
+}
+
+func anyOFDMLocked(ofdm []hitron.OFDMDownstreamInfo) bool {
+	for _, ch := range ofdm {
+		if strings.EqualFold(strings.TrimSpace(ch.PLCLock), "YES") {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *rebootPolicyCollector) describe(ch chan<- *prometheus.Desc) {
+	if c.policy.enabled() {
+		ch <- c.rebootsDesc
+	}
+}
+
+func (c *rebootPolicyCollector) collect(ch chan<- prometheus.Metric) {
+	if !c.policy.enabled() {
+		return
+	}
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	ch <- prometheus.MustNewConstMetric(c.rebootsDesc, prometheus.CounterValue, c.state.reboots)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+type benchmarkResult struct {
+	endpoint  string
+	latencies []time.Duration
+	failures  int
+}
+
+func (r *benchmarkResult) percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	idx := int(p * float64(len(r.latencies)-1))
+	return r.latencies[idx]
+}
+
+// runBenchmarkCommand implements the "benchmark" subcommand, which fetches
+// each endpoint back to back for a fixed duration and reports latency
+// distributions, to help pick sane -interval and -timeout values.
+func runBenchmarkCommand(client *hitron.ModemClient, args []string) error {
+	fs := flag.NewFlagSet("benchmark", flag.ContinueOnError)
+	duration := fs.Duration("duration", 30*time.Second, "How long to benchmark each endpoint")
+	pause := fs.Duration("pause", 0, "Delay between consecutive requests")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client.DetectModel(context.Background())
+	endpoints := client.Endpoints()
+	if fs.NArg() > 0 {
+		endpoints = fs.Args()
+		for _, e := range endpoints {
+			if !slices.Contains(client.Endpoints(), e) {
+				return fmt.Errorf("unknown endpoint %q", e)
+			}
+		}
+	}
+
+	// Per-request logging would drown out the report
+	defer silenceLogs()()
+
+	var results []*benchmarkResult
+	for _, endpoint := range endpoints {
+		fmt.Fprintf(os.Stderr, "Benchmarking %s for %s...\n", endpoint, *duration)
+		r := &benchmarkResult{endpoint: endpoint}
+		deadline := time.Now().Add(*duration)
+		for time.Now().Before(deadline) {
+			start := time.Now()
+			_, err := client.Get(context.Background(), endpoint)
+			elapsed := time.Since(start)
+			if err != nil {
+				r.failures++
+			} else {
+				r.latencies = append(r.latencies, elapsed)
+			}
+			time.Sleep(*pause)
+		}
+		sort.Slice(r.latencies, func(i, j int) bool { return r.latencies[i] < r.latencies[j] })
+		results = append(results, r)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENDPOINT\tREQUESTS\tFAILED\tMIN\tP50\tP90\tP99\tMAX")
+	for _, r := range results {
+		total := len(r.latencies) + r.failures
+		failRate := 0.0
+		if total > 0 {
+			failRate = float64(r.failures) / float64(total) * 100
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d (%.1f%%)\t%s\t%s\t%s\t%s\t%s\n",
+			r.endpoint, total, r.failures, failRate,
+			r.percentile(0).Round(time.Millisecond),
+			r.percentile(0.5).Round(time.Millisecond),
+			r.percentile(0.9).Round(time.Millisecond),
+			r.percentile(0.99).Round(time.Millisecond),
+			r.percentile(1).Round(time.Millisecond))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// pageCheck fetches one endpoint and parses it, returning a short summary of
+// what was found.
+type pageCheck struct {
+	endpoint string
+	parse    func([]byte) (string, error)
+	// optional endpoints only exist on some firmware, so a failure is
+	// reported but doesn't fail the check.
+	optional bool
+}
+
+func countOf[T any](what string, parse func([]byte) ([]T, error)) func([]byte) (string, error) {
+	return func(data []byte) (string, error) {
+		items, err := parse(data)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d %s", len(items), what), nil
+	}
+}
+
+// pageChecks lists the checks for a modem, using its model's pages and
+// parsers.
+func pageChecks(cfg *Config, client *hitron.ModemClient) []pageCheck {
+	a := client.Adapter()
+	checks := []pageCheck{
+		{endpoint: a.Endpoint(hitron.PageDownstream), parse: countOf("channels", a.ParseDownstream)},
+		{endpoint: a.Endpoint(hitron.PageUpstream), parse: countOf("channels", a.ParseUpstream)},
+		{endpoint: a.Endpoint(hitron.PageOFDMDownstream), parse: countOf("channels", a.ParseOFDMDownstream)},
+		{endpoint: a.Endpoint(hitron.PageOFDMUpstream), parse: countOf("channels", a.ParseOFDMUpstream)},
+		{endpoint: a.Endpoint(hitron.PageSystemInfo), parse: func(data []byte) (string, error) {
+			info, err := a.ParseSystemInfo(data)
+			if err != nil {
+				return "", err
+			}
+			return "firmware " + info.SWVersion, nil
+		}},
+		{endpoint: a.Endpoint(hitron.PageLinkStatus), parse: func(data []byte) (string, error) {
+			link, err := a.ParseLinkStatus(data)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("link %s at %s", link.LinkStatus, link.LinkSpeed), nil
+		}},
+	}
+	// Drop the pages the model doesn't serve
+	checks = slices.DeleteFunc(checks, func(c pageCheck) bool { return c.endpoint == "" })
+	if e := cfg.Collectors.UpstreamServiceFlowEndpoint; e != "" {
+		checks = append(checks, pageCheck{endpoint: e, parse: countOf("flows", hitron.ParseUpstreamServiceFlows), optional: true})
+	}
+	if e := cfg.Collectors.DownstreamServiceFlowEndpoint; e != "" {
+		checks = append(checks, pageCheck{endpoint: e, parse: countOf("flows", hitron.ParseDownstreamServiceFlows), optional: true})
+	}
+	if e := *cfg.Collectors.EventLogEndpoint; e != "" {
+		checks = append(checks, pageCheck{endpoint: e, parse: countOf("entries", hitron.ParseEventLog), optional: true})
+	}
+	if e := *cfg.Collectors.CMInitEndpoint; e != "" {
+		checks = append(checks, pageCheck{endpoint: e, parse: func(data []byte) (string, error) {
+			info, err := hitron.ParseCMInit(data)
+			if err != nil {
+				return "", err
+			}
+			return "registration " + info.Registration, nil
+		}, optional: true})
+	}
+	if e := *cfg.Collectors.DocsisWANEndpoint; e != "" {
+		checks = append(checks, pageCheck{endpoint: e, parse: func(data []byte) (string, error) {
+			wan, err := hitron.ParseDocsisWAN(data)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("ipv4 %s, ipv6 %s", wan.IPv4Address, wan.IPv6Address), nil
+		}, optional: true})
+	}
+	return checks
+}
+
+// runCheckCommand implements the "check" subcommand. The configuration has
+// already been validated by the time it runs; it then fetches and parses
+// every endpoint of each modem and reports the results, exiting non-zero if
+// a required endpoint fails.
+func runCheckCommand(cfg *Config, args []string) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	fmt.Println("Configuration OK")
+
+	type target struct {
+		name   string
+		client *hitron.ModemClient
+	}
+	var targets []target
+	for _, m := range cfg.Modems {
+		targets = append(targets, target{m.Name, cfg.newClient(m.Host, cfg.modemOptions(m)...)})
+	}
+	if len(targets) == 0 {
+		targets = append(targets, target{"", cfg.newClient(cfg.ModemHost, cfg.hostOptions()...)})
+	}
+
+	// Request logging would get mixed up with the report
+	defer silenceLogs()()
+
+	failed := 0
+	for _, t := range targets {
+		fmt.Println()
+		if t.name != "" {
+			fmt.Printf("Modem %s (%s)\n", t.name, t.client.BaseURL())
+		} else {
+			fmt.Printf("Modem %s\n", t.client.BaseURL())
+		}
+
+		t.client.DetectModel(context.Background())
+		fmt.Printf("Model %s\n", t.client.Adapter().Model())
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ENDPOINT\tRESULT\tLATENCY\tDETAIL")
+		for _, c := range pageChecks(cfg, t.client) {
+			start := time.Now()
+			data, err := t.client.Get(context.Background(), c.endpoint)
+			latency := time.Since(start).Round(time.Millisecond)
+			var detail string
+			if err == nil {
+				detail, err = c.parse(data)
+			}
+			result := "ok"
+			switch {
+			case err != nil && c.optional:
+				result, detail = "skipped", err.Error()
+			case err != nil:
+				result, detail = "FAIL", err.Error()
+				failed++
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.endpoint, result, latency, detail)
+		}
+		tw.Flush()
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d endpoint(s) failed", failed)
+	}
+	return nil
+}
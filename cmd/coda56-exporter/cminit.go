@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// cmInitCollector exports the DOCSIS initialization steps, which show a
+// modem that answers but hasn't finished registering with the CMTS.
+type cmInitCollector struct {
+	endpoint *optionalEndpoint
+
+	mu    sync.Mutex
+	steps []hitron.CMInitStep
+
+	step     *prometheus.Desc
+	complete *prometheus.Desc
+}
+
+func newCMInitCollector(endpoint string) *cmInitCollector {
+	return &cmInitCollector{
+		endpoint: &optionalEndpoint{name: endpoint},
+		step: prometheus.NewDesc(
+			"cm_init_step_done",
+			"Whether each DOCSIS initialization step completed (1 = done, 0 = not yet or failed), with the modem's status text",
+			[]string{"step", "status"}, nil,
+		),
+		complete: prometheus.NewDesc(
+			"cm_init_complete",
+			"Whether every DOCSIS initialization step completed (1 = provisioned, 0 = not)",
+			nil, nil,
+		),
+	}
+}
+
+func (c *cmInitCollector) update(ctx context.Context, client *hitron.ModemClient) {
+	if !c.endpoint.enabled() {
+		return
+	}
+	var steps []hitron.CMInitStep
+	info, err := client.GetCMInit(ctx, c.endpoint.name)
+	if err != nil {
+		slog.Warn("Failed to get CM init status", "err", err)
+		c.endpoint.check(err)
+	} else {
+		steps = info.Steps()
+	}
+	c.mu.Lock()
+	c.steps = steps
+	c.mu.Unlock()
+}
+
+func (c *cmInitCollector) describe(ch chan<- *prometheus.Desc) {
+	ch <- c.step
+	ch <- c.complete
+}
+
+func (c *cmInitCollector) collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.steps == nil {
+		return
+	}
+	complete := 1.0
+	for _, step := range c.steps {
+		done := 1.0
+		if !step.Done {
+			done, complete = 0, 0
+		}
+		ch <- prometheus.MustNewConstMetric(c.step, prometheus.GaugeValue, done, step.Name, step.Status)
+	}
+	ch <- prometheus.MustNewConstMetric(c.complete, prometheus.GaugeValue, complete)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+)
+
+// runCollectCommand polls the modems once and writes the metrics in the text
+// exposition format, for running from cron with node_exporter's textfile
+// collector picking up the result.
+func runCollectCommand(cfg *Config, args []string) error {
+	fs := flag.NewFlagSet("collect", flag.ContinueOnError)
+	output := fs.String("output", "", "File to write, e.g. /var/lib/node_exporter/textfile/modem.prom (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Without a background poller, gathering polls each modem.
+	reg := prometheus.NewRegistry()
+	collectors, err := (&exporter{}).newCollectors(cfg, reg)
+	if err != nil {
+		return err
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	write := func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		for _, mf := range families {
+			if _, err := expfmt.MetricFamilyToText(bw, mf); err != nil {
+				return err
+			}
+		}
+		return bw.Flush()
+	}
+	if *output == "" {
+		err = write(os.Stdout)
+	} else {
+		err = writeFileAtomic(*output, write)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+
+	// The metrics are written either way, so hitron_up records the failure,
+	// but cron should still see a non-zero exit.
+	for _, c := range collectors {
+		if snap := c.snapshot(); snap == nil || len(snap.Errors) == len(c.client.Endpoints()) {
+			return fmt.Errorf("failed to fetch any endpoint from %s", c.client.BaseURL())
+		}
+	}
+	return nil
+}
+
+// writeFileAtomic writes path through a temporary file in the same directory
+// and renames it into place, so readers never see a partial file.
+func writeFileAtomic(path string, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,482 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// Config is the YAML configuration file given with -config. Options given
+// explicitly on the command line take precedence over the file.
+type Config struct {
+	ModemHost       string            `yaml:"modem_host"`
+	ModemResolve    string            `yaml:"modem_resolve"`
+	ModemModel      string            `yaml:"modem_model"`
+	Timeout         time.Duration     `yaml:"timeout"`
+	CollectMode     string            `yaml:"collect_mode"`
+	Interval        *time.Duration    `yaml:"interval"`
+	IntervalJitter  float64           `yaml:"interval_jitter"`
+	MinPollInterval time.Duration     `yaml:"min_poll_interval"`
+	CacheTTL        time.Duration     `yaml:"cache_ttl"`
+	Retry           RetryConfig       `yaml:"retry"`
+	Breaker         BreakerConfig     `yaml:"breaker"`
+	Connection      ConnectionConfig  `yaml:"connection"`
+	SNMP            SNMPConfig        `yaml:"snmp"`
+	AutoReboot      AutoRebootConfig  `yaml:"auto_reboot"`
+	Watchdog        WatchdogConfig    `yaml:"watchdog"`
+	Spec            SpecConfig        `yaml:"spec"`
+	Modems          []ModemConfig     `yaml:"modems"`
+	Namespace       string            `yaml:"namespace"`
+	Labels          map[string]string `yaml:"labels"`
+	Collectors      CollectorsConfig  `yaml:"collectors"`
+	APITokens       []APITokenConfig  `yaml:"api_tokens"`
+	Sinks           []SinkConfig      `yaml:"sinks"`
+
+	// Credentials for the modem's web login, needed by firmware that puts
+	// data pages behind a session.
+	ModemUsername     string `yaml:"modem_username"`
+	ModemPassword     string `yaml:"modem_password"`
+	ModemPasswordFile string `yaml:"modem_password_file"`
+
+	TLS ModemTLSConfig `yaml:"tls"`
+
+	// ModemFallbacks are the schemes and ports tried when the modem keeps
+	// failing at its host, as with -modem-fallbacks. Empty by default.
+	ModemFallbacks []string `yaml:"modem_fallbacks"`
+
+	// ModemProxyURL is an HTTP or SOCKS5 proxy that all modems are reached
+	// through, as with -modem-proxy-url.
+	ModemProxyURL string `yaml:"modem_proxy_url"`
+
+	// RedactIdentifiers blanks the modem's serial number and MAC address,
+	// as with -redact-identifiers.
+	RedactIdentifiers bool `yaml:"redact_identifiers"`
+
+	// tlsConfig is built from TLS, and proxyURL from ModemProxyURL, by
+	// loadSettings.
+	tlsConfig *tls.Config
+	proxyURL  *url.URL
+}
+
+// CollectorsConfig toggles the optional collectors and pages.
+type CollectorsConfig struct {
+	// CombinedEndpoint and the optional pages enabled by default are
+	// pointers so that an explicit empty value can disable the page.
+	CombinedEndpoint              *string `yaml:"combined_endpoint"`
+	UpstreamServiceFlowEndpoint   string  `yaml:"upstream_service_flow_endpoint"`
+	DownstreamServiceFlowEndpoint string  `yaml:"downstream_service_flow_endpoint"`
+	EventLogEndpoint              *string `yaml:"event_log_endpoint"`
+	CMInitEndpoint                *string `yaml:"cm_init_endpoint"`
+	DocsisWANEndpoint             *string `yaml:"docsis_wan_endpoint"`
+	CompactLabels                 bool    `yaml:"compact_labels"`
+	Concurrency                   int     `yaml:"concurrency"`
+	RTTProbe                      string  `yaml:"rtt_probe"`
+	Strict                        bool    `yaml:"strict"`
+}
+
+// SinkConfig is an entry under sinks: an output of the given type, such as
+// influxdb, whose other settings are decoded by the type when the sinks
+// are set up.
+type SinkConfig struct {
+	Type string
+	node yaml.Node
+}
+
+func (s *SinkConfig) UnmarshalYAML(node *yaml.Node) error {
+	var entry struct {
+		Type string `yaml:"type"`
+	}
+	if err := node.Decode(&entry); err != nil {
+		return err
+	}
+	s.Type = entry.Type
+	s.node = *node
+	return nil
+}
+
+// MarshalYAML writes the entry back as it was given, for /config.
+func (s SinkConfig) MarshalYAML() (any, error) {
+	if s.node.Kind == 0 {
+		return map[string]string{"type": s.Type}, nil
+	}
+	return &s.node, nil
+}
+
+// RetryConfig sets how failed modem requests are repeated, as with the
+// -modem-retries, -modem-retry-delay and -modem-retry-jitter flags. Retries
+// and Jitter are pointers so that an explicit zero can turn them off.
+type RetryConfig struct {
+	Retries *int          `yaml:"retries"`
+	Delay   time.Duration `yaml:"delay"`
+	Jitter  *float64      `yaml:"jitter"`
+}
+
+// BreakerConfig sets up the circuit breaker for an unreachable modem, as with
+// the -modem-breaker-threshold and -modem-breaker-probe-interval flags.
+// Threshold is a pointer so that an explicit zero can disable it.
+type BreakerConfig struct {
+	Threshold     *int          `yaml:"threshold"`
+	ProbeInterval time.Duration `yaml:"probe_interval"`
+}
+
+// ConnectionConfig controls connection reuse, as with the -modem-keepalive,
+// -modem-max-idle-conns and -modem-idle-conn-timeout flags.
+type ConnectionConfig struct {
+	KeepAlive    *bool         `yaml:"keepalive"`
+	MaxIdleConns int           `yaml:"max_idle_conns"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+}
+
+// SNMPConfig reads the modem's DOCSIS SNMP agent, as with the -snmp.address,
+// -snmp.community and -snmp.mode flags.
+type SNMPConfig struct {
+	Address   string `yaml:"address"`
+	Community string `yaml:"community"`
+	Mode      string `yaml:"mode"`
+}
+
+// AutoRebootConfig sets up the automatic reboot policy, as with the
+// -auto-reboot.* flags.
+type AutoRebootConfig struct {
+	UncorrectablesPerMinute float64       `yaml:"uncorrectables_per_minute"`
+	OFDMLockLoss            bool          `yaml:"ofdm_lock_loss"`
+	Polls                   int           `yaml:"polls"`
+	Cooldown                time.Duration `yaml:"cooldown"`
+}
+
+// WatchdogConfig sets up the watchdog for a modem that keeps failing, as
+// with the -watchdog.* flags. ResetConnections is a pointer so that an
+// explicit false is kept.
+type WatchdogConfig struct {
+	Polls            int   `yaml:"polls"`
+	ResetConnections *bool `yaml:"reset_connections"`
+}
+
+// SpecConfig sets the signal ranges of the *_in_spec metrics, as with the
+// -spec.* flags. The fields are pointers so that an explicit zero is kept.
+type SpecConfig struct {
+	DownstreamPowerMin *float64 `yaml:"downstream_power_min"`
+	DownstreamPowerMax *float64 `yaml:"downstream_power_max"`
+	SNRMin             *float64 `yaml:"snr_min"`
+	UpstreamPowerMin   *float64 `yaml:"upstream_power_min"`
+	UpstreamPowerMax   *float64 `yaml:"upstream_power_max"`
+}
+
+// Collect modes.
+const (
+	// collectPull polls the modem on every scrape.
+	collectPull = "pull"
+	// collectBackground polls the modem every interval and answers scrapes
+	// from the last poll.
+	collectBackground = "background"
+)
+
+// SNMP modes.
+const (
+	snmpFallback = "fallback"
+	snmpOnly     = "only"
+)
+
+// ModemTLSConfig selects how the modem's certificate is verified, as with the
+// -modem-ca-file, -modem-cert-fingerprint and -modem-insecure-skip-verify
+// flags.
+type ModemTLSConfig struct {
+	CAFile             string `yaml:"ca_file"`
+	CertFingerprint    string `yaml:"cert_fingerprint"`
+	InsecureSkipVerify *bool  `yaml:"insecure_skip_verify"`
+}
+
+// APITokenConfig is a bearer token for the JSON API, as in -api-tokens-file.
+type APITokenConfig struct {
+	Name   string   `yaml:"name"`
+	Token  string   `yaml:"token"`
+	Scopes []string `yaml:"scopes"`
+}
+
+// ModemConfig describes one modem to poll. Every metric it produces carries
+// a modem="<name>" label plus any extra labels. Model, Username and
+// Password override the global settings; SNMP reads the modem's SNMP agent.
+type ModemConfig struct {
+	Name     string            `yaml:"name"`
+	Host     string            `yaml:"host"`
+	Resolve  string            `yaml:"resolve"`
+	Model    string            `yaml:"model"`
+	SNMP     SNMPConfig        `yaml:"snmp"`
+	Labels   map[string]string `yaml:"labels"`
+	Username string            `yaml:"username"`
+	Password string            `yaml:"password"`
+	// PasswordFile replaces Password with the file's contents, such as a
+	// Docker secret under /run/secrets.
+	PasswordFile string `yaml:"password_file"`
+}
+
+var (
+	labelNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	namespaceRe = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+)
+
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	return &cfg, nil
+}
+
+func validateLabelNames(labels map[string]string) error {
+	for k := range labels {
+		if !labelNameRe.MatchString(k) {
+			return fmt.Errorf("invalid label name %q", k)
+		}
+		if k == "modem" || k == "target" {
+			return fmt.Errorf("label %q is reserved", k)
+		}
+	}
+	return nil
+}
+
+func (s SNMPConfig) validate() error {
+	switch s.Mode {
+	case "", snmpFallback, snmpOnly:
+		return nil
+	}
+	return fmt.Errorf("mode must be %s or %s", snmpFallback, snmpOnly)
+}
+
+// validateResolve checks an address to connect to in place of the modem's
+// host, which may be empty.
+func validateResolve(addr string) error {
+	host := strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+	ip, _, _ := strings.Cut(host, "%")
+	if strings.Contains(host, ":") && net.ParseIP(ip) == nil {
+		return fmt.Errorf("invalid address %q: expected an IP address or host name without a port", addr)
+	}
+	return nil
+}
+
+func (c *Config) validate() error {
+	if c.ModemHost != "" {
+		if err := validateTarget(c.ModemHost); err != nil {
+			return fmt.Errorf("modem_host: %w", err)
+		}
+	}
+	if err := validateResolve(c.ModemResolve); err != nil {
+		return fmt.Errorf("modem_resolve: %w", err)
+	}
+	if err := validateModel(c.ModemModel); err != nil {
+		return fmt.Errorf("modem_model: %w", err)
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
+	if c.Interval != nil && *c.Interval < 0 {
+		return fmt.Errorf("interval must not be negative")
+	}
+	if c.IntervalJitter < 0 || c.IntervalJitter > 1 {
+		return fmt.Errorf("interval_jitter must be between 0 and 1")
+	}
+	switch c.CollectMode {
+	case "", collectPull:
+	case collectBackground:
+		if c.Interval != nil && *c.Interval == 0 {
+			return fmt.Errorf("collect_mode %s needs an interval above 0", collectBackground)
+		}
+	default:
+		return fmt.Errorf("unknown collect_mode %q, expected %s or %s", c.CollectMode, collectPull, collectBackground)
+	}
+	if c.MinPollInterval < 0 {
+		return fmt.Errorf("min_poll_interval must not be negative")
+	}
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("cache_ttl must not be negative")
+	}
+	if c.Retry.Retries != nil && *c.Retry.Retries < 0 {
+		return fmt.Errorf("retry: retries must not be negative")
+	}
+	if c.Retry.Delay < 0 {
+		return fmt.Errorf("retry: delay must not be negative")
+	}
+	if c.Retry.Jitter != nil && (*c.Retry.Jitter < 0 || *c.Retry.Jitter > 1) {
+		return fmt.Errorf("retry: jitter must be between 0 and 1")
+	}
+	if c.Breaker.Threshold != nil && *c.Breaker.Threshold < 0 {
+		return fmt.Errorf("breaker: threshold must not be negative")
+	}
+	if c.Breaker.ProbeInterval < 0 {
+		return fmt.Errorf("breaker: probe_interval must not be negative")
+	}
+	if c.Connection.MaxIdleConns < 0 {
+		return fmt.Errorf("connection: max_idle_conns must not be negative")
+	}
+	if c.Connection.IdleTimeout < 0 {
+		return fmt.Errorf("connection: idle_timeout must not be negative")
+	}
+	if c.AutoReboot.UncorrectablesPerMinute < 0 {
+		return fmt.Errorf("auto_reboot: uncorrectables_per_minute must not be negative")
+	}
+	if c.AutoReboot.Polls < 0 {
+		return fmt.Errorf("auto_reboot: polls must not be negative")
+	}
+	if c.AutoReboot.Cooldown < 0 {
+		return fmt.Errorf("auto_reboot: cooldown must not be negative")
+	}
+	if c.Watchdog.Polls < 0 {
+		return fmt.Errorf("watchdog: polls must not be negative")
+	}
+	if c.Spec.DownstreamPowerMin != nil && c.Spec.DownstreamPowerMax != nil && *c.Spec.DownstreamPowerMin > *c.Spec.DownstreamPowerMax {
+		return fmt.Errorf("spec: downstream_power_min must not be above downstream_power_max")
+	}
+	if c.Spec.UpstreamPowerMin != nil && c.Spec.UpstreamPowerMax != nil && *c.Spec.UpstreamPowerMin > *c.Spec.UpstreamPowerMax {
+		return fmt.Errorf("spec: upstream_power_min must not be above upstream_power_max")
+	}
+	if err := c.SNMP.validate(); err != nil {
+		return fmt.Errorf("snmp: %w", err)
+	}
+	if c.Collectors.Concurrency < 0 {
+		return fmt.Errorf("collectors: concurrency must not be negative")
+	}
+	switch c.Collectors.RTTProbe {
+	case "", hitron.RTTProbeTCP, hitron.RTTProbeICMP:
+	default:
+		return fmt.Errorf("collectors: rtt_probe must be %s or %s", hitron.RTTProbeTCP, hitron.RTTProbeICMP)
+	}
+	for i, entry := range c.Sinks {
+		if _, ok := sinkTypes[entry.Type]; !ok {
+			return fmt.Errorf("sinks: entry %d has unknown type %q, expected one of %s", i+1, entry.Type, strings.Join(sinkTypeNames(), ", "))
+		}
+	}
+	if c.Namespace != "" && !namespaceRe.MatchString(c.Namespace) {
+		return fmt.Errorf("invalid namespace %q", c.Namespace)
+	}
+	if err := validateLabelNames(c.Labels); err != nil {
+		return fmt.Errorf("labels: %w", err)
+	}
+	for i, t := range c.APITokens {
+		if _, err := newAPIToken(t.Name, t.Token, t.Scopes); err != nil {
+			return fmt.Errorf("api_tokens %d: %w", i, err)
+		}
+	}
+
+	names := map[string]bool{}
+	for i, m := range c.Modems {
+		if m.Name == "" {
+			return fmt.Errorf("modem %d: name is required", i)
+		}
+		if names[m.Name] {
+			return fmt.Errorf("modem %q: duplicate name", m.Name)
+		}
+		names[m.Name] = true
+		if err := validateTarget(m.Host); err != nil {
+			return fmt.Errorf("modem %q: %w", m.Name, err)
+		}
+		if err := validateResolve(m.Resolve); err != nil {
+			return fmt.Errorf("modem %q: resolve: %w", m.Name, err)
+		}
+		if err := validateModel(m.Model); err != nil {
+			return fmt.Errorf("modem %q: %w", m.Name, err)
+		}
+		if err := m.SNMP.validate(); err != nil {
+			return fmt.Errorf("modem %q: snmp: %w", m.Name, err)
+		}
+		if err := validateLabelNames(m.Labels); err != nil {
+			return fmt.Errorf("modem %q: %w", m.Name, err)
+		}
+		for k := range m.Labels {
+			if _, ok := c.Labels[k]; ok {
+				return fmt.Errorf("modem %q: label %q is already set for all modems", m.Name, k)
+			}
+		}
+	}
+	return nil
+}
+
+// background reports whether the modems are polled in the background rather
+// than on scrape.
+func (c *Config) background() bool {
+	return c.CollectMode == collectBackground
+}
+
+// registerer wraps reg so that metric names get the configured namespace as
+// a prefix and every series carries labels.
+func (c *Config) registerer(reg prometheus.Registerer, labels prometheus.Labels) prometheus.Registerer {
+	prefix := ""
+	if c.Namespace != "" {
+		prefix = c.Namespace + "_"
+	}
+	return prometheus.WrapRegistererWith(labels, prometheus.WrapRegistererWithPrefix(prefix, reg))
+}
+
+// labelFlag is a repeatable key=value flag.
+type labelFlag map[string]string
+
+func (f labelFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f labelFlag) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("expected key=value, got %q", s)
+	}
+	k = strings.TrimSpace(k)
+	if err := validateLabelNames(map[string]string{k: v}); err != nil {
+		return err
+	}
+	f[k] = v
+	return nil
+}
+
+// ModemLabels returns the constant labels for each modem: the global labels,
+// modem="<name>" and the modem's own labels. Prometheus requires every series
+// of a metric to share the same label names, so a label set on any modem is
+// added, empty, to the modems that don't define it.
+func (c *Config) ModemLabels() []map[string]string {
+	keys := map[string]bool{}
+	for _, m := range c.Modems {
+		for k := range m.Labels {
+			keys[k] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var out []map[string]string
+	for _, m := range c.Modems {
+		labels := map[string]string{"modem": m.Name}
+		for k, v := range c.Labels {
+			labels[k] = v
+		}
+		for _, k := range sorted {
+			labels[k] = m.Labels[k]
+		}
+		out = append(out, labels)
+	}
+	return out
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// secretPlaceholder replaces secret values, as in Prometheus's own
+// configuration page.
+const secretPlaceholder = "<secret>"
+
+// secretSuffixes are the endings of flag names and config keys, with dots and
+// dashes written as underscores, whose values are secret. Paths of secret
+// files aren't secrets themselves and are shown.
+var secretSuffixes = []string{"password", "token", "community", "secret", "webhook_url"}
+
+// isSecret reports whether the flag or config key name holds a secret.
+func isSecret(name string) bool {
+	name = strings.NewReplacer(".", "_", "-", "_").Replace(name)
+	for _, suffix := range secretSuffixes {
+		if name == suffix || strings.HasSuffix(name, "_"+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSetting hides the value of the setting name if it is a secret, and
+// the password of a URL with one, which becomes "xxxxx". Unset secrets stay
+// empty, so that it shows whether they are set.
+func redactSetting(name, value string) string {
+	if value == "" {
+		return value
+	}
+	if isSecret(name) {
+		return secretPlaceholder
+	}
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			return u.Redacted()
+		}
+	}
+	return value
+}
+
+// redactNode hides the secrets in a YAML tree whose key names, joined by
+// underscores, make up path. An entry of a list is named by its type key if
+// it has one, so that the url of an alert_webhook sink is treated like the
+// -alert.webhook-url flag.
+func redactNode(node *yaml.Node, path string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			redactNode(node.Content[i+1], path+"_"+node.Content[i].Value)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			name := path
+			if t := mappingValue(item, "type"); t != "" {
+				name += "_" + t
+			}
+			redactNode(item, name)
+		}
+	case yaml.ScalarNode:
+		if v := redactSetting(path, node.Value); v != node.Value {
+			node.Value = v
+			node.Style = 0
+		}
+	}
+}
+
+// mappingValue returns the value of a scalar key of a mapping node, or "".
+func mappingValue(node *yaml.Node, key string) string {
+	if node.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key && node.Content[i+1].Kind == yaml.ScalarNode {
+			return node.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+// configModem describes a modem as the exporter currently sees it.
+type configModem struct {
+	Name string `yaml:"name,omitempty"`
+	Host string `yaml:"host"`
+	// Model is the selected or, with -modem-model auto, detected model.
+	Model string `yaml:"model"`
+	// Pages maps each kind of status data to the page it is read from.
+	Pages map[string]string `yaml:"pages"`
+	// Collectors are the optional collectors in use, without those turned
+	// off because the firmware doesn't serve their page.
+	Collectors []string `yaml:"collectors"`
+}
+
+// configPage is the document served at /config.
+type configPage struct {
+	Config *yaml.Node        `yaml:"config"`
+	Flags  map[string]string `yaml:"flags"`
+	Modems []configModem     `yaml:"modems"`
+}
+
+// describeModem returns what c polls.
+func describeModem(c *MetricsCollector) configModem {
+	m := configModem{
+		Name:       c.tags["modem"],
+		Host:       redactSetting("host", c.client.BaseURL()),
+		Model:      c.client.Adapter().Model(),
+		Pages:      map[string]string{},
+		Collectors: []string{},
+	}
+	for _, p := range hitron.Pages {
+		if endpoint := c.client.Endpoint(p); endpoint != "" {
+			m.Pages[strings.ReplaceAll(p.String(), " ", "_")] = endpoint
+		}
+	}
+	for _, o := range []struct {
+		name     string
+		endpoint *optionalEndpoint
+	}{
+		{"upstream_service_flow", c.serviceFlows.upstream},
+		{"downstream_service_flow", c.serviceFlows.downstream},
+		{"event_log", c.eventLog.endpoint},
+		{"cm_init", c.cmInit.endpoint},
+		{"docsis_wan", c.wan.endpoint},
+	} {
+		if o.endpoint.enabled() {
+			m.Collectors = append(m.Collectors, o.name)
+		}
+	}
+	if c.latency.probe != "" {
+		m.Collectors = append(m.Collectors, "rtt_probe")
+	}
+	if c.autoReboot.policy.enabled() {
+		m.Collectors = append(m.Collectors, "auto_reboot")
+	}
+	return m
+}
+
+// registerConfigHandler serves the effective configuration at /config: the
+// config file merged with the flags, every flag's value, and the modems
+// being polled with their model and collectors. Passwords, tokens, SNMP
+// communities and webhook URLs are replaced by "<secret>", and passwords in
+// URLs by "xxxxx".
+func registerConfigHandler(mux *http.ServeMux, e *exporter, auth *APIAuth) {
+	mux.Handle("GET /config", auth.Require(ScopeRead, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var cfg yaml.Node
+		if err := cfg.Encode(e.config.Load()); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, fmt.Errorf("failed to encode config: %w", err))
+			return
+		}
+		redactNode(&cfg, "")
+		page := configPage{Config: &cfg, Flags: map[string]string{}, Modems: []configModem{}}
+		flag.VisitAll(func(f *flag.Flag) {
+			page.Flags[f.Name] = redactSetting(f.Name, f.Value.String())
+		})
+		if collectors := e.collectors.Load(); collectors != nil {
+			for _, c := range *collectors {
+				page.Modems = append(page.Modems, describeModem(c))
+			}
+		}
+		out, err := yaml.Marshal(page)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, fmt.Errorf("failed to encode config: %w", err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+		w.Write(out)
+	})))
+}
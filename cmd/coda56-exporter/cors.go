@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// CORSConfig controls which browser origins may call the JSON API.
+type CORSConfig struct {
+	AllowedOrigins []string
+	AllowedHeaders []string
+}
+
+func (c *CORSConfig) allowOrigin(origin string) string {
+	if slices.Contains(c.AllowedOrigins, "*") {
+		return "*"
+	}
+	if slices.Contains(c.AllowedOrigins, origin) {
+		return origin
+	}
+	return ""
+}
+
+// corsHandler adds CORS headers to /api/v1 responses and answers preflight
+// requests. It wraps the whole mux because method-specific routes would
+// otherwise reject OPTIONS before it reached the API handlers, and preflights
+// must succeed without credentials.
+func corsHandler(cfg *CORSConfig, next http.Handler) http.Handler {
+	if cfg == nil || len(cfg.AllowedOrigins) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !strings.HasPrefix(r.URL.Path, "/api/v1/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		allowed := cfg.allowOrigin(origin)
+		if allowed == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", allowed)
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// modemCounter is a cumulative count kept by the modem itself, such as a
+// channel's codeword errors. It is exported as-is rather than accumulated by
+// the exporter, which would count the same errors again on every poll.
+type modemCounter struct {
+	desc   *prometheus.Desc
+	value  float64
+	labels []string
+	// maxRate is the most the count can grow per second, for counters in a
+	// format that can wrap around, or 0 for those that don't.
+	maxRate float64
+
+	// created is set once the counter has been seen to reset.
+	created time.Time
+	// exemplar, if set, links the sample to the trace of the poll that
+	// read it.
+	exemplar *prometheus.Exemplar
+}
+
+func (m modemCounter) metric() prometheus.Metric {
+	var metric prometheus.Metric
+	if m.created.IsZero() {
+		metric = prometheus.MustNewConstMetric(m.desc, prometheus.CounterValue, m.value, m.labels...)
+	} else {
+		metric = prometheus.MustNewConstMetricWithCreatedTimestamp(m.desc, prometheus.CounterValue, m.value, m.created, m.labels...)
+	}
+	if m.exemplar == nil {
+		return metric
+	}
+	if withExemplar, err := prometheus.NewMetricWithExemplars(metric, *m.exemplar); err == nil {
+		return withExemplar
+	}
+	return metric
+}
+
+// counterResets remembers the last raw value of each modem counter. The modem
+// zeroes its counters when it reboots or re-locks a channel; when a value goes
+// backwards the series gets a new created timestamp, so Prometheus treats it
+// as a fresh counter instead of guessing from the drop alone. A counter with
+// a maxRate that drops from near the top of its range instead wrapped
+// around, if it was growing fast enough to get there, and keeps counting up
+// from its old value.
+type counterResets struct {
+	mu     sync.Mutex
+	series map[string]counterState
+	// rebooted is when the modem last booted, if that happened since the
+	// previous track.
+	rebooted time.Time
+}
+
+type counterState struct {
+	// Value is the modem's raw value, and Offset what wrapping around has
+	// added to it.
+	Value   float64   `json:"value"`
+	Offset  float64   `json:"offset,omitempty"`
+	Created time.Time `json:"created"`
+	// Seen is when Value was read.
+	Seen time.Time `json:"seen"`
+	// Rate is how fast Value grew per second up to Seen, if known.
+	Rate *float64 `json:"rate,omitempty"`
+}
+
+// Highest byte rates of one downstream channel, with some margin: a 256-QAM
+// SC-QAM channel carries about 43 Mbit/s and an OFDM channel up to about
+// 2 Gbit/s.
+const (
+	qamMaxByteRate  = 2 * 43e6 / 8
+	ofdmMaxByteRate = 2 * 2e9 / 8
+)
+
+// counterWidth is the range of a counter that reached v: some firmware
+// keeps 32-bit octet counts, which wrap after 4 GiB.
+func counterWidth(v float64) float64 {
+	if v < 1<<32 {
+		return 1 << 32
+	}
+	return 1 << 64
+}
+
+// wrapBurst is how much faster than before a counter may have grown in the
+// interval it wrapped in.
+const wrapBurst = 4
+
+// wrapped reports whether a counter that went down from s.Value to raw by
+// now more likely wrapped around than reset. It must have been in the top
+// half of its range, and the count it would have grown by to get past the
+// top must be possible at maxRate and, once the counter's rate is known, at
+// wrapBurst times that rate. However far apart the polls, a channel that
+// re-locks after counting a little, or slowly, starts a new series.
+func (s counterState) wrapped(raw, maxRate float64, now time.Time) bool {
+	if maxRate == 0 || s.Seen.IsZero() {
+		return false
+	}
+	width := counterWidth(s.Value)
+	if s.Value < width/2 {
+		return false
+	}
+	rate := maxRate
+	if s.Rate != nil {
+		rate = min(rate, wrapBurst**s.Rate)
+	}
+	return width-s.Value+raw <= rate*now.Sub(s.Seen).Seconds()
+}
+
+// perSecond returns n over d, or nil for an interval that isn't positive.
+func perSecond(n float64, d time.Duration) *float64 {
+	if d <= 0 {
+		return nil
+	}
+	rate := n / d.Seconds()
+	return &rate
+}
+
+func newCounterResets() *counterResets {
+	return &counterResets{series: map[string]counterState{}}
+}
+
+// reboot marks every counter as reset at boot, the modem's boot time, by
+// the next track. A reboot zeroes all counters, including those that were
+// already 0 or that grow past their old value before the next poll, and the
+// channels the modem locks afterwards start new series.
+func (r *counterResets) reboot(boot time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rebooted = boot
+}
+
+// track records each counter's value and fills in its created timestamp,
+// adding what the counter lost to wrapping around to its value. counters is
+// the complete set from one page, so series that are missing from it are
+// forgotten. A traceID of the poll gives each counter an exemplar.
+func (r *counterResets) track(counters []modemCounter, now time.Time, traceID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	series := make(map[string]counterState, len(counters))
+	rebooted := r.rebooted
+	r.rebooted = time.Time{}
+	for i := range counters {
+		// NUL rather than the usual 0xff keeps the key valid UTF-8 for
+		// the -state-file.
+		key := counters[i].desc.String() + "\x00" + strings.Join(counters[i].labels, "\x00")
+		state, seen := r.series[key]
+		raw := counters[i].value
+		// The rate is only known across an interval the counter kept
+		// counting in.
+		var rate *float64
+		switch {
+		case !rebooted.IsZero():
+			state.Created, state.Offset = rebooted, 0
+		case seen && raw < state.Value && state.wrapped(raw, counters[i].maxRate, now):
+			width := counterWidth(state.Value)
+			rate = perSecond(width-state.Value+raw, now.Sub(state.Seen))
+			state.Offset += width
+		case seen && raw < state.Value:
+			state.Created, state.Offset = now, 0
+		case seen:
+			rate = perSecond(raw-state.Value, now.Sub(state.Seen))
+		}
+		state.Value, state.Seen, state.Rate = raw, now, rate
+		series[key] = state
+		counters[i].value = state.Offset + raw
+		counters[i].created = state.Created
+		if traceID != "" {
+			counters[i].exemplar = &prometheus.Exemplar{
+				Value:     counters[i].value,
+				Labels:    prometheus.Labels{"trace_id": traceID},
+				Timestamp: now,
+			}
+		}
+	}
+	r.series = series
+}
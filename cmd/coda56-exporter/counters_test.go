@@ -0,0 +1,66 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestCounterWrapped(t *testing.T) {
+	seen := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	rate := func(r float64) *float64 { return &r }
+	tests := []struct {
+		name    string
+		state   counterState
+		raw     float64
+		maxRate float64
+		elapsed time.Duration
+		want    bool
+	}{
+		{"32-bit wrap", counterState{Value: 1<<32 - 1e8, Seen: seen}, 1e8, qamMaxByteRate, 30 * time.Second, true},
+		{"wrap at the known rate", counterState{Value: 1<<32 - 1e8, Seen: seen, Rate: rate(5e6)}, 1e8, qamMaxByteRate, 30 * time.Second, true},
+		{"too far from the top for the rate", counterState{Value: 1<<32 - 1e9, Seen: seen}, 1e8, qamMaxByteRate, 30 * time.Second, false},
+		{"too far from the top for the known rate", counterState{Value: 1<<32 - 1e8, Seen: seen, Rate: rate(1e5)}, 1e8, qamMaxByteRate, 30 * time.Second, false},
+		{"idle counter", counterState{Value: 1<<32 - 1e3, Seen: seen, Rate: rate(0)}, 10, qamMaxByteRate, 30 * time.Second, false},
+		// At OFDM rates a long interval spans the whole 32-bit range.
+		{"OFDM re-lock in the bottom half", counterState{Value: 1 << 30, Seen: seen}, 1e6, ofdmMaxByteRate, time.Minute, false},
+		{"OFDM wrap", counterState{Value: 1<<32 - 1e9, Seen: seen, Rate: rate(1e8)}, 1e9, ofdmMaxByteRate, 30 * time.Second, true},
+		{"64-bit counter", counterState{Value: 1 << 40, Seen: seen}, 1e6, ofdmMaxByteRate, time.Minute, false},
+		{"format that doesn't wrap", counterState{Value: 1<<32 - 1e8, Seen: seen}, 1e8, 0, 30 * time.Second, false},
+		{"never seen", counterState{Value: 1<<32 - 1e8}, 1e8, qamMaxByteRate, 30 * time.Second, false},
+	}
+	for _, tt := range tests {
+		if got := tt.state.wrapped(tt.raw, tt.maxRate, seen.Add(tt.elapsed)); got != tt.want {
+			t.Errorf("%s: wrapped = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCounterResetsTrack(t *testing.T) {
+	desc := prometheus.NewDesc("octets", "", []string{"channel_id"}, nil)
+	start := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		values      []float64
+		want        float64
+		wantCreated bool
+	}{
+		{"growing", []float64{1e8, 2e8, 3e8}, 3e8, false},
+		{"wrap", []float64{1<<32 - 2e8, 1<<32 - 1e8, 1e8}, 1<<32 + 1e8, false},
+		{"reset", []float64{1e8, 2e8, 1e6}, 1e6, true},
+		{"reset near the top after idling", []float64{1<<32 - 1e3, 1<<32 - 1e3, 10}, 10, true},
+	}
+	for _, tt := range tests {
+		r := newCounterResets()
+		var got modemCounter
+		for i, v := range tt.values {
+			counters := []modemCounter{{desc: desc, value: v, labels: []string{"1"}, maxRate: qamMaxByteRate}}
+			r.track(counters, start.Add(time.Duration(i)*30*time.Second), "")
+			got = counters[0]
+		}
+		if got.value != tt.want || got.created.IsZero() == tt.wantCreated {
+			t.Errorf("%s: value %v, created %v; want %v, new series %v", tt.name, got.value, got.created, tt.want, tt.wantCreated)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// dashboardPanel is one chart of the generated dashboard. unit is a Grafana
+// unit ID; stat panels show the latest value rather than a graph.
+type dashboardPanel struct {
+	title  string
+	expr   string
+	legend string
+	unit   string
+	stat   bool
+}
+
+// dashboardRow is a titled group of panels.
+type dashboardRow struct {
+	title  string
+	panels []dashboardPanel
+}
+
+// dashboardLabels returns the static label names of cfg's modems: the
+// global labels, and for a config file with modems, modem and the modems'
+// own labels.
+func dashboardLabels(cfg *Config) []string {
+	keys := map[string]bool{}
+	for k := range cfg.Labels {
+		keys[k] = true
+	}
+	for _, labels := range cfg.ModemLabels() {
+		for k := range labels {
+			keys[k] = true
+		}
+	}
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// generateDashboard builds a Grafana dashboard for the exporter's metrics as
+// cfg names and labels them. Each static label becomes a dashboard variable
+// that filters every panel.
+func generateDashboard(cfg *Config, title string) map[string]any {
+	prefix := ""
+	if cfg.Namespace != "" {
+		prefix = cfg.Namespace + "_"
+	}
+	labels := dashboardLabels(cfg)
+
+	var matchers []string
+	for _, l := range labels {
+		matchers = append(matchers, fmt.Sprintf(`%s=~"$%s"`, l, l))
+	}
+	selector := ""
+	if len(matchers) > 0 {
+		selector = "{" + strings.Join(matchers, ",") + "}"
+	}
+	metric := func(name string) string { return prefix + name + selector }
+	rate := func(name string) string { return "rate(" + metric(name) + "[$__rate_interval]) * 60" }
+
+	// Series of several modems need telling apart.
+	legend := func(s string) string {
+		if len(cfg.Modems) > 1 {
+			return "{{modem}} " + s
+		}
+		return s
+	}
+	channel := legend("{{channel_id}}")
+	receiver := legend("{{receive}}")
+	usch := legend("{{usch_index}}")
+
+	rows := []dashboardRow{
+		{"Overview", []dashboardPanel{
+			{title: "Modem up", expr: metric("up"), legend: legend("up"), stat: true},
+			{title: "Uptime", expr: metric("system_uptime_seconds"), legend: legend("uptime"), unit: "s", stat: true},
+			{title: "Bonded channels", expr: metric("downstream_bonded_channels"), legend: legend("downstream {{type}}"), stat: true},
+			{title: "Uncorrectables per minute", expr: rate("total_uncorrectables"), legend: legend("uncorrectables"), stat: true},
+		}},
+		{"Downstream", []dashboardPanel{
+			{title: "Downstream power", expr: metric("downstream_power_dbmv"), legend: channel, unit: "dBmV"},
+			{title: "Downstream SNR", expr: metric("downstream_snr_db"), legend: channel, unit: "dB"},
+			{title: "Correctables per minute", expr: rate("downstream_correctables"), legend: channel},
+			{title: "Uncorrectables per minute", expr: rate("downstream_uncorrectables"), legend: channel},
+		}},
+		{"OFDM", []dashboardPanel{
+			{title: "OFDM downstream power", expr: metric("ofdm_downstream_power_dbmv"), legend: receiver, unit: "dBmV"},
+			{title: "OFDM downstream SNR", expr: metric("ofdm_downstream_snr_db"), legend: receiver, unit: "dB"},
+			{title: "OFDM uncorrectables per minute", expr: rate("ofdm_downstream_uncorrectables"), legend: receiver},
+			{title: "OFDMA upstream power", expr: metric("ofdm_upstream_power_dbmv"), legend: usch, unit: "dBmV"},
+		}},
+		{"Upstream", []dashboardPanel{
+			{title: "Upstream power", expr: metric("upstream_power_dbmv"), legend: channel, unit: "dBmV"},
+			{title: "Upstream channel width", expr: metric("upstream_bandwidth_hz"), legend: channel, unit: "hertz"},
+		}},
+		{"Exporter", []dashboardPanel{
+			{title: "Endpoint up", expr: metric("endpoint_up"), legend: legend("{{endpoint}}")},
+			{title: "Poll duration", expr: metric("exporter_collect_duration_seconds"), legend: legend("poll"), unit: "s"},
+		}},
+	}
+
+	datasource := map[string]string{"type": "prometheus", "uid": "${datasource}"}
+	var panels []map[string]any
+	id, y := 1, 0
+	for _, row := range rows {
+		panels = append(panels, map[string]any{
+			"id": id, "type": "row", "title": row.title, "collapsed": false,
+			"gridPos": map[string]int{"x": 0, "y": y, "w": 24, "h": 1},
+		})
+		id++
+		y++
+		// Stat panels go four to a row, graphs two.
+		width, height := 12, 8
+		if len(row.panels) > 0 && row.panels[0].stat {
+			width, height = 6, 4
+		}
+		for i, p := range row.panels {
+			panelType := "timeseries"
+			if p.stat {
+				panelType = "stat"
+			}
+			panels = append(panels, map[string]any{
+				"id":         id,
+				"type":       panelType,
+				"title":      p.title,
+				"datasource": datasource,
+				"gridPos":    map[string]int{"x": i * width % 24, "y": y + i*width/24*height, "w": width, "h": height},
+				"fieldConfig": map[string]any{
+					"defaults":  map[string]any{"unit": p.unit},
+					"overrides": []any{},
+				},
+				"targets": []map[string]any{{
+					"refId": "A", "datasource": datasource, "expr": p.expr, "legendFormat": p.legend,
+				}},
+			})
+			id++
+		}
+		y += (len(row.panels)*width + 23) / 24 * height
+	}
+
+	variables := []map[string]any{{
+		"name": "datasource", "label": "Data source", "type": "datasource", "query": "prometheus",
+	}}
+	for _, l := range labels {
+		variables = append(variables, map[string]any{
+			"name":       l,
+			"label":      l,
+			"type":       "query",
+			"datasource": datasource,
+			"query":      map[string]string{"query": fmt.Sprintf("label_values(%sup, %s)", prefix, l), "refId": "A"},
+			"definition": fmt.Sprintf("label_values(%sup, %s)", prefix, l),
+			"refresh":    2,
+			"multi":      true,
+			"includeAll": true,
+			"allValue":   ".*",
+			"current":    map[string]any{"text": "All", "value": "$__all"},
+		})
+	}
+
+	uid := "coda56"
+	if cfg.Namespace != "" {
+		uid += "-" + cfg.Namespace
+	}
+	return map[string]any{
+		"title":         title,
+		"uid":           uid,
+		"tags":          []string{"hitron", "cable-modem"},
+		"timezone":      "browser",
+		"schemaVersion": 39,
+		"time":          map[string]string{"from": "now-24h", "to": "now"},
+		"refresh":       "1m",
+		"templating":    map[string]any{"list": variables},
+		"panels":        panels,
+	}
+}
+
+func writeDashboard(w io.Writer, cfg *Config, title string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(generateDashboard(cfg, title)); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// registerDashboardHandler serves the dashboard for the active configuration
+// at /grafana/dashboard.json, ready to import into Grafana.
+func registerDashboardHandler(mux *http.ServeMux, config func() *Config) {
+	mux.HandleFunc("GET /grafana/dashboard.json", func(w http.ResponseWriter, r *http.Request) {
+		title := r.URL.Query().Get("title")
+		if title == "" {
+			title = defaultDashboardTitle
+		}
+		w.Header().Set("Content-Type", "application/json")
+		writeDashboard(w, config(), title)
+	})
+}
+
+const defaultDashboardTitle = "Hitron Cable Modem"
+
+// runGenDashboardCommand writes the dashboard for the configuration given on
+// the command line, for provisioning Grafana from files.
+func runGenDashboardCommand(cfg *Config, args []string) error {
+	fs := flag.NewFlagSet("gen-dashboard", flag.ContinueOnError)
+	output := fs.String("output", "", "File to write (default: stdout)")
+	title := fs.String("title", defaultDashboardTitle, "Dashboard title")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *output == "" {
+		return writeDashboard(os.Stdout, cfg, *title)
+	}
+	if err := writeFileAtomic(*output, func(w io.Writer) error { return writeDashboard(w, cfg, *title) }); err != nil {
+		return fmt.Errorf("failed to write dashboard: %w", err)
+	}
+	return nil
+}
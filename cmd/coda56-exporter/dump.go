@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// runDumpCommand prints the raw JSON the modem returns, for attaching to
+// parser bug reports. A single endpoint is printed as-is; several are
+// combined into one object keyed by endpoint, the same shape that
+// /api/v1/ingest accepts.
+func runDumpCommand(client *hitron.ModemClient, args []string) error {
+	fs := flag.NewFlagSet("dump", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Request logging would get mixed up with the output
+	defer silenceLogs()()
+
+	if fs.NArg() == 1 {
+		data, err := client.Get(context.Background(), fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		return printJSON(data)
+	}
+
+	client.DetectModel(context.Background())
+	endpoints := client.Endpoints()
+	if fs.NArg() > 0 {
+		endpoints = fs.Args()
+	}
+	pages := map[string]json.RawMessage{}
+	for _, endpoint := range endpoints {
+		data, err := client.Get(context.Background(), endpoint)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", endpoint, err)
+			continue
+		}
+		if !json.Valid(data) {
+			// Keep malformed pages, which are what parser bugs are about
+			data, _ = json.Marshal(string(data))
+		}
+		pages[endpoint] = data
+	}
+	if len(pages) == 0 {
+		return fmt.Errorf("failed to fetch any modem endpoint")
+	}
+	data, err := json.Marshal(pages)
+	if err != nil {
+		return err
+	}
+	return printJSON(data)
+}
+
+// printJSON pretty-prints data to stdout, or prints it unchanged if it isn't
+// valid JSON.
+func printJSON(data []byte) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		buf.Reset()
+		buf.Write(data)
+	}
+	buf.WriteByte('\n')
+	_, err := os.Stdout.Write(buf.Bytes())
+	return err
+}
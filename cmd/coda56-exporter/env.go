@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// envPrefix starts the environment variable of every flag.
+const envPrefix = "CODA56_"
+
+// envName returns the environment variable for a flag, such as
+// CODA56_MODEM_HOST for -modem-host and CODA56_LOG_LEVEL for -log.level.
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(flagName))
+}
+
+// applyEnv sets the flags not given on the command line from their
+// environment variables, and records them in setFlags so that they also take
+// precedence over the config file. A repeatable flag such as -metrics.label
+// takes a comma-separated list.
+func applyEnv(fs *flag.FlagSet, setFlags map[string]bool) error {
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || setFlags[f.Name] {
+			return
+		}
+		name := envName(f.Name)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		values := []string{value}
+		if _, repeatable := f.Value.(labelFlag); repeatable {
+			values = splitList(value)
+		}
+		for _, v := range values {
+			if e := fs.Set(f.Name, v); e != nil {
+				err = fmt.Errorf("invalid value %q for %s: %w", v, name, e)
+				return
+			}
+		}
+		setFlags[f.Name] = true
+	})
+	return err
+}
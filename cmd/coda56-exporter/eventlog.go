@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// eventLogCollector counts DOCSIS event log entries by type. The modem only
+// keeps the most recent entries, so each poll is compared with the previous
+// one and only entries that weren't there before are counted.
+type eventLogCollector struct {
+	endpoint *optionalEndpoint
+
+	mu sync.Mutex
+	// seen holds the number of occurrences of each entry in the last
+	// successful poll; it is nil until then.
+	seen         map[string]int
+	counts       map[hitron.EventType]float64
+	lastCritical time.Time
+
+	events           *prometheus.Desc
+	lastCriticalDesc *prometheus.Desc
+}
+
+func newEventLogCollector(endpoint string) *eventLogCollector {
+	return &eventLogCollector{
+		endpoint: &optionalEndpoint{name: endpoint},
+		counts:   map[hitron.EventType]float64{},
+		events: prometheus.NewDesc(
+			"event_log_events_total",
+			"Entries seen in the modem's DOCSIS event log by event type",
+			[]string{"type"}, nil,
+		),
+		lastCriticalDesc: prometheus.NewDesc(
+			"event_log_last_critical_timestamp_seconds",
+			"Time of the most recent event log entry with critical or higher priority",
+			nil, nil,
+		),
+	}
+}
+
+func (c *eventLogCollector) update(ctx context.Context, client *hitron.ModemClient) {
+	if !c.endpoint.enabled() {
+		return
+	}
+	entries, err := client.GetEventLog(ctx, c.endpoint.name)
+	if err != nil {
+		slog.Warn("Failed to get event log", "err", err)
+		c.endpoint.check(err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Entries are identified by time and message; the index shifts as old
+	// entries drop off the end of the log.
+	seen := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		key := entry.Time + "\xff" + entry.Event
+		seen[key]++
+		if seen[key] <= c.seen[key] {
+			continue
+		}
+
+		c.counts[hitron.ClassifyEvent(entry.Event)]++
+		if entry.IsCritical() {
+			t, err := hitron.ParseEventTime(entry.Time, time.Local)
+			if err != nil {
+				slog.Debug("Failed to parse event log time", "err", err)
+			} else if t.After(c.lastCritical) {
+				c.lastCritical = t
+			}
+		}
+	}
+	c.seen = seen
+}
+
+func (c *eventLogCollector) describe(ch chan<- *prometheus.Desc) {
+	ch <- c.events
+	ch <- c.lastCriticalDesc
+}
+
+func (c *eventLogCollector) collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.seen == nil {
+		return
+	}
+	for _, t := range hitron.EventTypes {
+		ch <- prometheus.MustNewConstMetric(c.events, prometheus.CounterValue, c.counts[t], string(t))
+	}
+	if !c.lastCritical.IsZero() {
+		ch <- prometheus.MustNewConstMetric(c.lastCriticalDesc, prometheus.GaugeValue, float64(c.lastCritical.Unix()))
+	}
+}
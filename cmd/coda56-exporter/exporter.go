@@ -0,0 +1,654 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// loadSettings reads the config file, if any, and overlays the command-line
+// flags: flags set explicitly always win, and flag defaults fill in anything
+// the file leaves unset.
+func loadSettings(path string, setFlags map[string]bool) (*Config, error) {
+	cfg := &Config{}
+	if path != "" {
+		var err error
+		if cfg, err = LoadConfig(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if setFlags["modem-host"] || cfg.ModemHost == "" {
+		cfg.ModemHost = *modemHost
+	}
+	if setFlags["modem-resolve"] || cfg.ModemResolve == "" {
+		cfg.ModemResolve = *modemResolve
+	}
+	if setFlags["timeout"] || cfg.Timeout == 0 {
+		cfg.Timeout = *timeout
+	}
+	if setFlags["interval"] || cfg.Interval == nil {
+		cfg.Interval = interval
+	}
+	if setFlags["interval-jitter"] || cfg.IntervalJitter == 0 {
+		cfg.IntervalJitter = *intervalJitter
+	}
+	if setFlags["collect-mode"] || cfg.CollectMode == "" {
+		cfg.CollectMode = *collectMode
+	}
+	// Without a mode, an interval of 0 has always meant polling on scrape.
+	if cfg.CollectMode == "" {
+		cfg.CollectMode = collectBackground
+		if *cfg.Interval == 0 {
+			cfg.CollectMode = collectPull
+		}
+	}
+	if setFlags["modem-min-poll-interval"] || cfg.MinPollInterval == 0 {
+		cfg.MinPollInterval = *minPollInterval
+	}
+	if setFlags["cache.ttl"] || cfg.CacheTTL == 0 {
+		cfg.CacheTTL = *cacheTTL
+	}
+	if setFlags["modem-retries"] || cfg.Retry.Retries == nil {
+		cfg.Retry.Retries = modemRetries
+	}
+	if setFlags["modem-retry-delay"] || cfg.Retry.Delay == 0 {
+		cfg.Retry.Delay = *modemRetryDelay
+	}
+	if setFlags["modem-retry-jitter"] || cfg.Retry.Jitter == nil {
+		cfg.Retry.Jitter = modemRetryJitter
+	}
+	if setFlags["modem-breaker-threshold"] || cfg.Breaker.Threshold == nil {
+		cfg.Breaker.Threshold = modemBreakerThreshold
+	}
+	if setFlags["modem-breaker-probe-interval"] || cfg.Breaker.ProbeInterval == 0 {
+		cfg.Breaker.ProbeInterval = *modemBreakerInterval
+	}
+	if setFlags["modem-keepalive"] || cfg.Connection.KeepAlive == nil {
+		cfg.Connection.KeepAlive = modemKeepAlive
+	}
+	if setFlags["modem-max-idle-conns"] || cfg.Connection.MaxIdleConns == 0 {
+		cfg.Connection.MaxIdleConns = *modemMaxIdleConns
+	}
+	if setFlags["modem-idle-conn-timeout"] || cfg.Connection.IdleTimeout == 0 {
+		cfg.Connection.IdleTimeout = *modemIdleTimeout
+	}
+	if setFlags["modem-model"] || cfg.ModemModel == "" {
+		cfg.ModemModel = *modemModel
+	}
+	if setFlags["redact-identifiers"] || !cfg.RedactIdentifiers {
+		cfg.RedactIdentifiers = *redactIdentifiers
+	}
+	if setFlags["snmp.address"] || cfg.SNMP.Address == "" {
+		cfg.SNMP.Address = *snmpAddress
+	}
+	if setFlags["snmp.community"] || cfg.SNMP.Community == "" {
+		cfg.SNMP.Community = *snmpCommunity
+	}
+	if setFlags["snmp.mode"] || cfg.SNMP.Mode == "" {
+		cfg.SNMP.Mode = *snmpMode
+	}
+	if setFlags["modem-username"] || cfg.ModemUsername == "" {
+		cfg.ModemUsername = *modemUsername
+	}
+	if setFlags["modem-password"] || cfg.ModemPassword == "" {
+		cfg.ModemPassword = *modemPassword
+	}
+	if setFlags["modem-password-file"] || cfg.ModemPasswordFile == "" {
+		cfg.ModemPasswordFile = *modemPasswordFile
+	}
+	// Each may come from a flag, the environment or the config file, so
+	// rather than have one silently win over the other, setting both is an
+	// error.
+	if cfg.ModemPassword != "" && cfg.ModemPasswordFile != "" {
+		return nil, errors.New("both a modem password and a modem password file are set; use only one")
+	}
+	if cfg.ModemPasswordFile != "" {
+		data, err := os.ReadFile(cfg.ModemPasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read modem password file: %w", err)
+		}
+		cfg.ModemPassword = strings.TrimSpace(string(data))
+	}
+	for i, m := range cfg.Modems {
+		if m.PasswordFile == "" {
+			continue
+		}
+		data, err := os.ReadFile(m.PasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read password file of modem %q: %w", m.Name, err)
+		}
+		cfg.Modems[i].Password = strings.TrimSpace(string(data))
+	}
+	if setFlags["modem-ca-file"] || cfg.TLS.CAFile == "" {
+		cfg.TLS.CAFile = *modemCAFile
+	}
+	if setFlags["modem-cert-fingerprint"] || cfg.TLS.CertFingerprint == "" {
+		cfg.TLS.CertFingerprint = *modemFingerprint
+	}
+	if setFlags["modem-insecure-skip-verify"] || cfg.TLS.InsecureSkipVerify == nil {
+		cfg.TLS.InsecureSkipVerify = modemInsecure
+	}
+	tlsOpts := hitron.TLSOptions{CAFile: cfg.TLS.CAFile, Fingerprint: cfg.TLS.CertFingerprint}
+	// A CA or pin is a request to verify, so it takes precedence.
+	tlsOpts.Insecure = *cfg.TLS.InsecureSkipVerify && tlsOpts.CAFile == "" && tlsOpts.Fingerprint == ""
+	var err error
+	if cfg.tlsConfig, err = tlsOpts.Config(); err != nil {
+		return nil, fmt.Errorf("invalid modem TLS settings: %w", err)
+	}
+	if setFlags["modem-fallbacks"] || cfg.ModemFallbacks == nil {
+		cfg.ModemFallbacks = splitList(*modemFallbacks)
+	}
+	fallbacks := []string{}
+	for _, f := range cfg.ModemFallbacks {
+		if _, err := hitron.FallbackURL("https://modem", f); err != nil {
+			return nil, fmt.Errorf("modem_fallbacks: %w", err)
+		}
+		// Falling back to plain HTTP would get around the verification.
+		if scheme, _, _ := strings.Cut(strings.TrimSpace(f), ":"); scheme == "http" && !tlsOpts.Insecure {
+			slog.Warn("Ignoring plain HTTP modem fallback, since the modem's certificate is verified", "fallback", f)
+			continue
+		}
+		fallbacks = append(fallbacks, f)
+	}
+	cfg.ModemFallbacks = fallbacks
+	if setFlags["modem-proxy-url"] || cfg.ModemProxyURL == "" {
+		cfg.ModemProxyURL = *modemProxyURL
+	}
+	if cfg.ModemProxyURL != "" {
+		if cfg.proxyURL, err = hitron.ParseProxyURL(cfg.ModemProxyURL); err != nil {
+			return nil, fmt.Errorf("modem_proxy_url: %w", err)
+		}
+	}
+
+	if setFlags["combined-endpoint"] || cfg.Collectors.CombinedEndpoint == nil {
+		cfg.Collectors.CombinedEndpoint = combinedEndpoint
+	}
+	if setFlags["upstream-service-flow-endpoint"] || cfg.Collectors.UpstreamServiceFlowEndpoint == "" {
+		cfg.Collectors.UpstreamServiceFlowEndpoint = *usServiceFlowEndpoint
+	}
+	if setFlags["modem-rtt-probe"] || cfg.Collectors.RTTProbe == "" {
+		cfg.Collectors.RTTProbe = *modemRTTProbe
+	}
+	if setFlags["modem-concurrency"] || cfg.Collectors.Concurrency == 0 {
+		cfg.Collectors.Concurrency = *modemConcurrency
+	}
+	if setFlags["downstream-service-flow-endpoint"] || cfg.Collectors.DownstreamServiceFlowEndpoint == "" {
+		cfg.Collectors.DownstreamServiceFlowEndpoint = *dsServiceFlowEndpoint
+	}
+	if setFlags["event-log-endpoint"] || cfg.Collectors.EventLogEndpoint == nil {
+		cfg.Collectors.EventLogEndpoint = eventLogEndpoint
+	}
+	if setFlags["cm-init-endpoint"] || cfg.Collectors.CMInitEndpoint == nil {
+		cfg.Collectors.CMInitEndpoint = cmInitEndpoint
+	}
+	if setFlags["docsis-wan-endpoint"] || cfg.Collectors.DocsisWANEndpoint == nil {
+		cfg.Collectors.DocsisWANEndpoint = docsisWANEndpoint
+	}
+	if setFlags["auto-reboot.uncorrectables-per-minute"] || cfg.AutoReboot.UncorrectablesPerMinute == 0 {
+		cfg.AutoReboot.UncorrectablesPerMinute = *autoRebootUncorrectables
+	}
+	if setFlags["auto-reboot.ofdm-lock-loss"] || !cfg.AutoReboot.OFDMLockLoss {
+		cfg.AutoReboot.OFDMLockLoss = *autoRebootOFDMLockLoss
+	}
+	if setFlags["auto-reboot.polls"] || cfg.AutoReboot.Polls == 0 {
+		cfg.AutoReboot.Polls = *autoRebootPolls
+	}
+	if setFlags["auto-reboot.cooldown"] || cfg.AutoReboot.Cooldown == 0 {
+		cfg.AutoReboot.Cooldown = *autoRebootCooldown
+	}
+	if setFlags["watchdog.polls"] || cfg.Watchdog.Polls == 0 {
+		cfg.Watchdog.Polls = *watchdogPolls
+	}
+	if setFlags["watchdog.reset-connections"] || cfg.Watchdog.ResetConnections == nil {
+		cfg.Watchdog.ResetConnections = watchdogResetConnections
+	}
+	if setFlags["spec.downstream-power-min"] || cfg.Spec.DownstreamPowerMin == nil {
+		cfg.Spec.DownstreamPowerMin = specDownstreamPowerMin
+	}
+	if setFlags["spec.downstream-power-max"] || cfg.Spec.DownstreamPowerMax == nil {
+		cfg.Spec.DownstreamPowerMax = specDownstreamPowerMax
+	}
+	if setFlags["spec.snr-min"] || cfg.Spec.SNRMin == nil {
+		cfg.Spec.SNRMin = specSNRMin
+	}
+	if setFlags["spec.upstream-power-min"] || cfg.Spec.UpstreamPowerMin == nil {
+		cfg.Spec.UpstreamPowerMin = specUpstreamPowerMin
+	}
+	if setFlags["spec.upstream-power-max"] || cfg.Spec.UpstreamPowerMax == nil {
+		cfg.Spec.UpstreamPowerMax = specUpstreamPowerMax
+	}
+	if setFlags["strict"] || !cfg.Collectors.Strict {
+		cfg.Collectors.Strict = *strict
+	}
+	if setFlags["metrics.compact-labels"] || !cfg.Collectors.CompactLabels {
+		cfg.Collectors.CompactLabels = *compactLabels
+	}
+	if setFlags["metrics.namespace"] || cfg.Namespace == "" {
+		cfg.Namespace = *metricsNamespace
+	}
+	if len(metricsLabels) > 0 {
+		labels := map[string]string{}
+		for k, v := range cfg.Labels {
+			labels[k] = v
+		}
+		for k, v := range metricsLabels {
+			labels[k] = v
+		}
+		cfg.Labels = labels
+	}
+	// Flags can clash with modem labels from the file, so check the result
+	// as a whole.
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) collectorOptions() CollectorOptions {
+	return CollectorOptions{
+		UpstreamServiceFlowEndpoint:   c.Collectors.UpstreamServiceFlowEndpoint,
+		DownstreamServiceFlowEndpoint: c.Collectors.DownstreamServiceFlowEndpoint,
+		EventLogEndpoint:              *c.Collectors.EventLogEndpoint,
+		CMInitEndpoint:                *c.Collectors.CMInitEndpoint,
+		DocsisWANEndpoint:             *c.Collectors.DocsisWANEndpoint,
+		CompactLabels:                 c.Collectors.CompactLabels,
+		Concurrency:                   c.Collectors.Concurrency,
+		RTTProbe:                      c.Collectors.RTTProbe,
+		Strict:                        c.Collectors.Strict,
+		MinPollInterval:               c.MinPollInterval,
+		CacheTTL:                      c.CacheTTL,
+		// Polling on scrape already gives the samples the poll time.
+		SampleTimestamps: *webSampleTimestamps && c.background(),
+		RebootPolicy: RebootPolicy{
+			UncorrectablesPerMinute: c.AutoReboot.UncorrectablesPerMinute,
+			OFDMLockLoss:            c.AutoReboot.OFDMLockLoss,
+			Polls:                   c.AutoReboot.Polls,
+			Cooldown:                c.AutoReboot.Cooldown,
+		},
+		Watchdog: WatchdogPolicy{
+			Polls:            c.Watchdog.Polls,
+			ResetConnections: *c.Watchdog.ResetConnections,
+		},
+		Spec: SpecRanges{
+			DownstreamPowerMin: *c.Spec.DownstreamPowerMin,
+			DownstreamPowerMax: *c.Spec.DownstreamPowerMax,
+			DownstreamSNRMin:   *c.Spec.SNRMin,
+			UpstreamPowerMin:   *c.Spec.UpstreamPowerMin,
+			UpstreamPowerMax:   *c.Spec.UpstreamPowerMax,
+		},
+	}
+}
+
+// newClient returns a client for host with the global settings. Options in
+// opts are applied last, so they can override them.
+func (c *Config) newClient(host string, opts ...hitron.Option) *hitron.ModemClient {
+	defaults := []hitron.Option{
+		hitron.WithCombinedEndpoint(*c.Collectors.CombinedEndpoint),
+		hitron.WithTLSConfig(c.tlsConfig),
+		hitron.WithRetryPolicy(hitron.RetryPolicy{
+			Retries: *c.Retry.Retries,
+			Delay:   c.Retry.Delay,
+			Jitter:  *c.Retry.Jitter,
+		}),
+		hitron.WithCircuitBreaker(*c.Breaker.Threshold, c.Breaker.ProbeInterval),
+		hitron.WithConnectionOptions(hitron.ConnectionOptions{
+			DisableKeepAlive: !*c.Connection.KeepAlive,
+			MaxIdleConns:     c.Connection.MaxIdleConns,
+			IdleTimeout:      c.Connection.IdleTimeout,
+		}),
+	}
+	if c.proxyURL != nil {
+		defaults = append(defaults, hitron.WithProxy(c.proxyURL))
+	}
+	if len(c.ModemFallbacks) > 0 {
+		defaults = append(defaults, hitron.WithAddressFallbacks(c.ModemFallbacks...))
+	}
+	if c.ModemUsername != "" {
+		defaults = append(defaults, hitron.WithCredentials(c.ModemUsername, c.ModemPassword))
+	}
+	if c.RedactIdentifiers {
+		defaults = append(defaults, hitron.WithRedactedIdentifiers())
+	}
+	defaults = append(defaults, modelOptions(c.ModemModel)...)
+	return hitron.NewModemClient(host, c.Timeout, append(defaults, opts...)...)
+}
+
+// modemOptions returns the options overriding the global settings for m.
+func (c *Config) modemOptions(m ModemConfig) []hitron.Option {
+	var opts []hitron.Option
+	if m.Username != "" {
+		opts = append(opts, hitron.WithCredentials(m.Username, m.Password))
+	}
+	if m.Resolve != "" {
+		opts = append(opts, hitron.WithResolvedAddress(m.Resolve))
+	}
+	opts = append(opts, modelOptions(m.Model)...)
+	return append(opts, c.snmpOptions(m.SNMP)...)
+}
+
+// hostOptions returns the options for the modem given by modem_host, the
+// counterpart of modemOptions.
+func (c *Config) hostOptions() []hitron.Option {
+	var opts []hitron.Option
+	if c.ModemResolve != "" {
+		opts = append(opts, hitron.WithResolvedAddress(c.ModemResolve))
+	}
+	return append(opts, c.snmpOptions(c.SNMP)...)
+}
+
+// snmpOptions returns the options for reading the SNMP agent in s, if any,
+// with the global community and mode filling in what s leaves out. They
+// come after any model option, since SNMP alone only serves some pages.
+func (c *Config) snmpOptions(s SNMPConfig) []hitron.Option {
+	if s.Address == "" {
+		return nil
+	}
+	if s.Community == "" {
+		s.Community = c.SNMP.Community
+	}
+	if s.Mode == "" {
+		s.Mode = c.SNMP.Mode
+	}
+	src := &hitron.SNMPSource{Address: s.Address, Community: s.Community, Timeout: c.Timeout}
+	if s.Mode == snmpOnly {
+		return []hitron.Option{hitron.WithDataSource(src), hitron.WithAdapter(hitron.SNMPAdapter)}
+	}
+	return []hitron.Option{hitron.WithFallbackSource(src)}
+}
+
+// autoModel is the -modem-model value that detects the model.
+const autoModel = "auto"
+
+func validateModel(model string) error {
+	if model == "" || model == autoModel {
+		return nil
+	}
+	_, err := hitron.AdapterFor(model)
+	return err
+}
+
+// modelOptions returns the client options selecting model, which has been
+// checked by validateModel. An empty model keeps the client's default.
+func modelOptions(model string) []hitron.Option {
+	if model == autoModel {
+		return []hitron.Option{hitron.WithAutoDetect()}
+	}
+	if a, err := hitron.AdapterFor(model); err == nil {
+		return []hitron.Option{hitron.WithAdapter(a)}
+	}
+	return nil
+}
+
+// reloadableGatherer serves metrics from a registry that is replaced
+// wholesale when the configuration is reloaded.
+type reloadableGatherer struct {
+	mu sync.RWMutex
+	g  prometheus.Gatherer
+	// onDemand, when the modems are polled on scrape, builds a gatherer
+	// that polls them with the context of a scrape.
+	onDemand func(ctx context.Context) prometheus.Gatherer
+}
+
+func (r *reloadableGatherer) Gather() ([]*dto.MetricFamily, error) {
+	r.mu.RLock()
+	g := r.g
+	r.mu.RUnlock()
+	if g == nil {
+		return nil, nil
+	}
+	return g.Gather()
+}
+
+func (r *reloadableGatherer) set(g prometheus.Gatherer, onDemand func(ctx context.Context) prometheus.Gatherer) {
+	r.mu.Lock()
+	r.g = g
+	r.onDemand = onDemand
+	r.mu.Unlock()
+}
+
+// forScrape returns the gatherer for a scrape whose polls use ctx.
+func (r *reloadableGatherer) forScrape(ctx context.Context) prometheus.Gatherer {
+	r.mu.RLock()
+	onDemand := r.onDemand
+	r.mu.RUnlock()
+	if onDemand == nil {
+		return r
+	}
+	return onDemand(ctx)
+}
+
+// exporter holds the state that is rebuilt when the configuration changes.
+// The HTTP listener and everything configured only by flags stay as they are.
+type exporter struct {
+	configFile string
+	setFlags   map[string]bool
+	elector    Elector
+	ingest     *IngestSource
+	auth       *APIAuth
+	probe      *probeHandler
+	sinks      []sink
+	counters   *counterStore
+
+	modems    reloadableGatherer
+	config    atomic.Pointer[Config]
+	primary   atomic.Pointer[hitron.ModemClient]
+	named     atomic.Pointer[map[string]*hitron.ModemClient]
+	collected atomic.Pointer[MetricsCollector]
+	// collectors are those of all modems, and wasReady is set once they
+	// have all been polled successfully; see ready.
+	collectors atomic.Pointer[[]*MetricsCollector]
+	wasReady   atomic.Bool
+
+	// stopPolling cancels the background pollers of the active configuration.
+	stopPolling context.CancelFunc
+}
+
+// client returns the modem used by the snapshot API: the only modem, or the
+// first one when several are configured.
+func (e *exporter) client() *hitron.ModemClient {
+	return e.primary.Load()
+}
+
+// collectorNamed returns the collector of the modem named in the config
+// file, or nil.
+func (e *exporter) collectorNamed(name string) *MetricsCollector {
+	collectors := e.collectors.Load()
+	if collectors == nil {
+		return nil
+	}
+	for _, c := range *collectors {
+		if c.tags["modem"] == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// clientNamed returns the modem named in the config file, or the one
+// returned by client for an empty name.
+func (e *exporter) clientNamed(name string) *hitron.ModemClient {
+	if name == "" {
+		return e.client()
+	}
+	return (*e.named.Load())[name]
+}
+
+// collector returns the collector of the modem returned by client.
+func (e *exporter) collector() *MetricsCollector {
+	return e.collected.Load()
+}
+
+// newCollectors creates a collector for each modem in cfg and registers them
+// in reg.
+func (e *exporter) newCollectors(cfg *Config, reg prometheus.Registerer) ([]*MetricsCollector, error) {
+	opts := cfg.collectorOptions()
+	opts.Sinks = e.sinks
+	// Recorded and pushed data has no modem to measure.
+	if e.ingest != nil || *replayDir != "" {
+		opts.RTTProbe = ""
+	}
+	// Replayed counters are from another time than the live ones.
+	counters := e.counters
+	if *replayDir != "" {
+		counters = nil
+	}
+	var collectors []*MetricsCollector
+	if len(cfg.Modems) > 0 && e.ingest == nil {
+		for i, labels := range cfg.ModemLabels() {
+			clientOpts := cfg.modemOptions(cfg.Modems[i])
+			capture, err := captureOptions(cfg, cfg.Modems[i].Name)
+			if err != nil {
+				return nil, err
+			}
+			clientOpts = append(clientOpts, capture...)
+			var observe hitron.Option
+			opts.RequestMetrics, observe = newRequestMetrics()
+			clientOpts = append(clientOpts, observe)
+			client := cfg.newClient(cfg.Modems[i].Host, clientOpts...)
+			collector := NewMetricsCollector(client, e.elector, opts)
+			collector.tags = labels
+			collector.useCounterStore(counters)
+			collectors = append(collectors, collector)
+			if err := cfg.registerer(reg, labels).Register(collector); err != nil {
+				return nil, fmt.Errorf("failed to register modem %q: %w", cfg.Modems[i].Name, err)
+			}
+		}
+		return collectors, nil
+	}
+
+	clientOpts := cfg.hostOptions()
+	capture, err := captureOptions(cfg, "")
+	if err != nil {
+		return nil, err
+	}
+	clientOpts = append(clientOpts, capture...)
+	if e.ingest != nil {
+		clientOpts = append(clientOpts, hitron.WithDataSource(e.ingest))
+	}
+	var observe hitron.Option
+	opts.RequestMetrics, observe = newRequestMetrics()
+	clientOpts = append(clientOpts, observe)
+	collector := NewMetricsCollector(cfg.newClient(cfg.ModemHost, clientOpts...), e.elector, opts)
+	collector.tags = cfg.Labels
+	collector.useCounterStore(counters)
+	if err := cfg.registerer(reg, cfg.Labels).Register(collector); err != nil {
+		return nil, fmt.Errorf("failed to register modem: %w", err)
+	}
+	return append(collectors, collector), nil
+}
+
+// apply builds collectors for cfg and swaps them in. On error the previous
+// configuration stays active.
+func (e *exporter) apply(cfg *Config) error {
+	tokens := []apiToken{}
+	if *apiTokensFile != "" {
+		fileTokens, err := LoadAPITokens(*apiTokensFile)
+		if err != nil {
+			return err
+		}
+		tokens = append(tokens, fileTokens...)
+	}
+	for _, t := range cfg.APITokens {
+		tok, err := newAPIToken(t.Name, t.Token, t.Scopes)
+		if err != nil {
+			return err
+		}
+		tokens = append(tokens, tok)
+	}
+
+	reg := prometheus.NewRegistry()
+	collectors, err := e.newCollectors(cfg, reg)
+	if err != nil {
+		return err
+	}
+
+	if e.stopPolling != nil {
+		e.stopPolling()
+		e.stopPolling = nil
+	}
+	if cfg.background() {
+		ctx, cancel := context.WithCancel(context.Background())
+		for _, c := range collectors {
+			go c.Run(ctx, *cfg.Interval, cfg.IntervalJitter)
+		}
+		e.stopPolling = cancel
+	}
+
+	e.auth.SetTokens(tokens)
+	e.probe.configure(cfg)
+	e.config.Store(cfg)
+	e.primary.Store(collectors[0].client)
+	named := map[string]*hitron.ModemClient{}
+	for _, c := range collectors {
+		if name := c.tags["modem"]; name != "" {
+			named[name] = c.client
+		}
+	}
+	e.named.Store(&named)
+	e.collected.Store(collectors[0])
+	e.collectors.Store(&collectors)
+	// Registering anew per scrape is cheap next to polling the modem.
+	var onDemand func(ctx context.Context) prometheus.Gatherer
+	if !cfg.background() {
+		onDemand = func(ctx context.Context) prometheus.Gatherer {
+			reg := prometheus.NewRegistry()
+			for _, c := range collectors {
+				cfg.registerer(reg, c.tags).MustRegister(scrapeCollector{c, ctx})
+			}
+			return reg
+		}
+	}
+	e.modems.set(reg, onDemand)
+
+	switch {
+	case e.ingest != nil:
+		slog.Info("Serving modem data pushed to /api/v1/ingest")
+	case *replayDir != "":
+		slog.Info("Replaying modem recordings", "dir", *replayDir)
+	case len(cfg.Modems) > 0:
+		for _, m := range cfg.Modems {
+			slog.Info("Polling modem", "modem", m.Name, "host", m.Host)
+		}
+	default:
+		slog.Info("Polling modem", "host", cfg.ModemHost)
+	}
+	if len(tokens) > 0 {
+		slog.Info("Loaded API tokens", "count", len(tokens))
+	}
+	return nil
+}
+
+func (e *exporter) reload() error {
+	cfg, err := loadSettings(e.configFile, e.setFlags)
+	if err != nil {
+		return err
+	}
+	return e.apply(cfg)
+}
+
+// watchSIGHUP reloads the configuration whenever the process receives SIGHUP.
+func (e *exporter) watchSIGHUP() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	for range ch {
+		slog.Info("Received SIGHUP, reloading configuration")
+		if err := e.reload(); err != nil {
+			slog.Error("Failed to reload configuration, keeping the previous one", "err", err)
+			continue
+		}
+		slog.Info("Configuration reloaded")
+	}
+}
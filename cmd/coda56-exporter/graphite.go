@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// identityTags are the point tags that name a channel. Graphite paths only
+// include these, so a channel keeps its path when its modulation changes.
+var identityTags = map[string]bool{
+	"channel_id": true,
+	"receive":    true,
+	"usch_index": true,
+}
+
+var pathSegmentRe = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// graphitePaths flattens the snapshot into dotted paths such as
+// hitron.home.downstream.17.snr_db, with the modem's static label values
+// (sorted by label name) after the prefix.
+func graphitePaths(prefix string, snap *hitron.Snapshot, tags map[string]string, emit func(path string, value float64, t time.Time)) {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var base []string
+	if prefix != "" {
+		base = append(base, prefix)
+	}
+	for _, k := range keys {
+		if v := pathSegmentRe.ReplaceAllString(tags[k], "_"); v != "" {
+			base = append(base, v)
+		}
+	}
+
+	for _, p := range snapshotPoints(snap) {
+		segments := append(append([]string{}, base...), p.measurement)
+		for _, t := range p.tags {
+			if identityTags[t.key] {
+				segments = append(segments, pathSegmentRe.ReplaceAllString(t.value, "_"))
+			}
+		}
+		path := strings.Join(segments, ".")
+		for _, f := range p.fields {
+			emit(path+"."+f.key, f.value, p.time)
+		}
+	}
+}
+
+func init() {
+	registerSink("graphite", sinkType{
+		fromFlags: func(cfg *Config) (sink, error) {
+			return lineSinkFromFlags(cfg, *graphiteAddress, newGraphiteSink), nil
+		},
+		fromConfig: func(cfg *Config, node *yaml.Node) (sink, error) {
+			return lineSinkFromConfig(cfg, node, newGraphiteSink)
+		},
+	})
+	registerSink("statsd", sinkType{
+		fromFlags: func(cfg *Config) (sink, error) {
+			return lineSinkFromFlags(cfg, *statsdAddress, newStatsDSink), nil
+		},
+		fromConfig: func(cfg *Config, node *yaml.Node) (sink, error) {
+			return lineSinkFromConfig(cfg, node, newStatsDSink)
+		},
+	})
+}
+
+// lineSettings configure a Graphite or StatsD output, as with the
+// -graphite.* and -statsd.* flags.
+type lineSettings struct {
+	Address       string        `yaml:"address"`
+	Prefix        string        `yaml:"prefix"`
+	FlushInterval time.Duration `yaml:"flush_interval"`
+}
+
+// defaultLineSettings are the flag settings, with the prefix defaulting to
+// the metrics namespace.
+func defaultLineSettings(cfg *Config) lineSettings {
+	s := lineSettings{Prefix: *graphitePrefix, FlushInterval: *graphiteFlush}
+	if s.Prefix == "" {
+		s.Prefix = cfg.Namespace
+	}
+	return s
+}
+
+func lineSinkFromFlags(cfg *Config, address string, newSink func(address, prefix string, timeout, flush time.Duration) *lineSink) sink {
+	if address == "" {
+		return nil
+	}
+	s := defaultLineSettings(cfg)
+	return newSink(address, s.Prefix, cfg.Timeout, s.FlushInterval)
+}
+
+func lineSinkFromConfig(cfg *Config, node *yaml.Node, newSink func(address, prefix string, timeout, flush time.Duration) *lineSink) (sink, error) {
+	s := defaultLineSettings(cfg)
+	if err := decodeSinkSettings(node, &s); err != nil {
+		return nil, err
+	}
+	if s.Address == "" {
+		return nil, fmt.Errorf("address is required")
+	}
+	return newSink(s.Address, s.Prefix, cfg.Timeout, s.FlushInterval), nil
+}
+
+// lineSink sends each poll as plaintext lines, either straight away or, with
+// a flush interval, by resending the latest data of every modem on a timer.
+type lineSink struct {
+	name    string
+	network string
+	address string
+	prefix  string
+	timeout time.Duration
+	// format renders one value; packet is true when every line goes in its
+	// own datagram.
+	format func(path string, value float64, t time.Time) string
+	packet bool
+
+	mu     sync.Mutex
+	latest map[string][]string
+}
+
+// newGraphiteSink writes the Graphite plaintext protocol over TCP.
+func newGraphiteSink(address, prefix string, timeout, flush time.Duration) *lineSink {
+	return startLineSink(&lineSink{
+		name:    "graphite",
+		network: "tcp",
+		address: address,
+		prefix:  prefix,
+		timeout: timeout,
+		format: func(path string, value float64, t time.Time) string {
+			return fmt.Sprintf("%s %s %d\n", path, strconv.FormatFloat(value, 'f', -1, 64), t.Unix())
+		},
+	}, flush)
+}
+
+// newStatsDSink sends StatsD gauges over UDP. StatsD has no timestamps, so
+// the values are reported as of when they arrive.
+func newStatsDSink(address, prefix string, timeout, flush time.Duration) *lineSink {
+	return startLineSink(&lineSink{
+		name:    "statsd",
+		network: "udp",
+		address: address,
+		prefix:  prefix,
+		timeout: timeout,
+		format: func(path string, value float64, _ time.Time) string {
+			return fmt.Sprintf("%s:%s|g\n", path, strconv.FormatFloat(value, 'f', -1, 64))
+		},
+		packet: true,
+	}, flush)
+}
+
+func startLineSink(s *lineSink, flush time.Duration) *lineSink {
+	if flush > 0 {
+		s.latest = map[string][]string{}
+		go func() {
+			for range time.Tick(flush) {
+				if err := s.flush(); err != nil {
+					slog.Warn("Failed to write metrics", "sink", s.name, "err", err)
+				}
+			}
+		}()
+	}
+	return s
+}
+
+func (s *lineSink) Name() string { return s.name }
+
+func (s *lineSink) Write(snap *hitron.Snapshot, tags map[string]string) error {
+	var lines []string
+	graphitePaths(s.prefix, snap, tags, func(path string, value float64, t time.Time) {
+		lines = append(lines, s.format(path, value, t))
+	})
+	if s.latest == nil {
+		return s.send(lines)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latest[fmt.Sprint(tags)] = lines
+	return nil
+}
+
+func (s *lineSink) flush() error {
+	s.mu.Lock()
+	var lines []string
+	for _, l := range s.latest {
+		lines = append(lines, l...)
+	}
+	s.mu.Unlock()
+	if len(lines) == 0 {
+		return nil
+	}
+	return s.send(lines)
+}
+
+func (s *lineSink) send(lines []string) error {
+	conn, err := net.DialTimeout(s.network, s.address, s.timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetWriteDeadline(time.Now().Add(s.timeout))
+
+	if s.packet {
+		for _, line := range lines {
+			if _, err := conn.Write([]byte(line)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.WriteString(line)
+	}
+	_, err = conn.Write(buf.Bytes())
+	return err
+}
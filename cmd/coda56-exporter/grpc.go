@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/anupcshan/coda56-exporter/pkg/collector"
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+	"github.com/anupcshan/coda56-exporter/pkg/modempb"
+)
+
+// grpcHub hands every poll to the Watch calls on its modem. It is a sink,
+// so watches carry on across configuration reloads.
+type grpcHub struct {
+	mu       sync.Mutex
+	watchers map[string]map[chan *hitron.Snapshot]bool
+}
+
+func newGRPCHub() *grpcHub {
+	return &grpcHub{watchers: map[string]map[chan *hitron.Snapshot]bool{}}
+}
+
+func (h *grpcHub) Name() string { return "grpc" }
+
+func (h *grpcHub) Write(snap *hitron.Snapshot, tags map[string]string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.watchers[tags["modem"]] {
+		// A watcher still sending the previous poll only gets the newest.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- snap
+	}
+	return nil
+}
+
+// subscribe returns a channel receiving the polls of the named modem, and
+// the function that stops them.
+func (h *grpcHub) subscribe(modem string) (<-chan *hitron.Snapshot, func()) {
+	ch := make(chan *hitron.Snapshot, 1)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.watchers[modem] == nil {
+		h.watchers[modem] = map[chan *hitron.Snapshot]bool{}
+	}
+	h.watchers[modem][ch] = true
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.watchers[modem], ch)
+	}
+}
+
+// grpcServer implements the ModemService of pkg/modempb.
+type grpcServer struct {
+	modempb.UnimplementedModemServiceServer
+	e   *exporter
+	hub *grpcHub
+}
+
+// collectorNamed returns the collector of the modem named in the config
+// file, or of the only or first modem for an empty name.
+func (s *grpcServer) collectorNamed(name string) (*MetricsCollector, error) {
+	if name == "" {
+		if c := s.e.collector(); c != nil {
+			return c, nil
+		}
+		return nil, status.Error(codes.Unavailable, "no modem is configured yet")
+	}
+	if collectors := s.e.collectors.Load(); collectors != nil {
+		for _, c := range *collectors {
+			if c.tags["modem"] == name {
+				return c, nil
+			}
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "unknown modem %q", name)
+}
+
+func (s *grpcServer) GetStatus(ctx context.Context, req *modempb.StatusRequest) (*modempb.Status, error) {
+	c, err := s.collectorNamed(req.Modem)
+	if err != nil {
+		return nil, err
+	}
+	snap := c.Status(ctx)
+	if snap == nil {
+		return nil, status.Error(codes.Unavailable, "the modem has not been polled yet")
+	}
+	return statusProto(snap, c.tags["modem"]), nil
+}
+
+func (s *grpcServer) Watch(req *modempb.StatusRequest, stream modempb.ModemService_WatchServer) error {
+	c, err := s.collectorNamed(req.Modem)
+	if err != nil {
+		return err
+	}
+	name := c.tags["modem"]
+	polls, stop := s.hub.subscribe(name)
+	defer stop()
+
+	if snap := c.snapshot(); snap != nil {
+		if err := stream.Send(statusProto(snap, name)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case snap := <-polls:
+			if err := stream.Send(statusProto(snap, name)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// authorize checks the bearer token in the call's metadata like
+// APIAuth.Require does for HTTP requests.
+func (a *APIAuth) authorize(ctx context.Context, scope string) error {
+	if !a.Enabled() {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	var secret string
+	for _, v := range md.Get("authorization") {
+		if s, ok := strings.CutPrefix(v, "Bearer "); ok {
+			secret = strings.TrimSpace(s)
+		}
+	}
+	if secret == "" {
+		return status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	tok := a.lookup(secret)
+	if tok == nil {
+		return status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+	if !tok.scopes[scope] {
+		return status.Errorf(codes.PermissionDenied, "token %q lacks the %q scope", tok.name, scope)
+	}
+	return nil
+}
+
+// startGRPC serves the ModemService on addr in the background. Calls need
+// a token with the read scope when API tokens are configured.
+func startGRPC(addr, certFile, keyFile string, e *exporter, hub *grpcHub) error {
+	var opts []grpc.ServerOption
+	if certFile != "" || keyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load gRPC TLS certificate: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	opts = append(opts,
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := e.auth.authorize(ctx, ScopeRead); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := e.auth.authorize(ss.Context(), ScopeRead); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	)
+
+	var l net.Listener
+	var err error
+	if path, ok := socketPath(addr); ok {
+		l, err = listenUnix(path)
+	} else {
+		l, err = net.Listen("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to listen for gRPC: %w", err)
+	}
+	server := grpc.NewServer(opts...)
+	modempb.RegisterModemServiceServer(server, &grpcServer{e: e, hub: hub})
+	reflection.Register(server)
+	slog.Info("Starting gRPC server", "address", addr)
+	go func() {
+		if err := server.Serve(l); err != nil {
+			fatal("gRPC server failed", "err", err)
+		}
+	}()
+	return nil
+}
+
+// statusProto converts the data of a poll to its protobuf message. The
+// values are parsed as for the metrics, without counting parse errors a
+// second time.
+func statusProto(snap *hitron.Snapshot, modem string) *modempb.Status {
+	s := &modempb.Status{
+		Modem:  modem,
+		Time:   timestamppb.New(snap.Time),
+		Errors: snap.Errors,
+	}
+	parser := func(endpoint string) collector.Parser { return collector.NewParser(endpoint, nil) }
+	ds := collector.ParseDownstream(parser(hitron.EndpointDownstream), snap.Downstream)
+	for i, ch := range snap.Downstream {
+		s.Downstream = append(s.Downstream, &modempb.DownstreamChannel{
+			PortId:         ch.PortID,
+			Frequency:      ch.Frequency,
+			Modulation:     ch.Modulation,
+			SignalStrength: ch.SignalStrength,
+			Snr:            ch.SNR,
+			Octets:         ch.DSoctets,
+			Correcteds:     ch.Correcteds,
+			Uncorrectables: ch.Uncorrect,
+			ChannelId:      ch.ChannelID,
+
+			PowerDbmv:           protoDouble(ds[i].Power),
+			SnrDb:               protoDouble(ds[i].SNR),
+			FrequencyHz:         protoCount(ds[i].FrequencyHz),
+			OctetsCount:         protoCount(ds[i].Octets),
+			CorrectedsCount:     protoCount(ds[i].Correcteds),
+			UncorrectablesCount: protoCount(ds[i].Uncorrectables),
+		})
+	}
+	us := collector.ParseUpstream(parser(hitron.EndpointUpstream), snap.Upstream)
+	for i, ch := range snap.Upstream {
+		u := &modempb.UpstreamChannel{
+			PortId:         ch.PortID,
+			Frequency:      ch.Frequency,
+			Bandwidth:      ch.Bandwidth,
+			Modulation:     ch.ModType,
+			ScdmaMode:      ch.ScdmaMode,
+			SignalStrength: ch.SignalStrength,
+			ChannelId:      ch.ChannelID,
+
+			PowerDbmv:   protoDouble(us[i].Power),
+			FrequencyHz: protoCount(us[i].FrequencyHz),
+			BandwidthHz: protoCount(us[i].Bandwidth),
+		}
+		if us[i].SCDMA.OK {
+			u.Scdma = proto.Bool(us[i].SCDMA.V == 1)
+		}
+		s.Upstream = append(s.Upstream, u)
+	}
+	ofdmDs := collector.ParseOFDMDownstream(parser(hitron.EndpointOFDMDownstream), snap.OFDMDownstream)
+	for i, ch := range snap.OFDMDownstream {
+		s.OfdmDownstream = append(s.OfdmDownstream, &modempb.OFDMDownstreamChannel{
+			Receive:              ch.Receive,
+			FftType:              ch.FFTType,
+			Subcarrier0Frequency: ch.Subcarr0freqFreq,
+			PlcLock:              ch.PLCLock,
+			NcpLock:              ch.NCPLock,
+			Mdc1Lock:             ch.MDC1Lock,
+			PlcPower:             ch.PLCPower,
+			Snr:                  ch.SNR,
+			Octets:               ch.DSoctets,
+			Correcteds:           ch.Correcteds,
+			Uncorrectables:       ch.Uncorrect,
+
+			PowerDbmv:              protoDouble(ofdmDs[i].Power),
+			SnrDb:                  protoDouble(ofdmDs[i].SNR),
+			Subcarrier0FrequencyHz: protoCount(ofdmDs[i].FrequencyHz),
+			OctetsCount:            protoCount(ofdmDs[i].Octets),
+			CorrectedsCount:        protoCount(ofdmDs[i].Correcteds),
+			UncorrectablesCount:    protoCount(ofdmDs[i].Uncorrectables),
+			PlcLocked:              ofdmDs[i].PLCLock,
+			NcpLocked:              ofdmDs[i].NCPLock,
+			Mdc1Locked:             ofdmDs[i].MDC1Lock,
+		})
+	}
+	ofdmUs := collector.ParseOFDMUpstream(parser(hitron.EndpointOFDMUpstream), snap.OFDMUpstream)
+	for i, ch := range snap.OFDMUpstream {
+		s.OfdmUpstream = append(s.OfdmUpstream, &modempb.OFDMUpstreamChannel{
+			ChannelIndex:              ch.USCHIndex,
+			State:                     ch.State,
+			Frequency:                 ch.Frequency,
+			DigitalAttenuation:        ch.DigAtten,
+			DigitalAttenuationBackoff: ch.DigAttenBo,
+			ChannelBandwidth:          ch.ChannelBw,
+			ReportedPower:             ch.RepPower,
+			ReportedPower_1_6:         ch.RepPower1_6,
+			FftSize:                   ch.FFTVal,
+
+			Operating:          ofdmUs[i].Operating,
+			FrequencyHz:        protoCount(ofdmUs[i].FrequencyHz),
+			PowerDbmv:          protoDouble(ofdmUs[i].Power),
+			ChannelBandwidthHz: protoCount(ofdmUs[i].Bandwidth),
+		})
+	}
+	if sys := snap.System; sys != nil {
+		s.System = &modempb.SystemInfo{
+			HwVersion:          sys.HWVersion,
+			SwVersion:          sys.SWVersion,
+			SerialNumber:       sys.SerialNumber,
+			RfMac:              sys.RFMac,
+			WanIp:              sys.WanIP,
+			SystemUptime:       sys.SystemUptime,
+			SystemTime:         sys.SystemTime,
+			Timezone:           sys.Timezone,
+			WanReceivedPackets: sys.WRecPkt,
+			WanSentPackets:     sys.WSendPkt,
+			LanIp:              sys.LanIP,
+			LanReceivedPackets: sys.LRecPkt,
+			LanSentPackets:     sys.LSendPkt,
+		}
+		if uptime, err := hitron.ParseUptime(sys.SystemUptime); err == nil {
+			s.System.Uptime = durationpb.New(uptime)
+		}
+		for _, t := range collector.ParseTraffic(parser(hitron.EndpointSystemInfo), sys) {
+			bytes := protoCount(t.Bytes)
+			switch t.Interface + " " + t.Direction {
+			case "wan received":
+				s.System.WanReceivedBytes = bytes
+			case "wan sent":
+				s.System.WanSentBytes = bytes
+			case "lan received":
+				s.System.LanReceivedBytes = bytes
+			case "lan sent":
+				s.System.LanSentBytes = bytes
+			}
+		}
+	}
+	if link := snap.Link; link != nil {
+		s.Link = &modempb.LinkStatus{
+			LinkStatus: link.LinkStatus,
+			LinkDuplex: link.LinkDuplex,
+			LinkSpeed:  link.LinkSpeed,
+			LinkUp:     collector.LinkUp(link),
+		}
+		if speed, err := hitron.ParseLinkSpeed(link.LinkSpeed); err == nil {
+			s.Link.LinkSpeedBps = protoCount(collector.Value{V: speed, OK: true})
+		}
+	}
+	return s
+}
+
+// protoDouble returns v for an optional field, or nil if it isn't set.
+func protoDouble(v collector.Value) *float64 {
+	if !v.OK {
+		return nil
+	}
+	return proto.Float64(v.V)
+}
+
+// protoCount returns v rounded for an optional unsigned field, or nil if it
+// isn't set.
+func protoCount(v collector.Value) *uint64 {
+	if !v.OK || v.V < 0 {
+		return nil
+	}
+	return proto.Uint64(uint64(math.Round(v.V)))
+}
@@ -0,0 +1,454 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Elector decides which of several redundant exporter instances polls the
+// modem. Polling the CODA56 from two hosts at once destabilizes it, so only
+// the leader talks to the modem while standbys serve cached or proxied data.
+type Elector interface {
+	// Run maintains leadership state until ctx is cancelled.
+	Run(ctx context.Context)
+	// IsLeader reports whether this instance currently holds leadership.
+	IsLeader() bool
+	// LeaderURL returns the advertised URL of the current leader, if known.
+	LeaderURL() string
+}
+
+// HAConfig holds settings shared by all election modes.
+type HAConfig struct {
+	ID            string
+	AdvertiseURL  string
+	LeaseDuration time.Duration
+
+	LeaseFile string
+
+	Peers    []string
+	Priority int
+
+	KubeLease     string
+	KubeNamespace string
+}
+
+// resolveID fills in the hostname as the instance's identity when none is
+// set. It must happen before the config is shared, since the elector and
+// /-/ha have to agree on it.
+func (cfg *HAConfig) resolveID() error {
+	if cfg.ID != "" {
+		return nil
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("failed to determine HA identity: %w", err)
+	}
+	cfg.ID = host
+	return nil
+}
+
+func NewElector(mode string, cfg HAConfig) (Elector, error) {
+	if err := cfg.resolveID(); err != nil {
+		return nil, err
+	}
+
+	switch mode {
+	case "", "none":
+		return nil, nil
+	case "file":
+		if cfg.LeaseFile == "" {
+			return nil, fmt.Errorf("-ha-lease-file is required for file mode")
+		}
+		return &fileElector{cfg: cfg}, nil
+	case "static":
+		return &staticElector{cfg: cfg, client: &http.Client{Timeout: cfg.LeaseDuration / 3}}, nil
+	case "kubernetes":
+		e, err := newKubeElector(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return e, nil
+	default:
+		return nil, fmt.Errorf("unknown HA mode %q", mode)
+	}
+}
+
+// leaderState is embedded by electors to track and log leadership changes.
+type leaderState struct {
+	mu        sync.Mutex
+	leader    bool
+	leaderURL string
+}
+
+func (s *leaderState) set(leader bool, leaderURL string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if leader != s.leader {
+		if leader {
+			slog.Info("Acquired HA leadership, polling modem")
+		} else {
+			slog.Info("Lost HA leadership, entering standby")
+		}
+	}
+	s.leader = leader
+	s.leaderURL = leaderURL
+}
+
+func (s *leaderState) IsLeader() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.leader
+}
+
+func (s *leaderState) LeaderURL() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.leaderURL
+}
+
+func runElectionLoop(ctx context.Context, interval time.Duration, tick func()) {
+	tick()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			tick()
+		}
+	}
+}
+
+// fileLease is the on-disk format of a file-mode lease.
+type fileLease struct {
+	Holder    string    `json:"holder"`
+	URL       string    `json:"url"`
+	RenewTime time.Time `json:"renewTime"`
+}
+
+// fileElector coordinates through a lease file on storage shared by all
+// instances (e.g. an NFS mount or a shared volume).
+type fileElector struct {
+	leaderState
+	cfg HAConfig
+}
+
+func (e *fileElector) read() (*fileLease, error) {
+	data, err := os.ReadFile(e.cfg.LeaseFile)
+	if err != nil {
+		return nil, err
+	}
+	var l fileLease
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("failed to parse lease file: %w", err)
+	}
+	return &l, nil
+}
+
+func (e *fileElector) write(l *fileLease) error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	tmp := fmt.Sprintf("%s.%s.tmp", e.cfg.LeaseFile, filepath.Base(e.cfg.ID))
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, e.cfg.LeaseFile)
+}
+
+func (e *fileElector) tick() {
+	now := time.Now()
+	current, err := e.read()
+	if err != nil && !os.IsNotExist(err) {
+		slog.Warn("Failed to read HA lease file", "err", err)
+	}
+
+	if current != nil && current.Holder != e.cfg.ID && now.Sub(current.RenewTime) < e.cfg.LeaseDuration {
+		e.set(false, current.URL)
+		return
+	}
+
+	if err := e.write(&fileLease{Holder: e.cfg.ID, URL: e.cfg.AdvertiseURL, RenewTime: now}); err != nil {
+		slog.Warn("Failed to write HA lease file", "err", err)
+		e.set(false, "")
+		return
+	}
+
+	// Two instances may have raced for an expired lease; whoever's write
+	// landed last wins, so read it back before claiming leadership.
+	check, err := e.read()
+	if err != nil || check.Holder != e.cfg.ID {
+		e.set(false, "")
+		return
+	}
+	e.set(true, e.cfg.AdvertiseURL)
+}
+
+func (e *fileElector) Run(ctx context.Context) {
+	runElectionLoop(ctx, e.cfg.LeaseDuration/3, e.tick)
+}
+
+// peerStatus is served at /-/ha and consumed by static-mode peers.
+type peerStatus struct {
+	ID       string `json:"id"`
+	URL      string `json:"url"`
+	Priority int    `json:"priority"`
+	Leader   bool   `json:"leader"`
+}
+
+// staticElector picks the reachable instance with the highest priority.
+// Ties are broken by the lexicographically smallest ID.
+type staticElector struct {
+	leaderState
+	cfg    HAConfig
+	client *http.Client
+}
+
+func (e *staticElector) outranks(p peerStatus) bool {
+	if p.Priority != e.cfg.Priority {
+		return p.Priority > e.cfg.Priority
+	}
+	return p.ID < e.cfg.ID
+}
+
+func (e *staticElector) tick() {
+	leader := true
+	var best *peerStatus
+	for _, peer := range e.cfg.Peers {
+		resp, err := e.client.Get(strings.TrimSuffix(peer, "/") + "/-/ha")
+		if err != nil {
+			continue
+		}
+		var p peerStatus
+		err = json.NewDecoder(resp.Body).Decode(&p)
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK {
+			continue
+		}
+		if p.URL == "" {
+			p.URL = peer
+		}
+		if e.outranks(p) {
+			leader = false
+			if best == nil || p.Priority > best.Priority || (p.Priority == best.Priority && p.ID < best.ID) {
+				best = &p
+			}
+		}
+	}
+
+	if leader {
+		e.set(true, e.cfg.AdvertiseURL)
+	} else {
+		e.set(false, best.URL)
+	}
+}
+
+func (e *staticElector) Run(ctx context.Context) {
+	runElectionLoop(ctx, e.cfg.LeaseDuration/3, e.tick)
+}
+
+const (
+	kubeServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	kubeAdvertiseURLKey   = "coda56-exporter/advertise-url"
+	kubeMicroTime         = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+type kubeLease struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name            string            `json:"name"`
+		Namespace       string            `json:"namespace"`
+		ResourceVersion string            `json:"resourceVersion,omitempty"`
+		Annotations     map[string]string `json:"annotations,omitempty"`
+	} `json:"metadata"`
+	Spec struct {
+		HolderIdentity       string `json:"holderIdentity,omitempty"`
+		LeaseDurationSeconds int    `json:"leaseDurationSeconds,omitempty"`
+		AcquireTime          string `json:"acquireTime,omitempty"`
+		RenewTime            string `json:"renewTime,omitempty"`
+		LeaseTransitions     int    `json:"leaseTransitions,omitempty"`
+	} `json:"spec"`
+}
+
+// kubeElector uses a coordination.k8s.io/v1 Lease via the in-cluster API.
+type kubeElector struct {
+	leaderState
+	cfg    HAConfig
+	apiURL string
+	token  string
+	client *http.Client
+}
+
+func newKubeElector(cfg HAConfig) (*kubeElector, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("kubernetes mode requires running inside a cluster")
+	}
+	if cfg.KubeLease == "" {
+		return nil, fmt.Errorf("-ha-k8s-lease is required for kubernetes mode")
+	}
+
+	token, err := os.ReadFile(filepath.Join(kubeServiceAccountDir, "token"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account token: %w", err)
+	}
+	ca, err := os.ReadFile(filepath.Join(kubeServiceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(ca)
+
+	if cfg.KubeNamespace == "" {
+		ns, err := os.ReadFile(filepath.Join(kubeServiceAccountDir, "namespace"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine namespace: %w", err)
+		}
+		cfg.KubeNamespace = strings.TrimSpace(string(ns))
+	}
+
+	return &kubeElector{
+		cfg:    cfg,
+		apiURL: fmt.Sprintf("https://%s/apis/coordination.k8s.io/v1/namespaces/%s/leases", net.JoinHostPort(host, port), cfg.KubeNamespace),
+		token:  strings.TrimSpace(string(token)),
+		client: &http.Client{
+			Timeout:   cfg.LeaseDuration / 3,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+		},
+	}, nil
+}
+
+func (e *kubeElector) do(method, url string, body any) (*kubeLease, int, error) {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, 0, err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, r)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+e.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, resp.StatusCode, nil
+	}
+	var l kubeLease
+	if err := json.NewDecoder(resp.Body).Decode(&l); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to parse lease: %w", err)
+	}
+	return &l, resp.StatusCode, nil
+}
+
+func (e *kubeElector) tick() {
+	now := time.Now()
+	l, status, err := e.do(http.MethodGet, e.apiURL+"/"+e.cfg.KubeLease, nil)
+	if err != nil {
+		slog.Warn("Failed to get HA lease", "err", err)
+		e.set(false, "")
+		return
+	}
+
+	method, target := http.MethodPut, e.apiURL+"/"+e.cfg.KubeLease
+	switch {
+	case status == http.StatusNotFound:
+		l = &kubeLease{APIVersion: "coordination.k8s.io/v1", Kind: "Lease"}
+		l.Metadata.Name = e.cfg.KubeLease
+		l.Metadata.Namespace = e.cfg.KubeNamespace
+		l.Spec.AcquireTime = now.Format(kubeMicroTime)
+		method, target = http.MethodPost, e.apiURL
+	case l == nil:
+		slog.Warn("Unexpected status getting HA lease", "status", status)
+		e.set(false, "")
+		return
+	case l.Spec.HolderIdentity != e.cfg.ID:
+		renew, _ := time.Parse(kubeMicroTime, l.Spec.RenewTime)
+		if now.Sub(renew) < time.Duration(l.Spec.LeaseDurationSeconds)*time.Second {
+			e.set(false, l.Metadata.Annotations[kubeAdvertiseURLKey])
+			return
+		}
+		l.Spec.AcquireTime = now.Format(kubeMicroTime)
+		l.Spec.LeaseTransitions++
+	}
+
+	l.Spec.HolderIdentity = e.cfg.ID
+	l.Spec.LeaseDurationSeconds = int(e.cfg.LeaseDuration.Seconds())
+	l.Spec.RenewTime = now.Format(kubeMicroTime)
+	if l.Metadata.Annotations == nil {
+		l.Metadata.Annotations = map[string]string{}
+	}
+	l.Metadata.Annotations[kubeAdvertiseURLKey] = e.cfg.AdvertiseURL
+
+	// The resourceVersion makes this a compare-and-swap: a 409 means another
+	// instance updated the lease first.
+	if _, status, err = e.do(method, target, l); err != nil || status/100 != 2 {
+		if err != nil {
+			slog.Warn("Failed to update HA lease", "err", err)
+		}
+		e.set(false, "")
+		return
+	}
+	e.set(true, e.cfg.AdvertiseURL)
+}
+
+func (e *kubeElector) Run(ctx context.Context) {
+	runElectionLoop(ctx, e.cfg.LeaseDuration/3, e.tick)
+}
+
+// haStatusHandler serves this instance's election state for static-mode peers.
+func haStatusHandler(e Elector, cfg HAConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, peerStatus{
+			ID:       cfg.ID,
+			URL:      cfg.AdvertiseURL,
+			Priority: cfg.Priority,
+			Leader:   e.IsLeader(),
+		})
+	}
+}
+
+// standbyProxyHandler forwards scrapes to the current leader while this
+// instance is on standby, falling back to the local (cached) handler when the
+// leader is unknown.
+func standbyProxyHandler(e Elector, local http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaderURL := e.LeaderURL()
+		if e.IsLeader() || leaderURL == "" {
+			local.ServeHTTP(w, r)
+			return
+		}
+		target, err := url.Parse(leaderURL)
+		if err != nil {
+			local.ServeHTTP(w, r)
+			return
+		}
+		httputil.NewSingleHostReverseProxy(target).ServeHTTP(w, r)
+	})
+}
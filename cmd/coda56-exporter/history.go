@@ -0,0 +1,394 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// historyFields are the point fields kept in the history.
+var historyFields = map[string]bool{
+	"power_dbmv":     true,
+	"snr_db":         true,
+	"correctables":   true,
+	"uncorrectables": true,
+}
+
+// historyMetrics are the short names the history API accepts for
+// historyFields.
+var historyMetrics = map[string]string{
+	"power": "power_dbmv",
+	"snr":   "snr_db",
+}
+
+// historyCompactInterval is how often the history file is rewritten without
+// the samples that have aged out.
+const historyCompactInterval = time.Hour
+
+// sample is one value of a series, at a Unix time in milliseconds.
+type sample struct {
+	T int64
+	V float64
+}
+
+// MarshalJSON encodes a sample as [time, value] to keep payloads small.
+func (s sample) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("[%d,%g]", s.T, s.V)), nil
+}
+
+// modemHistory is the history of one modem. Series are keyed as
+// measurement/channel/field, such as "downstream/12/snr_db".
+type modemHistory struct {
+	labels map[string]string
+	series map[string][]sample
+}
+
+// historyRecord is one poll of one modem in the history file.
+type historyRecord struct {
+	Labels map[string]string  `json:"labels,omitempty"`
+	T      int64              `json:"t"`
+	Values map[string]float64 `json:"values"`
+}
+
+// historyStore keeps recent per-channel values of every modem, for the web
+// UI's charts and the history API. It is a sink, so it outlives
+// configuration reloads. With a path, every poll is also appended to that
+// file as a JSON line, and the file is read back on startup.
+type historyStore struct {
+	retention time.Duration
+	path      string
+
+	mu        sync.RWMutex
+	modems    map[string]*modemHistory
+	file      *os.File
+	compacted time.Time
+}
+
+// openHistoryStore creates the store, loading the samples in path that are
+// still within the retention. An empty path keeps the history in memory
+// only.
+func openHistoryStore(retention time.Duration, path string) (*historyStore, error) {
+	h := &historyStore{retention: retention, path: path, modems: map[string]*modemHistory{}}
+	if path == "" {
+		return h, nil
+	}
+	if err := h.load(); err != nil {
+		return nil, fmt.Errorf("failed to load history: %w", err)
+	}
+	if err := h.compact(time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to write history: %w", err)
+	}
+	return h, nil
+}
+
+func (h *historyStore) load() error {
+	f, err := os.Open(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	cutoff := time.Now().Add(-h.retention).UnixMilli()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	skipped := 0
+	for scanner.Scan() {
+		var r historyRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			// Most likely the last line, cut short by a crash
+			skipped++
+			continue
+		}
+		if r.T >= cutoff {
+			h.add(r.Labels, r.T, r.Values)
+		}
+	}
+	if skipped > 0 {
+		slog.Warn("Skipped unreadable history records", "path", h.path, "count", skipped)
+	}
+	return scanner.Err()
+}
+
+// compact rewrites the history file from memory, dropping what has aged
+// out, and reopens it for appending. h.mu must be held or the store not yet
+// shared.
+func (h *historyStore) compact(now time.Time) error {
+	if h.file != nil {
+		h.file.Close()
+		h.file = nil
+	}
+	err := writeFileAtomic(h.path, func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		enc := json.NewEncoder(bw)
+		for _, m := range h.modems {
+			polls := map[int64]map[string]float64{}
+			for name, s := range m.series {
+				for _, v := range s {
+					if polls[v.T] == nil {
+						polls[v.T] = map[string]float64{}
+					}
+					polls[v.T][name] = v.V
+				}
+			}
+			times := make([]int64, 0, len(polls))
+			for t := range polls {
+				times = append(times, t)
+			}
+			sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+			for _, t := range times {
+				if err := enc.Encode(historyRecord{Labels: m.labels, T: t, Values: polls[t]}); err != nil {
+					return err
+				}
+			}
+		}
+		return bw.Flush()
+	})
+	if err != nil {
+		return err
+	}
+	h.file, err = os.OpenFile(h.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	h.compacted = now
+	return err
+}
+
+func (h *historyStore) Name() string { return "history" }
+
+func (h *historyStore) Write(snap *hitron.Snapshot, tags map[string]string) error {
+	values := map[string]float64{}
+	for _, p := range snapshotPoints(snap) {
+		channel := ""
+		for _, tag := range p.tags {
+			if identityTags[tag.key] {
+				channel = tag.value
+			}
+		}
+		for _, f := range p.fields {
+			if historyFields[f.key] {
+				values[p.measurement+"/"+channel+"/"+f.key] = f.value
+			}
+		}
+	}
+	t := snap.Time.UnixMilli()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.add(tags, t, values) {
+		return nil
+	}
+	h.trim(snap.Time)
+	if h.file == nil {
+		return nil
+	}
+	if snap.Time.Sub(h.compacted) >= historyCompactInterval {
+		return h.compact(snap.Time)
+	}
+	line, err := json.Marshal(historyRecord{Labels: tags, T: t, Values: values})
+	if err != nil {
+		return err
+	}
+	if _, err := h.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to append to history: %w", err)
+	}
+	return nil
+}
+
+// add records one poll, reporting false if the modem's history already has
+// it. h.mu must be held.
+func (h *historyStore) add(labels map[string]string, t int64, values map[string]float64) bool {
+	key := fmt.Sprint(labels)
+	m := h.modems[key]
+	if m == nil {
+		m = &modemHistory{labels: labels, series: map[string][]sample{}}
+		h.modems[key] = m
+	}
+	added := false
+	for name, v := range values {
+		s := m.series[name]
+		if n := len(s); n > 0 && s[n-1].T >= t {
+			continue
+		}
+		m.series[name] = append(s, sample{T: t, V: v})
+		added = true
+	}
+	return added
+}
+
+// trim drops samples older than the retention. h.mu must be held.
+func (h *historyStore) trim(now time.Time) {
+	cutoff := now.Add(-h.retention).UnixMilli()
+	for _, m := range h.modems {
+		for name, s := range m.series {
+			i := sort.Search(len(s), func(i int) bool { return s[i].T >= cutoff })
+			switch {
+			case i == len(s):
+				// The channel is gone
+				delete(m.series, name)
+			case i > 0:
+				// append copies the live samples once the array fills up, so
+				// the dropped ones are released eventually
+				m.series[name] = s[i:]
+			}
+		}
+	}
+}
+
+// series returns a copy of every series of the modem with the given static
+// labels, limited to samples since the given time.
+func (h *historyStore) series(tags map[string]string, since time.Time) map[string][]sample {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	result := map[string][]sample{}
+	m := h.modems[fmt.Sprint(tags)]
+	if m == nil {
+		return result
+	}
+	from := since.UnixMilli()
+	for name, s := range m.series {
+		i := sort.Search(len(s), func(i int) bool { return s[i].T >= from })
+		if i < len(s) {
+			result[name] = append([]sample(nil), s[i:]...)
+		}
+	}
+	return result
+}
+
+// labelsNamed returns the static labels of the modem with the given name in
+// the config file.
+func (h *historyStore) labelsNamed(name string) (map[string]string, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, m := range h.modems {
+		if m.labels["modem"] == name {
+			return m.labels, true
+		}
+	}
+	return nil, false
+}
+
+// historySeries is one channel's samples in a history API response.
+type historySeries struct {
+	Type    string   `json:"type"`
+	Channel string   `json:"channel"`
+	Samples []sample `json:"samples"`
+}
+
+// downsample averages samples into buckets of step milliseconds, each
+// reported at the start of its bucket.
+func downsample(samples []sample, step int64) []sample {
+	var out []sample
+	var sum float64
+	n := 0
+	bucket := int64(math.MinInt64)
+	for _, s := range samples {
+		b := s.T - s.T%step
+		if b != bucket && n > 0 {
+			out = append(out, sample{T: bucket, V: sum / float64(n)})
+			sum, n = 0, 0
+		}
+		bucket = b
+		sum += s.V
+		n++
+	}
+	if n > 0 {
+		out = append(out, sample{T: bucket, V: sum / float64(n)})
+	}
+	return out
+}
+
+// registerHistoryHandler exposes the history at /api/v1/history, for
+// example ?metric=snr&channel=12&range=24h.
+func registerHistoryHandler(mux *http.ServeMux, collector func() *MetricsCollector, history *historyStore, auth *APIAuth) {
+	mux.Handle("GET /api/v1/history", auth.Require(ScopeRead, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		metric := q.Get("metric")
+		field := metric
+		if f, ok := historyMetrics[metric]; ok {
+			field = f
+		}
+		if !historyFields[field] {
+			writeJSONError(w, http.StatusBadRequest, errors.New("metric must be one of power, snr, correctables or uncorrectables"))
+			return
+		}
+		rng := 24 * time.Hour
+		if s := q.Get("range"); s != "" {
+			d, err := time.ParseDuration(s)
+			if err != nil || d <= 0 {
+				writeJSONError(w, http.StatusBadRequest, errors.New("range must be a duration such as 24h"))
+				return
+			}
+			rng = d
+		}
+		var step time.Duration
+		if s := q.Get("step"); s != "" {
+			d, err := time.ParseDuration(s)
+			if err != nil || d < time.Millisecond {
+				writeJSONError(w, http.StatusBadRequest, errors.New("step must be a duration such as 5m"))
+				return
+			}
+			step = d
+		}
+
+		labels := collector().tags
+		if name := q.Get("modem"); name != "" {
+			var ok bool
+			if labels, ok = history.labelsNamed(name); !ok {
+				writeJSONError(w, http.StatusNotFound, fmt.Errorf("no history for modem %q", name))
+				return
+			}
+		}
+
+		now := time.Now()
+		result := []historySeries{}
+		for name, samples := range history.series(labels, now.Add(-rng)) {
+			parts := strings.SplitN(name, "/", 3)
+			if len(parts) != 3 || parts[2] != field {
+				continue
+			}
+			if t := q.Get("type"); t != "" && parts[0] != t {
+				continue
+			}
+			if c := q.Get("channel"); c != "" && parts[1] != c {
+				continue
+			}
+			if step > 0 {
+				samples = downsample(samples, step.Milliseconds())
+			}
+			result = append(result, historySeries{Type: parts[0], Channel: parts[1], Samples: samples})
+		}
+		sort.Slice(result, func(i, j int) bool {
+			if result[i].Type != result[j].Type {
+				return result[i].Type < result[j].Type
+			}
+			return channelLess(result[i].Channel, result[j].Channel)
+		})
+		writeJSON(w, http.StatusOK, map[string]any{
+			"metric": field,
+			"from":   now.Add(-rng).UnixMilli(),
+			"to":     now.UnixMilli(),
+			"series": result,
+		})
+	})))
+}
+
+// channelLess orders channel IDs numerically when they are numbers.
+func channelLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
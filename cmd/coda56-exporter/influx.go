@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+func init() {
+	registerSink("influxdb", sinkType{
+		fromFlags: func(cfg *Config) (sink, error) {
+			if *influxURL == "" {
+				return nil, nil
+			}
+			if *influxOrg == "" || *influxBucket == "" {
+				return nil, fmt.Errorf("-influx.url requires -influx.org and -influx.bucket")
+			}
+			return influxSettings{*influxURL, *influxOrg, *influxBucket, *influxToken, *influxTokenFile}.newSink(cfg)
+		},
+		fromConfig: func(cfg *Config, node *yaml.Node) (sink, error) {
+			s := influxSettings{Org: *influxOrg, Bucket: *influxBucket, Token: *influxToken, TokenFile: *influxTokenFile}
+			if err := decodeSinkSettings(node, &s); err != nil {
+				return nil, err
+			}
+			if s.URL == "" || s.Org == "" || s.Bucket == "" {
+				return nil, fmt.Errorf("url, org and bucket are required")
+			}
+			return s.newSink(cfg)
+		},
+	})
+}
+
+// influxSettings configure an InfluxDB output, as with the -influx.* flags.
+type influxSettings struct {
+	URL       string `yaml:"url"`
+	Org       string `yaml:"org"`
+	Bucket    string `yaml:"bucket"`
+	Token     string `yaml:"token"`
+	TokenFile string `yaml:"token_file"`
+}
+
+func (s influxSettings) newSink(cfg *Config) (sink, error) {
+	token := s.Token
+	if s.TokenFile != "" {
+		data, err := os.ReadFile(s.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read InfluxDB token file: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	return newInfluxSink(s.URL, s.Org, s.Bucket, token, cfg.namespacePrefix(), cfg.Timeout), nil
+}
+
+// influxSink writes each poll to an InfluxDB v2 bucket in line protocol.
+type influxSink struct {
+	writeURL string
+	token    string
+	prefix   string
+	client   *http.Client
+}
+
+func newInfluxSink(baseURL, org, bucket, token, prefix string, timeout time.Duration) *influxSink {
+	q := url.Values{"org": {org}, "bucket": {bucket}, "precision": {"ns"}}
+	return &influxSink{
+		writeURL: strings.TrimRight(baseURL, "/") + "/api/v2/write?" + q.Encode(),
+		token:    token,
+		prefix:   prefix,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *influxSink) Name() string { return "influxdb" }
+
+func (s *influxSink) Write(snap *hitron.Snapshot, tags map[string]string) error {
+	var body bytes.Buffer
+	for _, p := range snapshotPoints(snap) {
+		writeLineProtocol(&body, s.prefix+p.measurement, p, tags)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.writeURL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Token "+s.token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to write to InfluxDB: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("InfluxDB write failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	tagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+)
+
+// writeLineProtocol appends p as one line. extra tags, such as the modem
+// name, come before the point's own tags, and all tags are sorted by key as
+// InfluxDB recommends.
+func writeLineProtocol(w *bytes.Buffer, measurement string, p point, extra map[string]string) {
+	tags := make([]tag, 0, len(extra)+len(p.tags))
+	for k, v := range extra {
+		if v != "" {
+			tags = append(tags, tag{k, v})
+		}
+	}
+	tags = append(tags, p.tags...)
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].key < tags[j].key })
+
+	w.WriteString(measurementEscaper.Replace(measurement))
+	for _, t := range tags {
+		w.WriteByte(',')
+		w.WriteString(tagEscaper.Replace(t.key))
+		w.WriteByte('=')
+		w.WriteString(tagEscaper.Replace(t.value))
+	}
+	for i, f := range p.fields {
+		if i == 0 {
+			w.WriteByte(' ')
+		} else {
+			w.WriteByte(',')
+		}
+		w.WriteString(tagEscaper.Replace(f.key))
+		w.WriteByte('=')
+		if f.integer {
+			w.WriteString(strconv.FormatInt(int64(f.value), 10))
+			w.WriteByte('i')
+		} else {
+			w.WriteString(strconv.FormatFloat(f.value, 'g', -1, 64))
+		}
+	}
+	w.WriteByte(' ')
+	w.WriteString(strconv.FormatInt(p.time.UnixNano(), 10))
+	w.WriteByte('\n')
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+)
+
+type ingestedPayload struct {
+	body     []byte
+	received time.Time
+}
+
+// IngestSource holds the most recent payloads pushed to /api/v1/ingest, for
+// setups where the exporter can't reach the modem network and a small script
+// next to the modem forwards its JSON instead.
+type IngestSource struct {
+	maxAge time.Duration
+
+	mu       sync.Mutex
+	payloads map[string]ingestedPayload
+}
+
+func NewIngestSource(maxAge time.Duration) *IngestSource {
+	return &IngestSource{maxAge: maxAge, payloads: map[string]ingestedPayload{}}
+}
+
+func (s *IngestSource) Fetch(endpoint string) ([]byte, error) {
+	s.mu.Lock()
+	p, ok := s.payloads[endpoint]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no data ingested for %s", endpoint)
+	}
+	if age := time.Since(p.received); s.maxAge > 0 && age > s.maxAge {
+		return nil, fmt.Errorf("ingested data for %s is stale (%s old)", endpoint, age.Round(time.Second))
+	}
+	return p.body, nil
+}
+
+func (s *IngestSource) store(endpoint string, body []byte) error {
+	if !ingestEndpointRe.MatchString(endpoint) {
+		return fmt.Errorf("invalid endpoint %q", endpoint)
+	}
+	if !json.Valid(body) {
+		return fmt.Errorf("payload for %s is not valid JSON", endpoint)
+	}
+	s.mu.Lock()
+	s.payloads[endpoint] = ingestedPayload{body: body, received: time.Now()}
+	s.mu.Unlock()
+	return nil
+}
+
+// Optional pages such as service flow tables can be pushed too, so any
+// data page name is accepted rather than just hitron.Endpoints.
+var ingestEndpointRe = regexp.MustCompile(`^[A-Za-z0-9_]+\.asp$`)
+
+const maxIngestBody = 1 << 20
+
+// requireIngestAuth only accepts pushes under the ingest scope and, unlike
+// the read-only API, refuses them outright while no tokens are configured.
+func requireIngestAuth(auth *APIAuth, h http.Handler) http.Handler {
+	scoped := auth.Require(ScopeIngest, h)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !auth.Enabled() {
+			writeJSONError(w, http.StatusForbidden, fmt.Errorf("ingestion requires API tokens to be configured"))
+			return
+		}
+		scoped.ServeHTTP(w, r)
+	})
+}
+
+// registerIngestHandlers exposes the push endpoints. Payloads can be posted
+// one endpoint at a time as the raw page body, or all together as a JSON
+// object keyed by endpoint name:
+//
+//	POST /api/v1/ingest/dsinfo.asp    <raw dsinfo.asp body>
+//	POST /api/v1/ingest               {"dsinfo.asp": [...], "usinfo.asp": [...]}
+func registerIngestHandlers(mux *http.ServeMux, source *IngestSource, auth *APIAuth) {
+	mux.Handle("POST /api/v1/ingest", requireIngestAuth(auth, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payloads map[string]json.RawMessage
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxIngestBody)).Decode(&payloads); err != nil {
+			writeJSONError(w, http.StatusBadRequest, fmt.Errorf("failed to parse ingest payload: %w", err))
+			return
+		}
+		var stored []string
+		for endpoint, body := range payloads {
+			if err := source.store(endpoint, body); err != nil {
+				writeJSONError(w, http.StatusBadRequest, err)
+				return
+			}
+			stored = append(stored, endpoint)
+		}
+		slog.Debug("Ingested payloads", "count", len(stored), "client", clientAddr(r))
+		writeJSON(w, http.StatusOK, map[string][]string{"stored": stored})
+	})))
+
+	mux.Handle("POST /api/v1/ingest/{endpoint}", requireIngestAuth(auth, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIngestBody))
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err)
+			return
+		}
+		endpoint := r.PathValue("endpoint")
+		if err := source.store(endpoint, body); err != nil {
+			writeJSONError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string][]string{"stored": {endpoint}})
+	})))
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// requestMetrics cover each HTTP request to the modem's data pages:
+// how long it took, the status it was answered with and why it failed.
+type requestMetrics struct {
+	durations *prometheus.HistogramVec
+	responses *prometheus.CounterVec
+	errors    *prometheus.CounterVec
+
+	// selfSigned logs how to trust the modem's certificate the first time
+	// a request fails on it.
+	selfSigned sync.Once
+}
+
+// newRequestMetrics returns the request metrics of a modem and the client
+// option that fills them. They outlive a single collector, so they are made
+// along with the modem's client.
+func newRequestMetrics() (*requestMetrics, hitron.Option) {
+	m := &requestMetrics{
+		durations: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "modem_request_duration_seconds",
+				Help:    "Round-trip time of HTTP requests to the modem's data pages, including retries, from sending the request to reading the response",
+				Buckets: []float64{.025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+				// Scrapers that ask for native histograms get buckets about
+				// 10% wide instead, fine enough to see the tail move.
+				NativeHistogramBucketFactor:     1.1,
+				NativeHistogramMaxBucketNumber:  160,
+				NativeHistogramMinResetDuration: time.Hour,
+			},
+			[]string{"endpoint"},
+		),
+		responses: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "modem_http_responses_total",
+				Help: "Number of HTTP responses from the modem's data pages, including retries, by status code",
+			},
+			[]string{"endpoint", "code"},
+		),
+		errors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "modem_request_errors_total",
+				Help: "Number of failed HTTP requests to the modem's data pages, including retries, by reason: timeout, connection_refused, tls, dns, http_status, login_required, invalid_response or other",
+			},
+			[]string{"endpoint", "reason"},
+		),
+	}
+	return m, hitron.WithRequestObserver(func(endpoint string, d time.Duration, code int, err error) {
+		m.durations.WithLabelValues(endpoint).Observe(d.Seconds())
+		if code != 0 {
+			m.responses.WithLabelValues(endpoint, strconv.Itoa(code)).Inc()
+		}
+		if err != nil {
+			m.errors.WithLabelValues(endpoint, hitron.ClassifyError(err)).Inc()
+		}
+		if errors.Is(err, hitron.ErrSelfSigned) {
+			m.selfSigned.Do(func() {
+				slog.Error("The modem's certificate is self-signed and can't be verified: pin it with -modem-cert-fingerprint, or accept any certificate with -modem-insecure-skip-verify", "err", err)
+			})
+		}
+	})
+}
+
+func (m *requestMetrics) describe(ch chan<- *prometheus.Desc) {
+	m.durations.Describe(ch)
+	m.responses.Describe(ch)
+	m.errors.Describe(ch)
+}
+
+func (m *requestMetrics) collect(ch chan<- prometheus.Metric) {
+	m.durations.Collect(ch)
+	m.responses.Collect(ch)
+	m.errors.Collect(ch)
+}
+
+// latencyCollector exports how quickly and how well the modem's management
+// interface answers: the HTTP requests of every poll and, optionally, a TCP
+// connect or ICMP echo probe before each poll. Latency rising there often
+// comes before the modem's web server locks up.
+type latencyCollector struct {
+	requests *requestMetrics
+	probe    string
+
+	rtt        *prometheus.GaugeVec
+	rttSuccess *prometheus.GaugeVec
+}
+
+func newLatencyCollector(requests *requestMetrics, probe string) *latencyCollector {
+	if requests == nil {
+		requests, _ = newRequestMetrics()
+	}
+	return &latencyCollector{
+		requests: requests,
+		probe:    probe,
+		rtt: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "modem_rtt_seconds",
+				Help: "Round-trip time of the last successful -modem-rtt-probe to the modem",
+			},
+			[]string{"probe"},
+		),
+		rttSuccess: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "modem_rtt_probe_success",
+				Help: "Whether the last -modem-rtt-probe got an answer from the modem (1 = answered)",
+			},
+			[]string{"probe"},
+		),
+	}
+}
+
+func (c *latencyCollector) update(ctx context.Context, client *hitron.ModemClient) {
+	if c.probe == "" {
+		return
+	}
+	rtt, err := client.ProbeRTT(ctx, c.probe)
+	if err != nil {
+		slog.Warn("Modem RTT probe failed", "probe", c.probe, "err", err)
+		c.rtt.Reset()
+		c.rttSuccess.WithLabelValues(c.probe).Set(0)
+		return
+	}
+	c.rtt.WithLabelValues(c.probe).Set(rtt.Seconds())
+	c.rttSuccess.WithLabelValues(c.probe).Set(1)
+}
+
+func (c *latencyCollector) describe(ch chan<- *prometheus.Desc) {
+	c.requests.describe(ch)
+	c.rtt.Describe(ch)
+	c.rttSuccess.Describe(ch)
+}
+
+func (c *latencyCollector) collect(ch chan<- prometheus.Metric) {
+	c.requests.collect(ch)
+	c.rtt.Collect(ch)
+	c.rttSuccess.Collect(ch)
+}
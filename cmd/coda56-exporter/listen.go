@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+)
+
+// unixPrefix marks a -listen-addr that is a Unix domain socket path, as in
+// unix:///run/coda56-exporter.sock.
+const unixPrefix = "unix://"
+
+// socketPath returns the socket path of a unix:// listen address, or false
+// for a TCP address.
+func socketPath(addr string) (string, bool) {
+	path, ok := strings.CutPrefix(addr, unixPrefix)
+	return path, ok
+}
+
+// listenUnix listens on a Unix domain socket at path. The exporter isn't
+// shut down gracefully, so a socket left behind by a previous run is removed
+// first; any other file at path is an error.
+func listenUnix(path string) (net.Listener, error) {
+	if path == "" {
+		return nil, errors.New("missing socket path")
+	}
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode().Type() != fs.ModeSocket {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,2094 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/exporter-toolkit/web"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/anupcshan/coda56-exporter/pkg/collector"
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+var (
+	modemHost                = flag.String("modem-host", "https://192.168.100.1", "Hitron CODA56 modem host URL, or a host name or IP address to reach over https")
+	modemProxyURL            = flag.String("modem-proxy-url", "", "HTTP or SOCKS5 proxy to reach the modem through, such as socks5://jumphost:1080 (default: connect directly)")
+	modemFallbacks           = flag.String("modem-fallbacks", "", "Comma-separated schemes and ports to try in order when the modem keeps failing to answer or serve a page at -modem-host, such as http,https:8443; plain HTTP is skipped when the certificate is verified (default: none)")
+	modemResolve             = flag.String("modem-resolve", "", "IP address to connect to instead of resolving the host of -modem-host, which is still used for TLS and the Host header")
+	configFile               = flag.String("config", "", "YAML configuration file")
+	listenAddr               = flag.String("listen-addr", ":2632", "Address to listen on for HTTP requests, or unix:///path/to.sock for a Unix domain socket")
+	timeout                  = flag.Duration("timeout", 10*time.Second, "HTTP request timeout")
+	modemUsername            = flag.String("modem-username", "", "Username for the modem's web login, for firmware that requires a session")
+	modemPassword            = flag.String("modem-password", "", "Password for the modem's web login")
+	modemPasswordFile        = flag.String("modem-password-file", "", "File containing the password for the modem's web login")
+	collectMode              = flag.String("collect-mode", "", "When to poll the modem: pull (on every scrape) or background (every -interval) (default background, or pull with -interval 0)")
+	interval                 = flag.Duration("interval", 30*time.Second, "How often to poll the modem in the background (0 = poll on every scrape)")
+	intervalJitter           = flag.Float64("interval-jitter", 0, "Randomize each background poll interval by up to this fraction either way (0 to 1), and start polling after a random part of it")
+	cacheTTL                 = flag.Duration("cache.ttl", 0, "Drop the channel series of a page that has failed to fetch for longer than this (0 = keep serving them until it is fetched again)")
+	minPollInterval          = flag.Duration("modem-min-poll-interval", 0, "With -collect-mode pull, answer scrapes from the previous poll if it is more recent than this (0 = poll on every scrape)")
+	modemCAFile              = flag.String("modem-ca-file", "", "PEM CA bundle used to verify the modem's certificate")
+	modemFingerprint         = flag.String("modem-cert-fingerprint", "", "SHA-256 fingerprint of the modem's certificate to pin")
+	modemInsecure            = flag.Bool("modem-insecure-skip-verify", false, "Accept any modem certificate instead of verifying it against the system roots; ignored with -modem-ca-file or -modem-cert-fingerprint")
+	logLevel                 = flag.String("log.level", "info", "Minimum level of log messages: debug, info, warn or error")
+	logFormat                = flag.String("log.format", "text", "Log format: text or json")
+	redactIdentifiers        = flag.Bool("redact-identifiers", false, "Blank the modem's serial number and MAC address in metrics, APIs, recordings and dumps")
+	modemModel               = flag.String("modem-model", autoModel, "Modem model, which selects the data pages to poll: "+autoModel+" to detect it, or one of "+strings.Join(hitron.Models(), ", "))
+	snmpAddress              = flag.String("snmp.address", "", "Modem's DOCSIS SNMP agent, as host or host:port (default: disabled)")
+	snmpCommunity            = flag.String("snmp.community", "public", "SNMP community for -snmp.address")
+	snmpMode                 = flag.String("snmp.mode", snmpFallback, "How the SNMP agent is used: fallback for pages the web interface fails to serve, or only to skip the web interface")
+	modemRTTProbe            = flag.String("modem-rtt-probe", "", "Measure the round trip to the modem before each poll: tcp to time connecting to it, icmp to ping it (default: disabled)")
+	modemConcurrency         = flag.Int("modem-concurrency", 3, "Maximum number of concurrent requests to the modem during a poll")
+	modemRetries             = flag.Int("modem-retries", 1, "How many times to retry a failed modem request")
+	modemRetryDelay          = flag.Duration("modem-retry-delay", 500*time.Millisecond, "Wait before the first retry of a modem request, doubling for each further retry")
+	modemKeepAlive           = flag.Bool("modem-keepalive", true, "Reuse connections to the modem; false sends Connection: close and reconnects for every request")
+	modemMaxIdleConns        = flag.Int("modem-max-idle-conns", 2, "Maximum idle connections kept open to the modem for reuse")
+	modemIdleTimeout         = flag.Duration("modem-idle-conn-timeout", 90*time.Second, "Close idle connections to the modem after this long")
+	modemBreakerThreshold    = flag.Int("modem-breaker-threshold", 3, "Stop polling the modem after this many consecutive requests get no answer, until it answers again (0 = never)")
+	modemBreakerInterval     = flag.Duration("modem-breaker-probe-interval", 10*time.Second, "How often to check whether an unreachable modem is back")
+	modemRetryJitter         = flag.Float64("modem-retry-jitter", 0.2, "Randomize each retry wait by up to this fraction either way (0 to 1)")
+	combinedEndpoint         = flag.String("combined-endpoint", "getViewInfo.asp", "Aggregate status page to try before individual endpoints (empty to disable)")
+	usServiceFlowEndpoint    = flag.String("upstream-service-flow-endpoint", "", "Data page with the upstream service flow table, e.g. usServiceFlow.asp (default: disabled)")
+	dsServiceFlowEndpoint    = flag.String("downstream-service-flow-endpoint", "", "Data page with the downstream QoS/service flow table, e.g. dsServiceFlow.asp (default: disabled)")
+	eventLogEndpoint         = flag.String("event-log-endpoint", "getEventLog.asp", "Data page with the DOCSIS event log (empty to disable)")
+	cmInitEndpoint           = flag.String("cm-init-endpoint", "getCMInit.asp", "Data page with the DOCSIS initialization steps (empty to disable)")
+	docsisWANEndpoint        = flag.String("docsis-wan-endpoint", "getCmDocsisWan.asp", "Data page with the WAN IPv4 and IPv6 addresses (empty to disable)")
+	autoRebootUncorrectables = flag.Float64("auto-reboot.uncorrectables-per-minute", 0, "Reboot the modem when uncorrectable codewords grow faster than this per minute for -auto-reboot.polls polls in a row (0 = off)")
+	autoRebootOFDMLockLoss   = flag.Bool("auto-reboot.ofdm-lock-loss", false, "Reboot the modem when no OFDM channel is locked for -auto-reboot.polls polls in a row")
+	autoRebootPolls          = flag.Int("auto-reboot.polls", 3, "Consecutive degraded polls that trigger an automatic reboot")
+	autoRebootCooldown       = flag.Duration("auto-reboot.cooldown", time.Hour, "Minimum time between automatic reboots")
+	watchdogPolls            = flag.Int("watchdog.polls", 0, "Consecutive polls that fetch no page before the watchdog resets the modem connections and then reports the exporter degraded (0 = off)")
+	watchdogResetConnections = flag.Bool("watchdog.reset-connections", true, "Have the watchdog reset the modem connections and session before reporting the exporter degraded")
+	specDownstreamPowerMin   = flag.Float64("spec.downstream-power-min", -15, "Lowest downstream power in dBmV counted as in spec by *_in_spec metrics")
+	specDownstreamPowerMax   = flag.Float64("spec.downstream-power-max", 15, "Highest downstream power in dBmV counted as in spec by *_in_spec metrics")
+	specSNRMin               = flag.Float64("spec.snr-min", 30, "Lowest downstream SNR in dB counted as in spec by *_in_spec metrics")
+	specUpstreamPowerMin     = flag.Float64("spec.upstream-power-min", 35, "Lowest upstream power in dBmV counted as in spec by *_in_spec metrics")
+	specUpstreamPowerMax     = flag.Float64("spec.upstream-power-max", 51, "Highest upstream power in dBmV counted as in spec by *_in_spec metrics")
+	requireReadyMetrics      = flag.Bool("metrics.require-ready", false, "Answer /metrics with 503 until every modem has been polled successfully once")
+	strict                   = flag.Bool("strict", false, "Fail a whole modem page when any of its values can't be parsed, dropping its channel series, rather than leaving out just those values")
+	compactLabels            = flag.Bool("metrics.compact-labels", false, "Identify channel series by channel only, exporting frequency and modulation in *_channel_info metrics")
+	influxURL                = flag.String("influx.url", "", "InfluxDB v2 URL to write each poll to, e.g. http://influxdb:8086 (default: disabled)")
+	influxOrg                = flag.String("influx.org", "", "InfluxDB organization")
+	influxBucket             = flag.String("influx.bucket", "", "InfluxDB bucket")
+	influxToken              = flag.String("influx.token", "", "InfluxDB API token")
+	influxTokenFile          = flag.String("influx.token-file", "", "File containing the InfluxDB API token")
+	graphiteAddress          = flag.String("graphite.address", "", "Graphite plaintext listener to send each poll to, as host:port (default: disabled)")
+	statsdAddress            = flag.String("statsd.address", "", "StatsD server to send each poll to as gauges, as host:port (default: disabled)")
+	graphitePrefix           = flag.String("graphite.prefix", "", "Path prefix for Graphite and StatsD (default: the metrics namespace)")
+	graphiteFlush            = flag.Duration("graphite.flush-interval", 0, "Resend the latest values to Graphite and StatsD at this interval instead of after every poll")
+	historyRetention         = flag.Duration("history.retention", 24*time.Hour, "How much per-channel history to keep for the web UI and /api/v1/history")
+	historyPath              = flag.String("history.path", "", "File to keep the history in across restarts (default: memory only)")
+	stateFile                = flag.String("state-file", "", "File to keep the modem counters' last values in across restarts, to detect resets while the exporter is down (default: memory only)")
+	sampleLogPath            = flag.String("log-samples.path", "", "File to append every poll's values to, for handing signal logs to the provider (default: disabled)")
+	sampleLogFormat          = flag.String("log-samples.format", sampleLogJSONL, "Sample log format: jsonl or csv")
+	sampleLogMaxSize         = flag.Int("log-samples.max-size-mb", 100, "Rotate the sample log once it reaches this many megabytes (0 = never)")
+	sampleLogMaxFiles        = flag.Int("log-samples.max-files", 5, "Rotated sample logs to keep")
+	alertWebhookURL          = flag.String("alert.webhook-url", "", "Webhook to post to when a signal threshold is crossed or clears (default: disabled)")
+	alertWebhookFormat       = flag.String("alert.webhook-format", alertFormatGeneric, "Webhook payload: generic JSON, slack or discord")
+	alertDsPowerMin          = flag.Float64("alert.downstream-power-min", -7, "Alert when a downstream channel's power drops below this many dBmV")
+	alertDsPowerMax          = flag.Float64("alert.downstream-power-max", 7, "Alert when a downstream channel's power rises above this many dBmV")
+	alertSNRMin              = flag.Float64("alert.snr-min", 33, "Alert when a downstream channel's SNR drops below this many dB")
+	alertUsPowerMax          = flag.Float64("alert.upstream-power-max", 51, "Alert when an upstream channel's transmit power rises above this many dBmV")
+	alertUncorrectables      = flag.Float64("alert.uncorrectables-per-minute", 1000, "Alert when uncorrectable codewords grow faster than this per minute (0 = off)")
+	otlpEndpoint             = flag.String("otlp.endpoint", "", "OpenTelemetry collector to export metrics to, as host:port or URL (default: disabled)")
+	otlpProtocol             = flag.String("otlp.protocol", "grpc", "OTLP transport: grpc or http")
+	otlpInsecure             = flag.Bool("otlp.insecure", false, "Connect to the OTLP endpoint without TLS")
+	otlpInterval             = flag.Duration("otlp.interval", 30*time.Second, "How often to export metrics over OTLP")
+	grpcListenAddr           = flag.String("grpc.listen-addr", "", "Address to serve the gRPC API on, or unix:///path/to.sock (default: disabled)")
+	grpcTLSCertFile          = flag.String("grpc.tls-cert-file", "", "Certificate to serve the gRPC API over TLS with (default: plaintext)")
+	grpcTLSKeyFile           = flag.String("grpc.tls-key-file", "", "Private key of -grpc.tls-cert-file")
+	otlpTraces               = flag.Bool("otlp.traces", false, "Also export a trace of each modem poll over OTLP, with a span per modem request")
+	metricsNamespace         = flag.String("metrics.namespace", "hitron", "Prefix for the exporter's metric names")
+	runtimeMetrics           = flag.Bool("metrics.runtime", true, "Export the exporter's own Go runtime and process metrics (go_*, process_*)")
+	snapshotDir              = flag.String("snapshot-dir", "snapshots", "Directory where named snapshots are stored")
+
+	ingestMode   = flag.Bool("ingest", false, "Serve modem data pushed to /api/v1/ingest instead of polling the modem")
+	ingestMaxAge = flag.Duration("ingest-max-age", 5*time.Minute, "Treat pushed data older than this as unavailable (0 = never)")
+	recordDir    = flag.String("record", "", "Save every raw modem response to this directory (default: disabled)")
+	replayDir    = flag.String("replay", "", "Serve modem responses saved with -record from this directory instead of polling the modem")
+
+	webConfigFile       = flag.String("web.config.file", "", "Exporter toolkit web configuration file enabling TLS and/or basic auth on the listener")
+	scrapeTimeoutOffset = flag.Duration("scrape-timeout-offset", 500*time.Millisecond, "Stop polling the modem this long before Prometheus's scrape timeout, when polling on scrape")
+	webOpenMetrics      = flag.Bool("web-openmetrics", false, "Serve the OpenMetrics format to scrapers that ask for it, with _created samples for counters and exemplars linking modem counters to poll traces")
+	webSampleTimestamps = flag.Bool("web-sample-timestamps", false, "In background mode, give every modem sample the time of the poll it came from instead of the scrape time")
+
+	haMode          = flag.String("ha-mode", "none", "High-availability coordination mode: none, file, static or kubernetes")
+	haID            = flag.String("ha-id", "", "Identity of this instance for leader election (default: hostname)")
+	haAdvertiseURL  = flag.String("ha-advertise-url", "", "URL at which peers can reach this instance")
+	haLeaseDuration = flag.Duration("ha-lease-duration", 15*time.Second, "How long leadership is held without renewal")
+	haLeaseFile     = flag.String("ha-lease-file", "", "Lease file on shared storage (file mode)")
+	haPeers         = flag.String("ha-peers", "", "Comma-separated URLs of peer instances (static mode)")
+	haPriority      = flag.Int("ha-priority", 0, "Priority of this instance; highest reachable wins (static mode)")
+	haKubeLease     = flag.String("ha-k8s-lease", "", "Name of the coordination.k8s.io Lease (kubernetes mode)")
+	haKubeNamespace = flag.String("ha-k8s-namespace", "", "Namespace of the Lease (default: pod namespace)")
+	apiTokensFile   = flag.String("api-tokens-file", "", "File of bearer tokens and scopes protecting /api/v1 (default: no authentication)")
+	apiCORSOrigins  = flag.String("api-cors-origins", "", "Comma-separated origins allowed to call /api/v1 from a browser (* for any)")
+	apiCORSHeaders  = flag.String("api-cors-headers", "Authorization,Content-Type", "Comma-separated request headers allowed in cross-origin API calls")
+	webRateLimit    = flag.Float64("web-rate-limit", 0, "Maximum requests per second per client address (0 = unlimited)")
+	webRateBurst    = flag.Int("web-rate-burst", 10, "Requests a client may burst above -web-rate-limit")
+	webMaxScrapes   = flag.Int("web-max-concurrent-scrapes", 0, "Maximum concurrent /metrics requests; excess get 503 (0 = unlimited)")
+	updateCheck     = flag.Bool("update-check", false, "Periodically check GitHub for newer exporter releases")
+	updateInterval  = flag.Duration("update-check-interval", 24*time.Hour, "How often to check for newer releases")
+	haStandby       = flag.String("ha-standby", "cache", "What a standby serves on /metrics: cache or proxy")
+)
+
+// CollectorOptions enables the optional collectors.
+type CollectorOptions struct {
+	// UpstreamServiceFlowEndpoint is the data page holding the upstream
+	// service flow table, or empty to disable collecting it.
+	UpstreamServiceFlowEndpoint string
+	// DownstreamServiceFlowEndpoint is the data page holding the downstream
+	// QoS/service flow table, or empty to disable collecting it.
+	DownstreamServiceFlowEndpoint string
+	// EventLogEndpoint is the data page holding the DOCSIS event log, or
+	// empty to disable collecting it.
+	EventLogEndpoint string
+	// CMInitEndpoint is the data page holding the DOCSIS initialization
+	// steps, or empty to disable collecting them.
+	CMInitEndpoint string
+	// DocsisWANEndpoint is the data page holding the WAN addresses, or
+	// empty to disable collecting them.
+	DocsisWANEndpoint string
+	// CompactLabels keys channel series by channel alone, leaving frequency,
+	// modulation and state to the *_channel_info metrics.
+	CompactLabels bool
+	// Sinks are outputs that receive the data after every poll.
+	Sinks []sink
+	// Concurrency is the maximum number of modem requests in flight during
+	// a poll; values below 1 mean 1.
+	Concurrency int
+	// RebootPolicy reboots the modem on sustained signal degradation; the
+	// zero value never does.
+	RebootPolicy RebootPolicy
+	// Watchdog resets the connections to a modem that keeps failing, and
+	// then reports the exporter degraded; the zero value does neither.
+	Watchdog WatchdogPolicy
+	// Spec holds the signal ranges behind the *_in_spec metrics.
+	Spec SpecRanges
+	// MinPollInterval is the least time between polls made for scrapes when
+	// there is no background poller; scrapes in between are answered from
+	// the previous poll.
+	MinPollInterval time.Duration
+	// CacheTTL is how long the channel series of a page that fails to fetch
+	// keep being served from its last successful poll; zero means until it
+	// is fetched again.
+	CacheTTL time.Duration
+	// RequestMetrics are the metrics the modem's client reports its
+	// requests to; see newRequestMetrics. Without them, none are exported.
+	RequestMetrics *requestMetrics
+	// RTTProbe is the hitron.RTTProbe* probe run before each poll, or empty
+	// for none.
+	RTTProbe string
+	// Strict fails a whole page when any of its values can't be parsed,
+	// instead of leaving out just those values.
+	Strict bool
+	// SampleTimestamps gives the samples the time of the poll they come
+	// from; see -web-sample-timestamps.
+	SampleTimestamps bool
+}
+
+type MetricsCollector struct {
+	labels collector.Labels
+
+	client      *hitron.ModemClient
+	elector     Elector
+	concurrency int
+	spec        SpecRanges
+	strict      bool
+
+	serviceFlows *serviceFlowCollector
+	eventLog     *eventLogCollector
+	cmInit       *cmInitCollector
+	wan          *wanCollector
+	autoReboot   *rebootPolicyCollector
+	watchdog     *watchdogCollector
+	latency      *latencyCollector
+
+	// Downstream metrics
+	downstreamPower          *prometheus.GaugeVec
+	downstreamSNR            *prometheus.GaugeVec
+	downstreamPowerInSpec    *prometheus.GaugeVec
+	downstreamSNRInSpec      *prometheus.GaugeVec
+	downstreamFreq           *prometheus.GaugeVec
+	downstreamCorrectables   *prometheus.Desc
+	downstreamUncorrectables *prometheus.Desc
+	downstreamOctets         *prometheus.Desc
+	downstreamModulationBits *prometheus.GaugeVec
+
+	// Upstream metrics
+	upstreamPower          *prometheus.GaugeVec
+	upstreamPowerInSpec    *prometheus.GaugeVec
+	upstreamFreq           *prometheus.GaugeVec
+	upstreamSymbolRate     *prometheus.GaugeVec
+	upstreamBandwidth      *prometheus.GaugeVec
+	upstreamScdmaMode      *prometheus.GaugeVec
+	upstreamScdma          *prometheus.GaugeVec
+	upstreamModulationBits *prometheus.GaugeVec
+
+	// OFDM Downstream metrics
+	ofdmDownstreamPower          *prometheus.GaugeVec
+	ofdmDownstreamPowerInSpec    *prometheus.GaugeVec
+	ofdmDownstreamSNR            *prometheus.GaugeVec
+	ofdmDownstreamFreq           *prometheus.GaugeVec
+	ofdmDownstreamWidth          *prometheus.GaugeVec
+	ofdmDownstreamSpacing        *prometheus.GaugeVec
+	ofdmDownstreamModulationBits *prometheus.GaugeVec
+	ofdmDownstreamCorrectables   *prometheus.Desc
+	ofdmDownstreamUncorrectables *prometheus.Desc
+	ofdmDownstreamOctets         *prometheus.Desc
+	ofdmDownstreamLocks          *prometheus.GaugeVec
+	ofdmDownstreamLockFlaps      *prometheus.CounterVec
+	// ofdmLocks is each receiver's lock state in the previous poll, keyed
+	// receive/lock_type, for counting flaps.
+	ofdmLocks map[string]float64
+
+	// OFDM Upstream metrics
+	ofdmUpstreamPower       *prometheus.GaugeVec
+	ofdmUpstreamPowerInSpec *prometheus.GaugeVec
+	ofdmUpstreamFreq        *prometheus.GaugeVec
+	ofdmUpstreamBandwidth   *prometheus.GaugeVec
+	ofdmUpstreamWidth       *prometheus.GaugeVec
+	ofdmUpstreamState       *prometheus.GaugeVec
+
+	// Link status metrics
+	linkStatus           prometheus.Gauge
+	linkInfo             *prometheus.GaugeVec
+	linkSpeed            prometheus.Gauge
+	linkSpeedBits        prometheus.Gauge
+	linkSpeedParseErrors prometheus.Counter
+
+	// Per-channel frequency, modulation and state, so that they can be left
+	// off the other series
+	downstreamInfo     *prometheus.GaugeVec
+	upstreamInfo       *prometheus.GaugeVec
+	ofdmDownstreamInfo *prometheus.GaugeVec
+	ofdmUpstreamInfo   *prometheus.GaugeVec
+	// upstreamType lists the SC-QAM and operating OFDMA upstream channels
+	// together, by type.
+	upstreamType *prometheus.GaugeVec
+
+	// System metrics
+	systemInfo   *prometheus.GaugeVec
+	systemUptime prometheus.Gauge
+	systemTime   prometheus.Gauge
+	clockDrift   prometheus.Gauge
+	// bootTime is when the modem last booted, from its uptime, to notice
+	// reboots.
+	bootTime time.Time
+	reboots  prometheus.Counter
+
+	// Summaries across channels, for alert rules that don't need per-channel
+	// detail
+	downstreamBonded          *prometheus.GaugeVec
+	upstreamBonded            *prometheus.GaugeVec
+	downstreamChannels        *prometheus.GaugeVec
+	upstreamChannels          *prometheus.GaugeVec
+	ofdmDownstreamChannels    *prometheus.GaugeVec
+	ofdmUpstreamChannels      *prometheus.GaugeVec
+	downstreamSNRSummary      *prometheus.GaugeVec
+	downstreamPowerSummary    *prometheus.GaugeVec
+	upstreamPowerSummary      *prometheus.GaugeVec
+	uncorrectablesTotalDesc   *prometheus.Desc
+	uncorrectablesTotalResets *counterResets
+	uncorrectablesTotal       []modemCounter
+
+	// The last seen versions, to notice firmware upgrades.
+	hwVersion, swVersion string
+	firmwareChanges      prometheus.Counter
+	firmwareVersion      *prometheus.GaugeVec
+
+	// mu is held for writing while a poll replaces the channel series, so a
+	// scrape never sees a half-rebuilt table.
+	mu sync.RWMutex
+
+	// status is the parsed data behind the metrics, for /api/v1/status and
+	// the sinks.
+	status hitron.Snapshot
+	sinks  []sink
+	// tags are the modem's static labels, passed to the sinks.
+	tags map[string]string
+	// counterStore, if set, keeps the counter trackers and is saved after
+	// every poll.
+	counterStore *counterStore
+
+	// Counters kept by the modem, as of the last successful poll
+	dsResets       *counterResets
+	ofdmDsResets   *counterResets
+	dsCounters     []modemCounter
+	ofdmDsCounters []modemCounter
+	trafficDesc    *prometheus.Desc
+	trafficResets  *counterResets
+	traffic        []modemCounter
+
+	// polled is when the last poll started. With sampleTimestamps the
+	// metrics carry it as their timestamp.
+	polled           time.Time
+	sampleTimestamps bool
+
+	// Poller metrics
+	up               prometheus.Gauge
+	endpointUp       *prometheus.GaugeVec
+	lastPoll         prometheus.Gauge
+	modemAddress     *prometheus.Desc
+	circuitOpen      prometheus.Gauge
+	authRequired     prometheus.Gauge
+	collectDuration  prometheus.Gauge
+	endpointDuration *prometheus.GaugeVec
+	endpointErrors   *prometheus.CounterVec
+	parseErrors      *prometheus.CounterVec
+	cachedScrapes    prometheus.Counter
+	dataStale        *prometheus.GaugeVec
+	lastSuccess      *prometheus.GaugeVec
+	dataAge          *prometheus.Desc
+
+	// cacheTTL and fetched, the time each page was last fetched, decide when
+	// a failing page's series are dropped.
+	cacheTTL time.Duration
+	fetched  map[hitron.Page]time.Time
+
+	// background is set once Run is polling the modem, after which Collect
+	// only serves the cached values.
+	background atomic.Bool
+	// polledOK is set once a poll has fetched every page.
+	polledOK atomic.Bool
+
+	// pollMu serializes polls for scrapes while minPollInterval is set, and
+	// guards lastPollStart.
+	pollMu          sync.Mutex
+	minPollInterval time.Duration
+	lastPollStart   time.Time
+}
+
+func NewMetricsCollector(client *hitron.ModemClient, elector Elector, opts CollectorOptions) *MetricsCollector {
+	labels := collector.Labels{Compact: opts.CompactLabels}
+	c := &MetricsCollector{
+		labels:      labels,
+		client:      client,
+		elector:     elector,
+		concurrency: max(opts.Concurrency, 1),
+		spec:        opts.Spec,
+		strict:      opts.Strict,
+
+		sampleTimestamps: opts.SampleTimestamps,
+
+		minPollInterval: opts.MinPollInterval,
+		cacheTTL:        opts.CacheTTL,
+		fetched:         map[hitron.Page]time.Time{},
+		sinks:           opts.Sinks,
+		dsResets:        newCounterResets(),
+		ofdmDsResets:    newCounterResets(),
+		trafficResets:   newCounterResets(),
+
+		uncorrectablesTotalResets: newCounterResets(),
+
+		serviceFlows: newServiceFlowCollector(opts.UpstreamServiceFlowEndpoint, opts.DownstreamServiceFlowEndpoint),
+		eventLog:     newEventLogCollector(opts.EventLogEndpoint),
+		cmInit:       newCMInitCollector(opts.CMInitEndpoint),
+		wan:          newWANCollector(opts.DocsisWANEndpoint),
+		latency:      newLatencyCollector(opts.RequestMetrics, opts.RTTProbe),
+		autoReboot:   newRebootPolicyCollector(opts.RebootPolicy),
+		watchdog:     newWatchdogCollector(opts.Watchdog),
+
+		downstreamPower: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "downstream_power_dbmv",
+				Help: "Downstream channel power level in dBmV",
+			},
+			labels.Names("channel_id", "frequency", "modulation"),
+		),
+
+		downstreamSNR: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "downstream_snr_db",
+				Help: "Downstream channel signal-to-noise ratio in dB",
+			},
+			labels.Names("channel_id", "frequency", "modulation"),
+		),
+
+		downstreamPowerInSpec: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "downstream_power_in_spec",
+				Help: "Whether the downstream channel power is within the -spec.downstream-power-* range (1 = yes, 0 = no)",
+			},
+			labels.Names("channel_id", "frequency", "modulation"),
+		),
+
+		downstreamSNRInSpec: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "downstream_snr_in_spec",
+				Help: "Whether the downstream channel SNR is within -spec.snr-min (1 = yes, 0 = no)",
+			},
+			labels.Names("channel_id", "frequency", "modulation"),
+		),
+
+		downstreamFreq: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "downstream_frequency_hz",
+				Help: "Downstream channel frequency in Hz",
+			},
+			labels.Names("channel_id", "modulation"),
+		),
+
+		downstreamCorrectables: prometheus.NewDesc(
+			"downstream_correctables",
+			"Number of correctable errors on downstream channel",
+			labels.Names("channel_id", "frequency", "modulation"), nil,
+		),
+
+		downstreamUncorrectables: prometheus.NewDesc(
+			"downstream_uncorrectables",
+			"Number of uncorrectable errors on downstream channel",
+			labels.Names("channel_id", "frequency", "modulation"), nil,
+		),
+
+		downstreamOctets: prometheus.NewDesc(
+			"downstream_octets_bytes",
+			"Number of octets (bytes) received on downstream channel",
+			labels.Names("channel_id", "frequency", "modulation"), nil,
+		),
+
+		downstreamModulationBits: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "downstream_modulation_bits",
+				Help: "Downstream channel modulation order in bits per symbol (e.g. 8 for QAM256)",
+			},
+			[]string{"channel_id"},
+		),
+
+		upstreamPower: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "upstream_power_dbmv",
+				Help: "Upstream channel power level in dBmV",
+			},
+			labels.Names("channel_id", "frequency", "modulation"),
+		),
+
+		upstreamPowerInSpec: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "upstream_power_in_spec",
+				Help: "Whether the upstream channel power is within the -spec.upstream-power-* range (1 = yes, 0 = no)",
+			},
+			labels.Names("channel_id", "frequency", "modulation"),
+		),
+
+		upstreamFreq: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "upstream_frequency_hz",
+				Help: "Upstream channel frequency in Hz",
+			},
+			labels.Names("channel_id", "modulation"),
+		),
+
+		upstreamSymbolRate: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "upstream_symbol_rate",
+				Help: "Deprecated: the channel width as the modem reports it, despite the name; use upstream_bandwidth_hz",
+			},
+			labels.Names("channel_id", "frequency", "modulation"),
+		),
+
+		upstreamBandwidth: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "upstream_bandwidth_hz",
+				Help: "Upstream channel width in Hz",
+			},
+			labels.Names("channel_id", "frequency", "modulation"),
+		),
+
+		upstreamScdmaMode: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "upstream_scdma_mode_info",
+				Help: "Upstream channel SCDMA mode reported by the modem (always 1; deprecated, use upstream_scdma)",
+			},
+			[]string{"channel_id", "scdma_mode"},
+		),
+
+		upstreamScdma: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "upstream_scdma",
+				Help: "Whether the upstream channel runs S-CDMA (1) rather than ATDMA (0)",
+			},
+			[]string{"channel_id"},
+		),
+
+		upstreamModulationBits: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "upstream_modulation_bits",
+				Help: "Upstream channel modulation order in bits per symbol (e.g. 6 for QAM64)",
+			},
+			[]string{"channel_id"},
+		),
+
+		downstreamInfo: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "downstream_channel_info",
+				Help: "Frequency and modulation of each downstream channel (always 1)",
+			},
+			[]string{"channel_id", "frequency", "modulation"},
+		),
+
+		upstreamInfo: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "upstream_channel_info",
+				Help: "Frequency and modulation of each upstream channel (always 1)",
+			},
+			[]string{"channel_id", "frequency", "modulation"},
+		),
+
+		ofdmDownstreamInfo: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_downstream_channel_info",
+				Help: "Frequency and FFT type of each OFDM downstream channel (always 1)",
+			},
+			[]string{"receive", "frequency", "fft_type"},
+		),
+
+		ofdmUpstreamInfo: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_upstream_channel_info",
+				Help: "Frequency and state of each OFDM upstream channel (always 1)",
+			},
+			[]string{"usch_index", "frequency", "state"},
+		),
+
+		upstreamType: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "upstream_channel_type_info",
+				Help: "Type of each upstream channel, SC-QAM or OFDMA, covering the QAM channels and the operating OFDMA ones (always 1)",
+			},
+			labels.Names("channel_id", "frequency", "type"),
+		),
+
+		systemInfo: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "system_info",
+				Help: "System information",
+			},
+			[]string{"hardware_version", "software_version", "serial_number"},
+		),
+
+		// OFDM Downstream metrics
+		ofdmDownstreamPower: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_downstream_power_dbmv",
+				Help: "OFDM downstream channel power level in dBmV",
+			},
+			labels.Names("receive", "frequency", "fft_type"),
+		),
+
+		ofdmDownstreamPowerInSpec: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_downstream_power_in_spec",
+				Help: "Whether the PLC power of a locked OFDM downstream channel is within the -spec.downstream-power-* range (1 = yes, 0 = no)",
+			},
+			labels.Names("receive", "frequency", "fft_type"),
+		),
+
+		ofdmDownstreamSNR: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_downstream_snr_db",
+				Help: "OFDM downstream channel signal-to-noise ratio in dB",
+			},
+			labels.Names("receive", "frequency", "fft_type"),
+		),
+
+		ofdmDownstreamFreq: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_downstream_frequency_hz",
+				Help: "OFDM downstream channel frequency in Hz",
+			},
+			labels.Names("receive", "fft_type"),
+		),
+
+		ofdmDownstreamWidth: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_downstream_channel_width_hz",
+				Help: "OFDM downstream channel width (FFT size times subcarrier spacing) in Hz",
+			},
+			labels.Names("receive", "fft_type"),
+		),
+
+		ofdmDownstreamSpacing: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_downstream_subcarrier_spacing_hz",
+				Help: "OFDM downstream subcarrier spacing in Hz",
+			},
+			labels.Names("receive", "fft_type"),
+		),
+
+		ofdmDownstreamModulationBits: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_downstream_modulation_bits",
+				Help: "Highest OFDM modulation order, in bits per subcarrier, the downstream channel's SNR supports under DOCSIS 3.1 (e.g. 12 for QAM4096)",
+			},
+			[]string{"receive"},
+		),
+
+		ofdmDownstreamCorrectables: prometheus.NewDesc(
+			"ofdm_downstream_correctables",
+			"Number of correctable errors on OFDM downstream channel",
+			labels.Names("receive", "frequency", "fft_type"), nil,
+		),
+
+		ofdmDownstreamUncorrectables: prometheus.NewDesc(
+			"ofdm_downstream_uncorrectables",
+			"Number of uncorrectable errors on OFDM downstream channel",
+			labels.Names("receive", "frequency", "fft_type"), nil,
+		),
+
+		ofdmDownstreamOctets: prometheus.NewDesc(
+			"ofdm_downstream_octets_bytes",
+			"Number of octets (bytes) received on OFDM downstream channel",
+			labels.Names("receive", "frequency", "fft_type"), nil,
+		),
+
+		ofdmDownstreamLocks: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_downstream_locks",
+				Help: "OFDM downstream channel lock status (1 = locked, 0 = unlocked)",
+			},
+			labels.Names("receive", "frequency", "lock_type"),
+		),
+
+		ofdmDownstreamLockFlaps: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "ofdm_downstream_lock_flaps_total",
+				Help: "Number of times an OFDM downstream lock changed state between polls",
+			},
+			labels.Names("receive", "lock_type"),
+		),
+		ofdmLocks: map[string]float64{},
+
+		// OFDM Upstream metrics
+		ofdmUpstreamPower: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_upstream_power_dbmv",
+				Help: "OFDM upstream channel power level in dBmV",
+			},
+			labels.Names("usch_index", "frequency", "state"),
+		),
+
+		ofdmUpstreamPowerInSpec: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_upstream_power_in_spec",
+				Help: "Whether the power of an operating OFDMA upstream channel is within the -spec.upstream-power-* range (1 = yes, 0 = no)",
+			},
+			labels.Names("usch_index", "frequency", "state"),
+		),
+
+		ofdmUpstreamFreq: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_upstream_frequency_hz",
+				Help: "OFDM upstream channel frequency in Hz",
+			},
+			labels.Names("usch_index", "state"),
+		),
+
+		ofdmUpstreamBandwidth: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_upstream_bandwidth_mhz",
+				Help: "OFDM upstream channel bandwidth in MHz (deprecated, use the _hz gauge)",
+			},
+			labels.Names("usch_index", "frequency", "state"),
+		),
+
+		ofdmUpstreamWidth: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_upstream_bandwidth_hz",
+				Help: "OFDM upstream channel bandwidth in Hz",
+			},
+			labels.Names("usch_index", "frequency", "state"),
+		),
+
+		ofdmUpstreamState: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_upstream_state",
+				Help: "OFDM upstream channel state (1 = operate, 0 = disabled)",
+			},
+			labels.Names("usch_index", "frequency"),
+		),
+
+		// Link status metrics
+		linkStatus: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "link_status",
+				Help: "Link status (1 = up, 0 = down)",
+			},
+		),
+
+		linkInfo: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "link_info",
+				Help: "Ethernet link duplex as reported by the modem (always 1)",
+			},
+			[]string{"duplex"},
+		),
+
+		linkSpeed: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "link_speed_mbps",
+				Help: "Link speed in Mbps",
+			},
+		),
+
+		linkSpeedBits: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "link_speed_bits_per_second",
+				Help: "Link speed in bits per second",
+			},
+		),
+
+		linkSpeedParseErrors: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "link_speed_parse_errors_total",
+				Help: "Number of times the reported link speed could not be parsed",
+			},
+		),
+
+		up: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "up",
+				Help: "Whether the last poll reached the modem (1 if any endpoint could be fetched)",
+			},
+		),
+
+		endpointUp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "endpoint_up",
+				Help: "Whether each modem endpoint could be fetched in the last poll",
+			},
+			[]string{"endpoint"},
+		),
+
+		systemUptime: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "system_uptime_seconds",
+				Help: "Time since the modem last booted, in seconds",
+			},
+		),
+
+		trafficDesc: prometheus.NewDesc(
+			"traffic_bytes",
+			"Bytes the modem has counted on its WAN and LAN side, by interface and direction",
+			[]string{"interface", "direction"}, nil,
+		),
+
+		reboots: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "modem_reboots_total",
+				Help: "Number of modem reboots noticed from its uptime going backwards",
+			},
+		),
+
+		systemTime: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "system_time_seconds",
+				Help: "The modem's clock as a Unix time, read in its timezone setting",
+			},
+		),
+
+		clockDrift: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "clock_drift_seconds",
+				Help: "How far the modem's clock is ahead of the exporter host's (negative if behind)",
+			},
+		),
+
+		downstreamBonded: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "downstream_bonded_channels",
+				Help: "Number of bonded downstream channels; OFDM channels count once their PLC is locked",
+			},
+			[]string{"type"},
+		),
+
+		upstreamBonded: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "upstream_bonded_channels",
+				Help: "Number of bonded upstream channels; OFDMA channels count while in the OPERATE state",
+			},
+			[]string{"type"},
+		),
+
+		downstreamChannels: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "downstream_channels",
+				Help: "Number of downstream QAM channels by modulation",
+			},
+			[]string{"modulation"},
+		),
+
+		upstreamChannels: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "upstream_channels",
+				Help: "Number of upstream QAM channels by modulation",
+			},
+			[]string{"modtype"},
+		),
+
+		ofdmDownstreamChannels: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_downstream_channels",
+				Help: "Number of downstream OFDM channels by PLC lock status",
+			},
+			[]string{"plc_lock"},
+		),
+
+		ofdmUpstreamChannels: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ofdm_upstream_channels",
+				Help: "Number of upstream OFDMA channels by state",
+			},
+			[]string{"state"},
+		),
+
+		downstreamSNRSummary: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "downstream_snr_summary_db",
+				Help: "Minimum, maximum and average SNR across downstream QAM channels in dB",
+			},
+			[]string{"stat"},
+		),
+
+		downstreamPowerSummary: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "downstream_power_summary_dbmv",
+				Help: "Minimum, maximum and average power across downstream QAM channels in dBmV",
+			},
+			[]string{"stat"},
+		),
+
+		upstreamPowerSummary: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "upstream_power_summary_dbmv",
+				Help: "Minimum, maximum and average power across upstream QAM channels in dBmV",
+			},
+			[]string{"stat"},
+		),
+
+		uncorrectablesTotalDesc: prometheus.NewDesc(
+			"total_uncorrectables",
+			"Number of uncorrectable errors summed over all QAM and OFDM downstream channels",
+			nil, nil,
+		),
+
+		firmwareChanges: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "firmware_changes_total",
+				Help: "Times the modem's hardware or software version changed while the exporter was running",
+			},
+		),
+
+		firmwareVersion: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "firmware_version_timestamp_seconds",
+				Help: "Unix time at which the modem's current hardware and software version was first seen",
+			},
+			[]string{"hardware_version", "software_version"},
+		),
+
+		modemAddress: prometheus.NewDesc(
+			"modem_address_info",
+			"Always 1, with the scheme and port requests to the modem currently go to, which differ from -modem-host after falling back",
+			[]string{"scheme", "port"}, nil,
+		),
+
+		lastPoll: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "last_poll_timestamp_seconds",
+				Help: "Unix time at which the modem was last polled",
+			},
+		),
+
+		circuitOpen: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "exporter_circuit_open",
+				Help: "Whether requests to the modem are suspended because it stopped answering (1 = suspended)",
+			},
+		),
+
+		authRequired: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "auth_required",
+				Help: "Whether the modem answered the last poll with its login page, even after logging in if credentials are set (1 = login required)",
+			},
+		),
+
+		collectDuration: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "exporter_collect_duration_seconds",
+				Help: "Time taken by the last poll of the modem",
+			},
+		),
+
+		endpointDuration: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "exporter_endpoint_scrape_duration_seconds",
+				Help: "Time taken to fetch and parse each modem endpoint in the last poll",
+			},
+			[]string{"endpoint"},
+		),
+
+		endpointErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "exporter_endpoint_errors_total",
+				Help: "Number of failed fetches of each modem endpoint",
+			},
+			[]string{"endpoint"},
+		),
+
+		dataStale: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "data_stale",
+				Help: "Whether the metrics from each modem endpoint are left over from an earlier poll because the last one failed (1 = stale)",
+			},
+			[]string{"endpoint"},
+		),
+
+		lastSuccess: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "last_success_timestamp_seconds",
+				Help: "Unix time at which each modem endpoint was last fetched successfully",
+			},
+			[]string{"endpoint"},
+		),
+
+		dataAge: prometheus.NewDesc(
+			"data_age_seconds",
+			"Time since each modem endpoint was last fetched successfully, as of the scrape",
+			[]string{"endpoint"}, nil,
+		),
+
+		cachedScrapes: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "exporter_cached_scrapes_total",
+				Help: "Number of scrapes answered from the previous poll because of -modem-min-poll-interval",
+			},
+		),
+
+		parseErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "parse_errors_total",
+				Help: "Number of values the modem reported that were not numbers, and so were left out",
+			},
+			[]string{"endpoint", "field"},
+		),
+	}
+	// Start every endpoint at zero so rate() works before the first failure
+	for _, endpoint := range client.Endpoints() {
+		c.endpointErrors.WithLabelValues(endpoint)
+	}
+	return c
+}
+
+func (c *MetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.downstreamPower.Describe(ch)
+	c.downstreamSNR.Describe(ch)
+	c.downstreamPowerInSpec.Describe(ch)
+	c.downstreamSNRInSpec.Describe(ch)
+	c.downstreamFreq.Describe(ch)
+	ch <- c.downstreamCorrectables
+	ch <- c.downstreamUncorrectables
+	ch <- c.downstreamOctets
+	c.downstreamModulationBits.Describe(ch)
+	c.upstreamPower.Describe(ch)
+	c.upstreamPowerInSpec.Describe(ch)
+	c.upstreamFreq.Describe(ch)
+	c.upstreamSymbolRate.Describe(ch)
+	c.upstreamBandwidth.Describe(ch)
+	c.upstreamScdmaMode.Describe(ch)
+	c.upstreamScdma.Describe(ch)
+	c.upstreamModulationBits.Describe(ch)
+	c.ofdmDownstreamPower.Describe(ch)
+	c.ofdmDownstreamPowerInSpec.Describe(ch)
+	c.ofdmDownstreamSNR.Describe(ch)
+	c.ofdmDownstreamFreq.Describe(ch)
+	c.ofdmDownstreamWidth.Describe(ch)
+	c.ofdmDownstreamSpacing.Describe(ch)
+	c.ofdmDownstreamModulationBits.Describe(ch)
+	ch <- c.ofdmDownstreamCorrectables
+	ch <- c.ofdmDownstreamUncorrectables
+	ch <- c.ofdmDownstreamOctets
+	c.ofdmDownstreamLocks.Describe(ch)
+	c.ofdmDownstreamLockFlaps.Describe(ch)
+	c.ofdmUpstreamPower.Describe(ch)
+	c.ofdmUpstreamPowerInSpec.Describe(ch)
+	c.ofdmUpstreamFreq.Describe(ch)
+	c.ofdmUpstreamBandwidth.Describe(ch)
+	c.ofdmUpstreamWidth.Describe(ch)
+	c.ofdmUpstreamState.Describe(ch)
+	c.linkStatus.Describe(ch)
+	c.linkInfo.Describe(ch)
+	c.linkSpeed.Describe(ch)
+	c.linkSpeedBits.Describe(ch)
+	c.linkSpeedParseErrors.Describe(ch)
+	c.downstreamInfo.Describe(ch)
+	c.upstreamInfo.Describe(ch)
+	c.ofdmDownstreamInfo.Describe(ch)
+	c.ofdmUpstreamInfo.Describe(ch)
+	c.upstreamType.Describe(ch)
+	c.systemInfo.Describe(ch)
+	c.systemUptime.Describe(ch)
+	ch <- c.trafficDesc
+	c.reboots.Describe(ch)
+	c.systemTime.Describe(ch)
+	c.clockDrift.Describe(ch)
+	c.downstreamBonded.Describe(ch)
+	c.downstreamChannels.Describe(ch)
+	c.upstreamChannels.Describe(ch)
+	c.ofdmDownstreamChannels.Describe(ch)
+	c.ofdmUpstreamChannels.Describe(ch)
+	c.upstreamBonded.Describe(ch)
+	c.downstreamSNRSummary.Describe(ch)
+	c.downstreamPowerSummary.Describe(ch)
+	c.upstreamPowerSummary.Describe(ch)
+	ch <- c.uncorrectablesTotalDesc
+	c.firmwareChanges.Describe(ch)
+	c.firmwareVersion.Describe(ch)
+	c.up.Describe(ch)
+	c.endpointUp.Describe(ch)
+	c.lastPoll.Describe(ch)
+	ch <- c.modemAddress
+	c.circuitOpen.Describe(ch)
+	c.authRequired.Describe(ch)
+	c.collectDuration.Describe(ch)
+	c.endpointDuration.Describe(ch)
+	c.endpointErrors.Describe(ch)
+	c.parseErrors.Describe(ch)
+	c.cachedScrapes.Describe(ch)
+	c.dataStale.Describe(ch)
+	c.lastSuccess.Describe(ch)
+	ch <- c.dataAge
+	c.serviceFlows.describe(ch)
+	c.eventLog.describe(ch)
+	c.cmInit.describe(ch)
+	c.wan.describe(ch)
+	c.autoReboot.describe(ch)
+	c.watchdog.describe(ch)
+	c.latency.describe(ch)
+}
+
+func (c *MetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collect(context.Background(), ch)
+}
+
+// collect is Collect with a context for the poll, which happens on every
+// scrape when there is no background poller.
+func (c *MetricsCollector) collect(ctx context.Context, ch chan<- prometheus.Metric) {
+	c.pollOnDemand(ctx)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.sampleTimestamps && !c.polled.IsZero() {
+		var done func()
+		ch, done = withTimestamp(ch, c.polled)
+		defer done()
+	}
+
+	// Collect all metrics
+	c.downstreamPower.Collect(ch)
+	c.downstreamSNR.Collect(ch)
+	c.downstreamPowerInSpec.Collect(ch)
+	c.downstreamSNRInSpec.Collect(ch)
+	c.downstreamFreq.Collect(ch)
+	c.downstreamModulationBits.Collect(ch)
+	for _, counter := range c.dsCounters {
+		ch <- counter.metric()
+	}
+	for _, counter := range c.ofdmDsCounters {
+		ch <- counter.metric()
+	}
+	c.upstreamPower.Collect(ch)
+	c.upstreamPowerInSpec.Collect(ch)
+	c.upstreamFreq.Collect(ch)
+	c.upstreamSymbolRate.Collect(ch)
+	c.upstreamBandwidth.Collect(ch)
+	c.upstreamScdmaMode.Collect(ch)
+	c.upstreamScdma.Collect(ch)
+	c.upstreamModulationBits.Collect(ch)
+	c.ofdmDownstreamPower.Collect(ch)
+	c.ofdmDownstreamPowerInSpec.Collect(ch)
+	c.ofdmDownstreamSNR.Collect(ch)
+	c.ofdmDownstreamFreq.Collect(ch)
+	c.ofdmDownstreamWidth.Collect(ch)
+	c.ofdmDownstreamSpacing.Collect(ch)
+	c.ofdmDownstreamModulationBits.Collect(ch)
+	c.ofdmDownstreamLocks.Collect(ch)
+	c.ofdmDownstreamLockFlaps.Collect(ch)
+	c.ofdmUpstreamPower.Collect(ch)
+	c.ofdmUpstreamPowerInSpec.Collect(ch)
+	c.ofdmUpstreamFreq.Collect(ch)
+	c.ofdmUpstreamBandwidth.Collect(ch)
+	c.ofdmUpstreamWidth.Collect(ch)
+	c.ofdmUpstreamState.Collect(ch)
+	c.linkStatus.Collect(ch)
+	c.linkInfo.Collect(ch)
+	c.linkSpeed.Collect(ch)
+	c.linkSpeedBits.Collect(ch)
+	c.linkSpeedParseErrors.Collect(ch)
+	c.downstreamInfo.Collect(ch)
+	c.upstreamInfo.Collect(ch)
+	c.ofdmDownstreamInfo.Collect(ch)
+	c.ofdmUpstreamInfo.Collect(ch)
+	c.upstreamType.Collect(ch)
+	c.systemInfo.Collect(ch)
+	c.systemUptime.Collect(ch)
+	for _, counter := range c.traffic {
+		ch <- counter.metric()
+	}
+	c.reboots.Collect(ch)
+	c.systemTime.Collect(ch)
+	c.clockDrift.Collect(ch)
+	c.downstreamBonded.Collect(ch)
+	c.downstreamChannels.Collect(ch)
+	c.upstreamChannels.Collect(ch)
+	c.ofdmDownstreamChannels.Collect(ch)
+	c.ofdmUpstreamChannels.Collect(ch)
+	c.upstreamBonded.Collect(ch)
+	c.downstreamSNRSummary.Collect(ch)
+	c.downstreamPowerSummary.Collect(ch)
+	c.upstreamPowerSummary.Collect(ch)
+	for _, counter := range c.uncorrectablesTotal {
+		ch <- counter.metric()
+	}
+	c.firmwareChanges.Collect(ch)
+	c.firmwareVersion.Collect(ch)
+	c.up.Collect(ch)
+	c.endpointUp.Collect(ch)
+	c.lastPoll.Collect(ch)
+	if u, err := url.Parse(c.client.Address()); err == nil {
+		port := u.Port()
+		if port == "" {
+			port = map[string]string{"http": "80", "https": "443"}[u.Scheme]
+		}
+		ch <- prometheus.MustNewConstMetric(c.modemAddress, prometheus.GaugeValue, 1, u.Scheme, port)
+	}
+	c.circuitOpen.Collect(ch)
+	c.authRequired.Collect(ch)
+	c.collectDuration.Collect(ch)
+	c.endpointDuration.Collect(ch)
+	c.endpointErrors.Collect(ch)
+	c.parseErrors.Collect(ch)
+	c.cachedScrapes.Collect(ch)
+	c.dataStale.Collect(ch)
+	c.lastSuccess.Collect(ch)
+	now := time.Now()
+	for page, fetched := range c.fetched {
+		ch <- prometheus.MustNewConstMetric(c.dataAge, prometheus.GaugeValue, now.Sub(fetched).Seconds(), c.client.Endpoint(page))
+	}
+	c.serviceFlows.collect(ch)
+	c.eventLog.collect(ch)
+	c.cmInit.collect(ch)
+	c.wan.collect(ch)
+	c.autoReboot.collect(ch)
+	c.watchdog.collect(ch)
+	c.latency.collect(ch)
+}
+
+// isLeader reports whether this instance may poll the modem. Standby
+// instances re-export the values last seen while they were leader.
+func (c *MetricsCollector) isLeader() bool {
+	return c.elector == nil || c.elector.IsLeader()
+}
+
+// pollOnDemand polls the modem for a scrape when there is no background
+// poller. With a minimum poll interval, scrapes that arrive sooner than that
+// after the previous poll, including ones that waited for a poll in flight,
+// are answered from it instead.
+func (c *MetricsCollector) pollOnDemand(ctx context.Context) {
+	if c.background.Load() || !c.isLeader() {
+		return
+	}
+	if c.minPollInterval <= 0 {
+		c.poll(ctx)
+		return
+	}
+	c.pollMu.Lock()
+	defer c.pollMu.Unlock()
+	if !c.lastPollStart.IsZero() && time.Since(c.lastPollStart) < c.minPollInterval {
+		c.cachedScrapes.Inc()
+		return
+	}
+	c.lastPollStart = time.Now()
+	c.poll(ctx)
+}
+
+// Run polls the modem every interval until ctx is cancelled, so scrapes are
+// answered from the most recent poll instead of hitting the modem. With
+// jitter, a fraction from 0 to 1, each wait is randomized by up to that
+// fraction of interval either way, and the first poll waits a random part of
+// it, so that exporters and modems started together don't poll in lockstep.
+func (c *MetricsCollector) Run(ctx context.Context, interval time.Duration, jitter float64) {
+	c.background.Store(true)
+	t := time.NewTimer(time.Duration(rand.Float64() * jitter * float64(interval)))
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		start := time.Now()
+		if c.isLeader() {
+			c.poll(ctx)
+		}
+		// Waits are measured from the start of each poll, like a ticker's.
+		next := float64(interval) * (1 + jitter*(2*rand.Float64()-1))
+		t.Reset(time.Until(start.Add(time.Duration(next))))
+	}
+}
+
+// observe runs get for endpoint and records its duration and any error.
+func (c *MetricsCollector) observe(endpoint string, get func() error) error {
+	start := time.Now()
+	err := get()
+	c.endpointDuration.WithLabelValues(endpoint).Set(time.Since(start).Seconds())
+	if err != nil {
+		c.endpointErrors.WithLabelValues(endpoint).Inc()
+		c.endpointUp.WithLabelValues(endpoint).Set(0)
+	} else {
+		c.endpointUp.WithLabelValues(endpoint).Set(1)
+	}
+	return err
+}
+
+// parser parses the values of one page, counting the ones that aren't
+// numbers in hitron_parse_errors_total.
+func (c *MetricsCollector) parser(page hitron.Page) collector.Parser {
+	endpoint := c.client.Endpoint(page)
+	return collector.NewParser(endpoint, c.parseErrors.MustCurryWith(prometheus.Labels{"endpoint": endpoint}))
+}
+
+// update polls the modem and refreshes all gauges.
+func (c *MetricsCollector) update(ctx context.Context) {
+	ctx, span := tracer.Start(ctx, "poll", trace.WithAttributes(attribute.String("modem", c.client.BaseURL())))
+	defer span.End()
+	start := time.Now()
+	c.latency.update(ctx, c.client)
+	c.client.DetectModel(ctx)
+	c.client.PrefetchCombined(ctx)
+
+	// Fetch the endpoints in parallel. Each one fails independently, so the
+	// group is only used to bound concurrency and errors are kept per page.
+	var (
+		dsInfo     []hitron.DownstreamInfo
+		usInfo     []hitron.UpstreamInfo
+		ofdmDsInfo []hitron.OFDMDownstreamInfo
+		ofdmUsInfo []hitron.OFDMUpstreamInfo
+		linkInfo   *hitron.LinkStatus
+		sysInfo    *hitron.SystemInfo
+		sysFetched time.Time
+
+		dsErr, usErr, ofdmDsErr, ofdmUsErr, linkErr, sysErr error
+	)
+	var g errgroup.Group
+	g.SetLimit(c.concurrency)
+	// Pages the modem's model doesn't serve are skipped and keep a nil error.
+	fetch := func(page hitron.Page, errp *error, get func() error) {
+		endpoint := c.client.Endpoint(page)
+		if endpoint == "" {
+			return
+		}
+		g.Go(func() error {
+			*errp = c.observe(endpoint, get)
+			return nil
+		})
+	}
+	// In strict mode a page with a value that doesn't parse fails as a
+	// whole, and its data is dropped so that nothing of it is exported:
+	// trackFetched expires the channel pages' previous series too, while
+	// the link and system pages keep theirs, marked stale.
+	fetch(hitron.PageDownstream, &dsErr, func() (err error) {
+		dsInfo, err = c.client.GetDownstreamInfo(ctx)
+		if err == nil && c.strict {
+			if err = c.checkDownstream(dsInfo); err != nil {
+				dsInfo = nil
+			}
+		}
+		return err
+	})
+	fetch(hitron.PageUpstream, &usErr, func() (err error) {
+		usInfo, err = c.client.GetUpstreamInfo(ctx)
+		if err == nil && c.strict {
+			if err = c.checkUpstream(usInfo); err != nil {
+				usInfo = nil
+			}
+		}
+		return err
+	})
+	fetch(hitron.PageOFDMDownstream, &ofdmDsErr, func() (err error) {
+		ofdmDsInfo, err = c.client.GetOFDMDownstreamInfo(ctx)
+		if err == nil && c.strict {
+			if err = c.checkOFDMDownstream(ofdmDsInfo); err != nil {
+				ofdmDsInfo = nil
+			}
+		}
+		return err
+	})
+	fetch(hitron.PageOFDMUpstream, &ofdmUsErr, func() (err error) {
+		ofdmUsInfo, err = c.client.GetOFDMUpstreamInfo(ctx)
+		if err == nil && c.strict {
+			if err = c.checkOFDMUpstream(ofdmUsInfo); err != nil {
+				ofdmUsInfo = nil
+			}
+		}
+		return err
+	})
+	fetch(hitron.PageLinkStatus, &linkErr, func() (err error) {
+		linkInfo, err = c.client.GetLinkStatus(ctx)
+		if err == nil && c.strict {
+			if err = c.checkLinkStatus(linkInfo); err != nil {
+				linkInfo = nil
+			}
+		}
+		return err
+	})
+	fetch(hitron.PageSystemInfo, &sysErr, func() (err error) {
+		sysInfo, err = c.client.GetSystemInfo(ctx)
+		sysFetched = time.Now()
+		if err == nil && c.strict {
+			if err = c.checkSystemInfo(sysInfo); err != nil {
+				sysInfo = nil
+			}
+		}
+		return err
+	})
+	g.Go(func() error { c.serviceFlows.update(ctx, c.client); return nil })
+	g.Go(func() error { c.eventLog.update(ctx, c.client); return nil })
+	g.Go(func() error { c.cmInit.update(ctx, c.client); return nil })
+	g.Go(func() error { c.wan.update(ctx, c.client); return nil })
+	g.Wait()
+
+	errs := map[hitron.Page]error{
+		hitron.PageDownstream:     dsErr,
+		hitron.PageUpstream:       usErr,
+		hitron.PageOFDMDownstream: ofdmDsErr,
+		hitron.PageOFDMUpstream:   ofdmUsErr,
+		hitron.PageLinkStatus:     linkErr,
+		hitron.PageSystemInfo:     sysErr,
+	}
+	up := 0.0
+	for page, err := range errs {
+		if err == nil && c.client.Endpoint(page) != "" {
+			up = 1
+			break
+		}
+	}
+	c.up.Set(up)
+	c.watchdog.update(c.client, up == 1)
+	if c.client.CircuitOpen() {
+		c.circuitOpen.Set(1)
+	} else {
+		c.circuitOpen.Set(0)
+	}
+	full := true
+	failed := 0
+	for page, err := range errs {
+		if err != nil && c.client.Endpoint(page) != "" {
+			full = false
+			failed++
+		}
+	}
+	span.SetAttributes(attribute.Int("pages_failed", failed))
+	if up == 0 {
+		span.SetStatus(codes.Error, "no page could be fetched")
+	}
+	if full {
+		c.polledOK.Store(true)
+	}
+	authRequired := 0.0
+	for page, err := range errs {
+		if errors.Is(err, hitron.ErrLoginRequired) {
+			slog.Warn("Modem answered with its login page; check -modem-username and -modem-password", "endpoint", c.client.Endpoint(page))
+			authRequired = 1
+			break
+		}
+	}
+	c.authRequired.Set(authRequired)
+
+	// Each page that was fetched replaces its channels wholesale, so channels
+	// the modem no longer reports (after a re-scan, say) stop being exported.
+	// A failed page keeps its last values.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.polled = start
+	// Exemplars only point at traces that were kept.
+	var traceID string
+	if sc := span.SpanContext(); sc.IsSampled() {
+		traceID = sc.TraceID().String()
+	}
+	expired := c.trackFetched(errs, time.Now())
+	// The counters are tracked below, so a reboot has to be known first.
+	if sysErr == nil {
+		if uptime, err := hitron.ParseUptime(sysInfo.SystemUptime); err == nil {
+			c.trackBoot(sysFetched.Add(-uptime))
+		}
+	}
+
+	// Collect downstream metrics. An expired page is rebuilt from no
+	// channels, which drops its series.
+	if dsErr != nil {
+		slog.Warn("Failed to get downstream info", "reason", hitron.ClassifyError(dsErr), "err", dsErr)
+	}
+	if dsErr == nil || expired[hitron.PageDownstream] {
+		resetVecs(c.downstreamPower, c.downstreamSNR, c.downstreamPowerInSpec, c.downstreamSNRInSpec, c.downstreamFreq, c.downstreamModulationBits, c.downstreamInfo, c.downstreamChannels)
+		var counters []modemCounter
+		var snrs, powers []float64
+		parse := c.parser(hitron.PageDownstream)
+		for _, channel := range collector.ParseDownstream(parse, dsInfo) {
+			labels := c.labels.Values("channel_id", channel.ID, "frequency", channel.Frequency, "modulation", channel.Modulation)
+			c.downstreamInfo.WithLabelValues(channel.ID, channel.Frequency, channel.Modulation).Set(1)
+
+			if power := channel.Power; power.OK {
+				c.downstreamPower.WithLabelValues(labels...).Set(power.V)
+				c.downstreamPowerInSpec.WithLabelValues(labels...).Set(inSpec(power.V, c.spec.DownstreamPowerMin, c.spec.DownstreamPowerMax))
+				powers = append(powers, power.V)
+			}
+			if snr := channel.SNR; snr.OK {
+				c.downstreamSNR.WithLabelValues(labels...).Set(snr.V)
+				c.downstreamSNRInSpec.WithLabelValues(labels...).Set(inSpec(snr.V, c.spec.DownstreamSNRMin, math.Inf(1)))
+				snrs = append(snrs, snr.V)
+			}
+			if channel.FrequencyHz.OK {
+				c.downstreamFreq.WithLabelValues(c.labels.Values("channel_id", channel.ID, "modulation", channel.Modulation)...).Set(channel.FrequencyHz.V)
+			}
+			if channel.Correcteds.OK {
+				counters = append(counters, modemCounter{desc: c.downstreamCorrectables, value: channel.Correcteds.V, labels: labels})
+			}
+			if channel.Uncorrectables.OK {
+				counters = append(counters, modemCounter{desc: c.downstreamUncorrectables, value: channel.Uncorrectables.V, labels: labels})
+			}
+			if channel.Octets.OK {
+				counter := modemCounter{desc: c.downstreamOctets, value: channel.Octets.V, labels: labels}
+				if channel.OctetsMayWrap {
+					counter.maxRate = qamMaxByteRate
+				}
+				counters = append(counters, counter)
+			}
+			if channel.ModulationBits.OK {
+				c.downstreamModulationBits.WithLabelValues(channel.ID).Set(channel.ModulationBits.V)
+			}
+			c.downstreamChannels.WithLabelValues(channel.Modulation).Inc()
+		}
+		c.dsResets.track(counters, time.Now(), traceID)
+		c.dsCounters = counters
+		c.downstreamBonded.WithLabelValues("qam").Set(float64(len(dsInfo)))
+		summarize(c.downstreamSNRSummary, snrs)
+		summarize(c.downstreamPowerSummary, powers)
+	}
+
+	// Collect upstream metrics
+	if usErr != nil {
+		slog.Warn("Failed to get upstream info", "reason", hitron.ClassifyError(usErr), "err", usErr)
+	}
+	if usErr == nil || expired[hitron.PageUpstream] {
+		resetVecs(c.upstreamPower, c.upstreamPowerInSpec, c.upstreamFreq, c.upstreamSymbolRate, c.upstreamBandwidth, c.upstreamScdmaMode, c.upstreamScdma, c.upstreamModulationBits, c.upstreamInfo, c.upstreamChannels)
+		// The OFDMA channels come from another page.
+		c.upstreamType.DeletePartialMatch(prometheus.Labels{"type": upstreamTypeSCQAM})
+		var powers []float64
+		parse := c.parser(hitron.PageUpstream)
+		for _, channel := range collector.ParseUpstream(parse, usInfo) {
+			labels := c.labels.Values("channel_id", channel.ID, "frequency", channel.Frequency, "modulation", channel.Modulation)
+			c.upstreamInfo.WithLabelValues(channel.ID, channel.Frequency, channel.Modulation).Set(1)
+			c.upstreamType.WithLabelValues(c.labels.Values("channel_id", channel.ID, "frequency", channel.Frequency, "type", upstreamTypeSCQAM)...).Set(1)
+
+			if power := channel.Power; power.OK {
+				c.upstreamPower.WithLabelValues(labels...).Set(power.V)
+				c.upstreamPowerInSpec.WithLabelValues(labels...).Set(inSpec(power.V, c.spec.UpstreamPowerMin, c.spec.UpstreamPowerMax))
+				powers = append(powers, power.V)
+			}
+			if channel.FrequencyHz.OK {
+				c.upstreamFreq.WithLabelValues(c.labels.Values("channel_id", channel.ID, "modulation", channel.Modulation)...).Set(channel.FrequencyHz.V)
+			}
+			if channel.Bandwidth.OK {
+				c.upstreamBandwidth.WithLabelValues(labels...).Set(channel.Bandwidth.V)
+			}
+			if channel.SymbolRate.OK {
+				c.upstreamSymbolRate.WithLabelValues(labels...).Set(channel.SymbolRate.V)
+			}
+			if channel.ModulationBits.OK {
+				c.upstreamModulationBits.WithLabelValues(channel.ID).Set(channel.ModulationBits.V)
+			}
+			c.upstreamScdmaMode.WithLabelValues(channel.ID, strings.TrimSpace(channel.ScdmaMode)).Set(1)
+			if channel.SCDMA.OK {
+				c.upstreamScdma.WithLabelValues(channel.ID).Set(channel.SCDMA.V)
+			}
+			c.upstreamChannels.WithLabelValues(channel.Modulation).Inc()
+		}
+		c.upstreamBonded.WithLabelValues("qam").Set(float64(len(usInfo)))
+		summarize(c.upstreamPowerSummary, powers)
+	}
+
+	// Collect OFDM downstream metrics
+	if ofdmDsErr != nil {
+		slog.Warn("Failed to get OFDM downstream info", "reason", hitron.ClassifyError(ofdmDsErr), "err", ofdmDsErr)
+	}
+	if ofdmDsErr == nil || expired[hitron.PageOFDMDownstream] {
+		resetVecs(c.ofdmDownstreamPower, c.ofdmDownstreamPowerInSpec, c.ofdmDownstreamSNR, c.ofdmDownstreamFreq,
+			c.ofdmDownstreamWidth, c.ofdmDownstreamSpacing, c.ofdmDownstreamModulationBits, c.ofdmDownstreamLocks, c.ofdmDownstreamInfo, c.ofdmDownstreamChannels)
+		var counters []modemCounter
+		locked := 0
+		parse := c.parser(hitron.PageOFDMDownstream)
+		for _, channel := range collector.ParseOFDMDownstream(parse, ofdmDsInfo) {
+			labels := c.labels.Values("receive", channel.Receive, "frequency", channel.Frequency, "fft_type", channel.FFTType)
+			widthLabels := c.labels.Values("receive", channel.Receive, "fft_type", channel.FFTType)
+			c.ofdmDownstreamInfo.WithLabelValues(channel.Receive, channel.Frequency, channel.FFTType).Set(1)
+
+			if power := channel.Power; power.OK {
+				c.ofdmDownstreamPower.WithLabelValues(labels...).Set(power.V)
+				// An unlocked receiver reports no meaningful power.
+				if channel.PLCLock {
+					c.ofdmDownstreamPowerInSpec.WithLabelValues(labels...).Set(inSpec(power.V, c.spec.DownstreamPowerMin, c.spec.DownstreamPowerMax))
+				}
+			}
+			if channel.SNR.OK {
+				c.ofdmDownstreamSNR.WithLabelValues(labels...).Set(channel.SNR.V)
+			}
+			if channel.ModulationBits.OK {
+				c.ofdmDownstreamModulationBits.WithLabelValues(channel.Receive).Set(channel.ModulationBits.V)
+			}
+			if channel.FrequencyHz.OK {
+				c.ofdmDownstreamFreq.WithLabelValues(widthLabels...).Set(channel.FrequencyHz.V)
+			}
+			if channel.Width.OK {
+				c.ofdmDownstreamWidth.WithLabelValues(widthLabels...).Set(channel.Width.V)
+				c.ofdmDownstreamSpacing.WithLabelValues(widthLabels...).Set(channel.Spacing.V)
+			}
+			if channel.Correcteds.OK {
+				counters = append(counters, modemCounter{desc: c.ofdmDownstreamCorrectables, value: channel.Correcteds.V, labels: labels})
+			}
+			if channel.Uncorrectables.OK {
+				counters = append(counters, modemCounter{desc: c.ofdmDownstreamUncorrectables, value: channel.Uncorrectables.V, labels: labels})
+			}
+			if channel.Octets.OK {
+				counters = append(counters, modemCounter{desc: c.ofdmDownstreamOctets, value: channel.Octets.V, labels: labels, maxRate: ofdmMaxByteRate})
+			}
+
+			// Lock status metrics
+			lockLabels := c.labels.Values("receive", channel.Receive, "frequency", channel.Frequency)
+			plcLock := 0.0
+			if channel.PLCLock {
+				plcLock = 1.0
+				locked++
+			}
+			ncpLock := 0.0
+			if channel.NCPLock {
+				ncpLock = 1.0
+			}
+			mdc1Lock := 0.0
+			if channel.MDC1Lock {
+				mdc1Lock = 1.0
+			}
+
+			c.ofdmDownstreamLocks.WithLabelValues(append(lockLabels, "plc")...).Set(plcLock)
+			c.ofdmDownstreamLocks.WithLabelValues(append(lockLabels, "ncp")...).Set(ncpLock)
+			c.ofdmDownstreamLocks.WithLabelValues(append(lockLabels, "mdc1")...).Set(mdc1Lock)
+			c.trackLock(channel.Receive, "plc", plcLock)
+			c.trackLock(channel.Receive, "ncp", ncpLock)
+			c.trackLock(channel.Receive, "mdc1", mdc1Lock)
+			c.ofdmDownstreamChannels.WithLabelValues(channel.PLCLockStatus).Inc()
+		}
+		c.ofdmDsResets.track(counters, time.Now(), traceID)
+		c.ofdmDsCounters = counters
+		c.downstreamBonded.WithLabelValues("ofdm").Set(float64(locked))
+	}
+	if dsErr == nil || ofdmDsErr == nil || expired[hitron.PageDownstream] || expired[hitron.PageOFDMDownstream] {
+		counters := c.sumUncorrectables()
+		c.uncorrectablesTotalResets.track(counters, time.Now(), traceID)
+		c.uncorrectablesTotal = counters
+	}
+
+	// Collect OFDM upstream metrics
+	if ofdmUsErr != nil {
+		slog.Warn("Failed to get OFDM upstream info", "reason", hitron.ClassifyError(ofdmUsErr), "err", ofdmUsErr)
+	}
+	if ofdmUsErr == nil || expired[hitron.PageOFDMUpstream] {
+		resetVecs(c.ofdmUpstreamPower, c.ofdmUpstreamPowerInSpec, c.ofdmUpstreamFreq, c.ofdmUpstreamBandwidth, c.ofdmUpstreamWidth, c.ofdmUpstreamState, c.ofdmUpstreamInfo, c.ofdmUpstreamChannels)
+		c.upstreamType.DeletePartialMatch(prometheus.Labels{"type": upstreamTypeOFDMA})
+		operating := 0
+		parse := c.parser(hitron.PageOFDMUpstream)
+		for _, channel := range collector.ParseOFDMUpstream(parse, ofdmUsInfo) {
+			state := channel.State
+			stateValue := 0.0
+			if channel.Operating {
+				stateValue = 1.0
+				operating++
+				c.upstreamType.WithLabelValues(c.labels.Values("channel_id", channel.USCHIndex, "frequency", channel.Frequency, "type", upstreamTypeOFDMA)...).Set(1)
+			}
+
+			labels := c.labels.Values("usch_index", channel.USCHIndex, "frequency", channel.Frequency, "state", state)
+			c.ofdmUpstreamInfo.WithLabelValues(channel.USCHIndex, channel.Frequency, state).Set(1)
+			c.ofdmUpstreamChannels.WithLabelValues(state).Inc()
+
+			// Only active channels have their levels set
+			if channel.FrequencyHz.OK {
+				c.ofdmUpstreamFreq.WithLabelValues(c.labels.Values("usch_index", channel.USCHIndex, "state", state)...).Set(channel.FrequencyHz.V)
+			}
+			if power := channel.Power; power.OK {
+				c.ofdmUpstreamPower.WithLabelValues(labels...).Set(power.V)
+				if channel.Operating {
+					c.ofdmUpstreamPowerInSpec.WithLabelValues(labels...).Set(inSpec(power.V, c.spec.UpstreamPowerMin, c.spec.UpstreamPowerMax))
+				}
+			}
+			if bandwidth := channel.Bandwidth; bandwidth.OK {
+				c.ofdmUpstreamBandwidth.WithLabelValues(labels...).Set(bandwidth.V / 1e6)
+				c.ofdmUpstreamWidth.WithLabelValues(labels...).Set(bandwidth.V)
+			}
+			c.ofdmUpstreamState.WithLabelValues(c.labels.Values("usch_index", channel.USCHIndex, "frequency", channel.Frequency)...).Set(stateValue)
+		}
+		c.upstreamBonded.WithLabelValues("ofdma").Set(float64(operating))
+	}
+
+	// Collect link status
+	if linkErr != nil {
+		slog.Warn("Failed to get link status", "reason", hitron.ClassifyError(linkErr), "err", linkErr)
+	} else {
+		// Parse link status
+		status := 0.0
+		if collector.LinkUp(linkInfo) {
+			status = 1.0
+		}
+		c.linkStatus.Set(status)
+
+		c.linkInfo.Reset()
+		c.linkInfo.WithLabelValues(hitron.ParseDuplex(linkInfo.LinkDuplex)).Set(1)
+
+		// Leave the speed gauges untouched rather than report a bogus 0
+		speed, err := hitron.ParseLinkSpeed(linkInfo.LinkSpeed)
+		if err != nil {
+			slog.Warn("Failed to parse link speed", "err", err)
+			c.linkSpeedParseErrors.Inc()
+		} else {
+			c.linkSpeed.Set(speed / 1e6)
+			c.linkSpeedBits.Set(speed)
+		}
+	}
+
+	// Collect system info
+	if sysErr != nil {
+		slog.Warn("Failed to get system info", "reason", hitron.ClassifyError(sysErr), "err", sysErr)
+	} else {
+		// Reset so a firmware upgrade doesn't leave the old version behind
+		c.systemInfo.Reset()
+		c.systemInfo.WithLabelValues(
+			sysInfo.HWVersion,
+			sysInfo.SWVersion,
+			sysInfo.SerialNumber,
+		).Set(1)
+		c.trackFirmware(sysInfo)
+
+		if uptime, err := hitron.ParseUptime(sysInfo.SystemUptime); err != nil {
+			slog.Warn("Failed to parse system uptime", "err", err)
+		} else {
+			c.systemUptime.Set(uptime.Seconds())
+		}
+
+		parse := c.parser(hitron.PageSystemInfo)
+		var counters []modemCounter
+		for _, t := range collector.ParseTraffic(parse, sysInfo) {
+			if t.Bytes.OK {
+				counters = append(counters, modemCounter{desc: c.trafficDesc, value: t.Bytes.V, labels: []string{t.Interface, t.Direction}})
+			}
+		}
+		c.trafficResets.track(counters, time.Now(), traceID)
+		c.traffic = counters
+
+		// The clock only has whole seconds, so up to a second of drift is
+		// rounding. Sources without the field, such as SNMP, leave it empty.
+		if strings.TrimSpace(sysInfo.SystemTime) != "" {
+			modemTime, err := hitron.ParseSystemTime(sysInfo.SystemTime, sysInfo.Timezone, time.Local)
+			if parse.Check("systemTime", err) {
+				c.systemTime.Set(float64(modemTime.Unix()))
+				c.clockDrift.Set(modemTime.Sub(sysFetched.Truncate(time.Second)).Seconds())
+			}
+		}
+	}
+
+	c.autoReboot.update(c.client, start, dsInfo, dsErr, ofdmDsInfo, ofdmDsErr)
+	c.recordStatus(start, errs, dsInfo, usInfo, ofdmDsInfo, ofdmUsInfo, sysInfo, linkInfo)
+
+	c.lastPoll.SetToCurrentTime()
+	c.collectDuration.Set(time.Since(start).Seconds())
+}
+
+// trackFetched records which pages were fetched in a poll and marks the
+// others stale, as they keep serving their previous data. It returns the
+// pages that have failed for longer than the cache TTL, whose series are to
+// be dropped. c.mu must be held.
+func (c *MetricsCollector) trackFetched(errs map[hitron.Page]error, now time.Time) map[hitron.Page]bool {
+	expired := map[hitron.Page]bool{}
+	for page, err := range errs {
+		endpoint := c.client.Endpoint(page)
+		if endpoint == "" {
+			continue
+		}
+		if err == nil {
+			c.fetched[page] = now
+			c.lastSuccess.WithLabelValues(endpoint).Set(float64(now.Unix()))
+			c.dataStale.WithLabelValues(endpoint).Set(0)
+			continue
+		}
+		last, ok := c.fetched[page]
+		switch {
+		case !ok:
+		case errors.Is(err, errInvalidValue) && channelPage(page):
+			// With -strict, channels that no longer parse go at once: their
+			// previous values are older than a page that couldn't be trusted.
+			slog.Warn("Dropping metrics of a page with invalid values", "endpoint", endpoint, "last_success", last)
+			expired[page] = true
+			delete(c.fetched, page)
+			ok = false
+		case c.cacheTTL > 0 && now.Sub(last) > c.cacheTTL:
+			slog.Warn("Dropping stale metrics", "endpoint", endpoint, "last_success", last)
+			expired[page] = true
+			delete(c.fetched, page)
+			ok = false
+		}
+		stale := 0.0
+		if ok {
+			stale = 1.0
+		}
+		c.dataStale.WithLabelValues(endpoint).Set(stale)
+	}
+	return expired
+}
+
+// channelPage reports whether page lists channels, whose series update
+// drops when the page expires. The link and system pages keep theirs.
+func channelPage(page hitron.Page) bool {
+	return page != hitron.PageLinkStatus && page != hitron.PageSystemInfo
+}
+
+// bootSlack is how far apart two estimates of the same boot time can be,
+// from the uptime's rounding and the time taken to fetch it.
+const bootSlack = time.Minute
+
+// trackBoot notices a reboot from the boot time moving forward, and has the
+// counter trackers start all series over from it rather than only those that
+// went backwards. c.mu must be held.
+func (c *MetricsCollector) trackBoot(boot time.Time) {
+	previous := c.bootTime
+	c.bootTime = boot
+	if c.counterStore != nil {
+		c.counterStore.setBoot(c.tags["modem"], boot)
+	}
+	if previous.IsZero() || boot.Sub(previous) <= bootSlack {
+		return
+	}
+	slog.Info("Modem rebooted", "modem", c.client.BaseURL(), "boot_time", boot.Truncate(time.Second), "previous_boot_time", previous.Truncate(time.Second))
+	c.reboots.Inc()
+	for _, r := range []*counterResets{c.dsResets, c.ofdmDsResets, c.uncorrectablesTotalResets, c.trafficResets} {
+		r.reboot(boot)
+	}
+}
+
+// trackLock counts a flap when an OFDM receiver's lock differs from the
+// previous poll, so unlocks that are over by the next scrape still show up in
+// increase(). c.mu must be held.
+func (c *MetricsCollector) trackLock(receive, lockType string, locked float64) {
+	flaps := c.ofdmDownstreamLockFlaps.WithLabelValues(c.labels.Values("receive", receive, "lock_type", lockType)...)
+	key := receive + "/" + lockType
+	if previous, ok := c.ofdmLocks[key]; ok && previous != locked {
+		slog.Info("OFDM downstream lock changed", "receive", receive, "lock_type", lockType, "locked", locked == 1)
+		flaps.Inc()
+	}
+	c.ofdmLocks[key] = locked
+}
+
+// trackFirmware counts version changes and records when the current version
+// was first seen, so an upgrade pushed by the ISP shows up as an event rather
+// than just a new hitron_system_info label set. c.mu must be held.
+func (c *MetricsCollector) trackFirmware(info *hitron.SystemInfo) {
+	if info.HWVersion == c.hwVersion && info.SWVersion == c.swVersion {
+		return
+	}
+	if c.hwVersion != "" || c.swVersion != "" {
+		slog.Info("Modem firmware changed", "from", c.hwVersion+"/"+c.swVersion, "to", info.HWVersion+"/"+info.SWVersion)
+		c.firmwareChanges.Inc()
+	}
+	c.hwVersion, c.swVersion = info.HWVersion, info.SWVersion
+	c.firmwareVersion.Reset()
+	c.firmwareVersion.WithLabelValues(info.HWVersion, info.SWVersion).SetToCurrentTime()
+}
+
+// Values of the type label of upstream_channel_type_info.
+const (
+	upstreamTypeSCQAM = "SC-QAM"
+	upstreamTypeOFDMA = "OFDMA"
+)
+
+// resetVecs drops every series from vecs.
+func resetVecs(vecs ...*prometheus.GaugeVec) {
+	for _, v := range vecs {
+		v.Reset()
+	}
+}
+
+// metricsLabels collects the repeatable -metrics.label flag.
+var metricsLabels = labelFlag{}
+
+func init() {
+	flag.Var(metricsLabels, "metrics.label", "Static `key=value` label added to every metric (repeatable)")
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func main() {
+	startServiceHandler()
+	flag.Parse()
+	setFlags := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+	if err := applyEnv(flag.CommandLine, setFlags); err != nil {
+		fatal(err.Error())
+	}
+	if err := setupLogging(*logLevel, *logFormat); err != nil {
+		fatal(err.Error())
+	}
+
+	cfg, err := loadSettings(*configFile, setFlags)
+	if err != nil {
+		fatal(err.Error())
+	}
+
+	store := NewSnapshotStore(*snapshotDir)
+
+	if flag.NArg() > 0 {
+		client := cfg.newClient(cfg.ModemHost, cfg.hostOptions()...)
+		switch flag.Arg(0) {
+		case "snapshot":
+			err = runSnapshotCommand(client, store, flag.Args()[1:])
+		case "benchmark":
+			err = runBenchmarkCommand(client, flag.Args()[1:])
+		case "dump":
+			err = runDumpCommand(client, flag.Args()[1:])
+		case "check":
+			err = runCheckCommand(cfg, flag.Args()[1:])
+		case "mockmodem":
+			err = runMockModemCommand(flag.Args()[1:])
+		case "collect":
+			err = runCollectCommand(cfg, flag.Args()[1:])
+		case "gen-dashboard":
+			err = runGenDashboardCommand(cfg, flag.Args()[1:])
+		case "install-service":
+			err = runInstallServiceCommand(flag.Args()[1:])
+		case "uninstall-service":
+			err = runUninstallServiceCommand(flag.Args()[1:])
+		default:
+			err = fmt.Errorf("unknown command %q", flag.Arg(0))
+		}
+		if err != nil {
+			fatal(err.Error())
+		}
+		return
+	}
+
+	slog.Info("Starting Hitron CODA56 Prometheus Exporter", "version", version)
+
+	haConfig := HAConfig{
+		ID:            *haID,
+		AdvertiseURL:  *haAdvertiseURL,
+		LeaseDuration: *haLeaseDuration,
+		LeaseFile:     *haLeaseFile,
+		Priority:      *haPriority,
+		KubeLease:     *haKubeLease,
+		KubeNamespace: *haKubeNamespace,
+	}
+	haConfig.Peers = splitList(*haPeers)
+	if *haStandby != "cache" && *haStandby != "proxy" {
+		fatal("Failed to set up high availability", "err", fmt.Errorf("unknown -ha-standby %q: expected cache or proxy", *haStandby))
+	}
+	if err := haConfig.resolveID(); err != nil {
+		fatal("Failed to set up high availability", "err", err)
+	}
+	elector, err := NewElector(*haMode, haConfig)
+	if err != nil {
+		fatal("Failed to set up high availability", "err", err)
+	}
+
+	sinks, err := newSinks(cfg)
+	if err != nil {
+		fatal(err.Error())
+	}
+	history, err := openHistoryStore(*historyRetention, *historyPath)
+	if err != nil {
+		fatal(err.Error())
+	}
+	counters, err := openCounterStore(*stateFile)
+	if err != nil {
+		fatal(err.Error())
+	}
+	sinks = append(sinks, history)
+	var grpcWatchers *grpcHub
+	if *grpcListenAddr != "" {
+		grpcWatchers = newGRPCHub()
+		sinks = append(sinks, grpcWatchers)
+	}
+
+	e := &exporter{
+		configFile: *configFile,
+		setFlags:   setFlags,
+		elector:    elector,
+		auth:       NewAPIAuth(),
+		probe:      newProbeHandler(cfg),
+		sinks:      sinks,
+		counters:   counters,
+	}
+	e.probe.named = e.collectorNamed
+	if *ingestMode && *replayDir != "" {
+		fatal("-ingest and -replay can't be combined")
+	}
+	if *ingestMode {
+		e.ingest = NewIngestSource(*ingestMaxAge)
+	}
+	if err := e.apply(cfg); err != nil {
+		fatal(err.Error())
+	}
+	if e.ingest != nil && !e.auth.Enabled() {
+		fatal("-ingest requires API tokens so that pushed data is authenticated")
+	}
+	go e.watchSIGHUP()
+
+	// The exporter's own metrics. A private registry rather than the
+	// default one, so the runtime collectors can be left out.
+	reg := prometheus.NewRegistry()
+	if *runtimeMetrics {
+		reg.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+	}
+
+	if *updateCheck {
+		checker := NewUpdateChecker(*updateInterval)
+		cfg.registerer(reg, cfg.Labels).MustRegister(checker)
+		go checker.Run(context.Background())
+	}
+
+	gatherer := prometheus.Gatherers{reg, &e.modems}
+	if *otlpTraces {
+		if *otlpEndpoint == "" {
+			fatal("-otlp.traces requires -otlp.endpoint")
+		}
+		if _, err := startOTLPTracing(context.Background(), *otlpEndpoint, *otlpProtocol, *otlpInsecure); err != nil {
+			fatal(err.Error())
+		}
+	}
+	if *otlpEndpoint != "" {
+		if _, err := startOTLP(context.Background(), gatherer, *otlpEndpoint, *otlpProtocol, *otlpInsecure, *otlpInterval); err != nil {
+			fatal(err.Error())
+		}
+	}
+
+	mux := http.NewServeMux()
+	metricsHandler := promhttp.InstrumentMetricHandler(
+		reg,
+		scrapeHandler(func(ctx context.Context) prometheus.Gatherer {
+			return prometheus.Gatherers{reg, e.modems.forScrape(ctx)}
+		}, *scrapeTimeoutOffset, *webMaxScrapes, exposition()),
+	)
+	if elector != nil {
+		slog.Info("High availability enabled", "mode", *haMode)
+		go elector.Run(context.Background())
+
+		cfg.registerer(reg, cfg.Labels).MustRegister(prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Name: "exporter_ha_leader",
+				Help: "Whether this exporter instance is the HA leader (1 = leader, 0 = standby)",
+			},
+			func() float64 {
+				if elector.IsLeader() {
+					return 1
+				}
+				return 0
+			},
+		))
+
+		mux.Handle("/-/ha", haStatusHandler(elector, haConfig))
+		if *haStandby == "proxy" {
+			metricsHandler = standbyProxyHandler(elector, metricsHandler)
+		}
+	}
+	if *requireReadyMetrics {
+		metricsHandler = requireReady(e, metricsHandler)
+	}
+	mux.Handle("/metrics", metricsHandler)
+	registerHealthHandlers(mux, e)
+	mux.Handle("/probe", e.probe)
+	registerSDHandler(mux, e)
+	registerSnapshotHandlers(mux, e.client, store, e.auth)
+	registerStatusHandler(mux, e.collector, e.auth)
+	registerRawHandler(mux, e.clientNamed, e.auth)
+	registerConfigHandler(mux, e, e.auth)
+	registerActionHandlers(mux, e.clientNamed, e.auth)
+	if e.ingest != nil {
+		registerIngestHandlers(mux, e.ingest, e.auth)
+	}
+	registerDashboardHandler(mux, e.config.Load)
+	registerUIHandlers(mux, e.collector, history, e.auth)
+	registerHistoryHandler(mux, e.collector, history, e.auth)
+
+	if *grpcListenAddr != "" {
+		if err := startGRPC(*grpcListenAddr, *grpcTLSCertFile, *grpcTLSKeyFile, e, grpcWatchers); err != nil {
+			fatal(err.Error())
+		}
+	}
+
+	slog.Info("Starting HTTP server", "address", *listenAddr)
+	var handler http.Handler = corsHandler(&CORSConfig{
+		AllowedOrigins: splitList(*apiCORSOrigins),
+		AllowedHeaders: splitList(*apiCORSHeaders),
+	}, mux)
+	if *webRateLimit > 0 {
+		handler = NewClientRateLimiter(*webRateLimit, *webRateBurst).Wrap(handler)
+	}
+
+	// The exporter toolkit adds TLS and basic auth from -web.config.file and
+	// behaves like plain ListenAndServe without it.
+	server := &http.Server{Handler: handler}
+	systemdSocket := false
+	webFlags := &web.FlagConfig{
+		WebListenAddresses: &[]string{*listenAddr},
+		WebSystemdSocket:   &systemdSocket,
+		WebConfigFile:      webConfigFile,
+	}
+	if path, ok := socketPath(*listenAddr); ok {
+		l, err := listenUnix(path)
+		if err != nil {
+			fatal("Failed to listen on Unix socket", "path", path, "err", err)
+		}
+		if err := web.Serve(l, server, webFlags, slog.Default()); err != nil {
+			fatal("Failed to start HTTP server", "err", err)
+		}
+		return
+	}
+	if err := web.ListenAndServe(server, webFlags, slog.Default()); err != nil {
+		fatal("Failed to start HTTP server", "err", err)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"embed"
+	"encoding/hex"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed mockdata/*.json
+var mockData embed.FS
+
+// runMockModemCommand implements the "mockmodem" subcommand: an HTTPS server
+// that answers like a CODA56 from canned pages, for trying out the exporter
+// and dashboards without a modem. Like the real modem it uses a self-signed
+// certificate, generated at startup.
+func runMockModemCommand(args []string) error {
+	fs := flag.NewFlagSet("mockmodem", flag.ContinueOnError)
+	listen := fs.String("listen", "localhost:8443", "Address to serve the mock modem on")
+	username := fs.String("username", "", "Require the web interface login with this username (default: no login)")
+	password := fs.String("password", "", "Password for -username")
+	certOut := fs.String("cert-out", "", "Write the generated certificate to this PEM file, for -modem-ca-file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cert, err := selfSignedCert()
+	if err != nil {
+		return fmt.Errorf("failed to generate certificate: %w", err)
+	}
+	if *certOut != "" {
+		block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+		if err := os.WriteFile(*certOut, block, 0o644); err != nil {
+			return fmt.Errorf("failed to write certificate: %w", err)
+		}
+	}
+	fingerprint := sha256.Sum256(cert.Certificate[0])
+
+	m := &mockModem{username: *username, password: *password, sessions: map[string]bool{}}
+	server := &http.Server{
+		Addr:      *listen,
+		Handler:   m.handler(),
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+	}
+	slog.Info("Mock modem listening", "url", "https://"+*listen)
+	slog.Info("Generated certificate", "fingerprint", hex.EncodeToString(fingerprint[:]))
+	return server.ListenAndServeTLS("", "")
+}
+
+// mockModem mimics the parts of the web interface the exporter talks to.
+type mockModem struct {
+	username string
+	password string
+
+	mu       sync.Mutex
+	sessions map[string]bool
+}
+
+func (m *mockModem) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /login.html", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "preSession", Value: randomToken(), Path: "/"})
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintln(w, "<html><body><form action=\"/goform/login\" method=\"post\"></form></body></html>")
+	})
+	mux.HandleFunc("POST /goform/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("usr") != m.username || r.FormValue("pwd") != m.password {
+			fmt.Fprint(w, "failed")
+			return
+		}
+		session := randomToken()
+		m.mu.Lock()
+		m.sessions[session] = true
+		m.mu.Unlock()
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: session, Path: "/"})
+		fmt.Fprint(w, "success")
+	})
+	mux.HandleFunc("POST /goform/Reboot", func(w http.ResponseWriter, r *http.Request) {
+		if !m.loggedIn(r) {
+			http.Redirect(w, r, "/login.html", http.StatusFound)
+			return
+		}
+		slog.Info("Mock modem received a reboot request", "form", r.FormValue("model"))
+		fmt.Fprint(w, "success")
+	})
+	mux.HandleFunc("GET /data/{page}", func(w http.ResponseWriter, r *http.Request) {
+		if !m.loggedIn(r) {
+			http.Redirect(w, r, "/login.html", http.StatusFound)
+			return
+		}
+		data, err := mockData.ReadFile("mockdata/" + strings.TrimSuffix(r.PathValue("page"), ".asp") + ".json")
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+	return mux
+}
+
+func (m *mockModem) loggedIn(r *http.Request) bool {
+	if m.username == "" {
+		return true
+	}
+	c, err := r.Cookie("session")
+	if err != nil {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sessions[c.Value]
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// selfSignedCert creates a certificate for the names the modem is usually
+// reached by.
+func selfSignedCert() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "coda56-exporter mock modem"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1"), net.ParseIP("192.168.100.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
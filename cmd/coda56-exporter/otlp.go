@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	promBridge "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// tracer records the exporter's polls and scrapes; the modem client adds
+// its requests as child spans. It is a no-op until startOTLPTracing installs
+// a provider.
+var tracer = otel.Tracer("github.com/anupcshan/coda56-exporter")
+
+// otlpResource identifies the exporter in everything sent over OTLP.
+func otlpResource() *resource.Resource {
+	return resource.NewSchemaless(
+		attribute.String("service.name", "coda56-exporter"),
+		attribute.String("service.version", version),
+	)
+}
+
+// startOTLP periodically exports everything gatherer serves on /metrics to
+// an OpenTelemetry collector. The metrics are bridged from the Prometheus
+// registry, so both outputs carry the same data.
+func startOTLP(ctx context.Context, gatherer prometheus.Gatherer, endpoint, protocol string, insecure bool, interval time.Duration) (*metric.MeterProvider, error) {
+	isURL := strings.Contains(endpoint, "://")
+
+	var exp metric.Exporter
+	var err error
+	switch protocol {
+	case "grpc":
+		var opts []otlpmetricgrpc.Option
+		if isURL {
+			opts = append(opts, otlpmetricgrpc.WithEndpointURL(endpoint))
+		} else {
+			opts = append(opts, otlpmetricgrpc.WithEndpoint(endpoint))
+		}
+		if insecure {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+		exp, err = otlpmetricgrpc.New(ctx, opts...)
+	case "http":
+		var opts []otlpmetrichttp.Option
+		if isURL {
+			opts = append(opts, otlpmetrichttp.WithEndpointURL(endpoint))
+		} else {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(endpoint))
+		}
+		if insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		exp, err = otlpmetrichttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown OTLP protocol %q, expected grpc or http", protocol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	reader := metric.NewPeriodicReader(exp,
+		metric.WithInterval(interval),
+		metric.WithProducer(promBridge.NewMetricProducer(promBridge.WithGatherer(gatherer))),
+	)
+	slog.Info("Exporting metrics over OTLP", "protocol", protocol, "endpoint", endpoint, "interval", interval)
+	return metric.NewMeterProvider(metric.WithReader(reader), metric.WithResource(otlpResource())), nil
+}
+
+// startOTLPTracing exports a trace of every poll of the modem, with a span
+// per page request, to the same kind of endpoint as startOTLP, and installs
+// the provider globally so the modem clients use it. Sampling follows the
+// standard OTEL_TRACES_SAMPLER variables and defaults to every poll.
+func startOTLPTracing(ctx context.Context, endpoint, protocol string, insecure bool) (*sdktrace.TracerProvider, error) {
+	isURL := strings.Contains(endpoint, "://")
+
+	var exp sdktrace.SpanExporter
+	var err error
+	switch protocol {
+	case "grpc":
+		var opts []otlptracegrpc.Option
+		if isURL {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(endpoint))
+		} else {
+			opts = append(opts, otlptracegrpc.WithEndpoint(endpoint))
+		}
+		if insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exp, err = otlptracegrpc.New(ctx, opts...)
+	case "http":
+		var opts []otlptracehttp.Option
+		if isURL {
+			opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+		} else {
+			opts = append(opts, otlptracehttp.WithEndpoint(endpoint))
+		}
+		if insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exp, err = otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown OTLP protocol %q, expected grpc or http", protocol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp), sdktrace.WithResource(otlpResource()))
+	otel.SetTracerProvider(tp)
+	slog.Info("Exporting traces over OTLP", "protocol", protocol, "endpoint", endpoint)
+	return tp, nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// point is one row of modem data for push-based outputs: a measurement such
+// as "downstream" with identifying tags and numeric fields.
+type point struct {
+	measurement string
+	tags        []tag
+	fields      []field
+	time        time.Time
+}
+
+type tag struct {
+	key, value string
+}
+
+type field struct {
+	key     string
+	value   float64
+	integer bool
+}
+
+// pointBuilder collects fields, skipping values that don't parse.
+type pointBuilder struct {
+	point
+}
+
+func newPoint(measurement string, t time.Time, tags ...string) *pointBuilder {
+	b := &pointBuilder{point{measurement: measurement, time: t}}
+	for i := 0; i+1 < len(tags); i += 2 {
+		if v := strings.TrimSpace(tags[i+1]); v != "" {
+			b.tags = append(b.tags, tag{tags[i], v})
+		}
+	}
+	return b
+}
+
+func (b *pointBuilder) float(key, s string) *pointBuilder {
+	if v, err := hitron.ParseNumber(s); err == nil {
+		b.fields = append(b.fields, field{key: key, value: v})
+	}
+	return b
+}
+
+func (b *pointBuilder) integer(key, s string) *pointBuilder {
+	if v, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64); err == nil {
+		b.fields = append(b.fields, field{key: key, value: float64(v), integer: true})
+	}
+	return b
+}
+
+func (b *pointBuilder) frequency(key, s string) *pointBuilder {
+	if v, err := hitron.ParseFrequency(s); err == nil {
+		b.fields = append(b.fields, field{key: key, value: v})
+	}
+	return b
+}
+
+func (b *pointBuilder) value(key string, v float64, integer bool) *pointBuilder {
+	b.fields = append(b.fields, field{key: key, value: v, integer: integer})
+	return b
+}
+
+func (b *pointBuilder) flag(key string, set bool) *pointBuilder {
+	v := 0.0
+	if set {
+		v = 1
+	}
+	return b.value(key, v, true)
+}
+
+// snapshotPoints flattens a snapshot into points, one per channel plus the
+// system and link pages. Values are normalized the same way as the metrics.
+func snapshotPoints(snap *hitron.Snapshot) []point {
+	t := snap.Time
+	var points []point
+	add := func(b *pointBuilder) {
+		if len(b.fields) > 0 {
+			points = append(points, b.point)
+		}
+	}
+
+	for _, ch := range snap.Downstream {
+		b := newPoint("downstream", t, "channel_id", ch.ChannelID, "modulation", hitron.NormalizeModulation(ch.Modulation)).
+			float("power_dbmv", ch.SignalStrength).
+			float("snr_db", ch.SNR).
+			frequency("frequency_hz", ch.Frequency).
+			integer("correctables", ch.Correcteds).
+			integer("uncorrectables", ch.Uncorrect)
+		if octets, err := hitron.ParseComplexOctets(ch.DSoctets); err == nil {
+			b.value("octets", float64(octets), true)
+		}
+		add(b)
+	}
+	for _, ch := range snap.Upstream {
+		add(newPoint("upstream", t, "channel_id", ch.ChannelID, "modulation", hitron.NormalizeModulation(ch.ModType)).
+			float("power_dbmv", ch.SignalStrength).
+			frequency("frequency_hz", ch.Frequency).
+			frequency("bandwidth_hz", ch.Bandwidth).
+			float("symbol_rate", ch.Bandwidth))
+	}
+	for _, ch := range snap.OFDMDownstream {
+		add(newPoint("ofdm_downstream", t, "receive", ch.Receive, "fft_type", ch.FFTType).
+			float("power_dbmv", ch.PLCPower).
+			float("snr_db", ch.SNR).
+			frequency("frequency_hz", ch.Subcarr0freqFreq).
+			integer("correctables", ch.Correcteds).
+			integer("uncorrectables", ch.Uncorrect).
+			integer("octets", ch.DSoctets).
+			flag("plc_lock", strings.TrimSpace(ch.PLCLock) == "YES"))
+	}
+	for _, ch := range snap.OFDMUpstream {
+		state := strings.TrimSpace(ch.State)
+		b := newPoint("ofdm_upstream", t, "usch_index", ch.USCHIndex, "state", state).
+			flag("operate", state == "OPERATE")
+		if f, err := hitron.ParseFrequency(ch.Frequency); err == nil && f > 0 {
+			b.value("frequency_hz", f, false).
+				float("power_dbmv", ch.RepPower).
+				frequency("bandwidth_hz", ch.ChannelBw)
+		}
+		add(b)
+	}
+	if sys := snap.System; sys != nil {
+		b := newPoint("system", t, "hardware_version", sys.HWVersion, "software_version", sys.SWVersion)
+		if uptime, err := hitron.ParseUptime(sys.SystemUptime); err == nil {
+			b.value("uptime_seconds", uptime.Seconds(), false)
+		}
+		add(b)
+	}
+	if link := snap.Link; link != nil {
+		b := newPoint("link", t).flag("up", strings.EqualFold(strings.TrimSpace(link.LinkStatus), "up"))
+		if speed, err := hitron.ParseLinkSpeed(link.LinkSpeed); err == nil {
+			b.value("speed_bps", speed, false)
+		}
+		add(b)
+	}
+	return points
+}
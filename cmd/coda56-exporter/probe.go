@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// probeHandler serves /probe?target=<modem URL>, following the blackbox and
+// snmp exporter convention so one exporter can scrape several modems. Each
+// request gets a fresh collector and registry, so only the target's metrics
+// are returned, all carrying a target label.
+//
+// /probe?modem=<name> instead returns the metrics of a modem from the config
+// file, as it is polled for /metrics, without the labels /sd/targets gives
+// Prometheus for it.
+type probeHandler struct {
+	mu   sync.Mutex
+	cfg  *Config
+	opts CollectorOptions
+	// named returns the collector of the modem with the given name in the
+	// config file, or nil.
+	named func(name string) *MetricsCollector
+
+	// Clients are kept per target so conditional-request validators and
+	// optional-endpoint detection survive between scrapes. Targets are
+	// whatever callers ask for, so only the most recently used
+	// maxProbeClients are kept.
+	clients map[string]*probeClient
+}
+
+// maxProbeClients bounds the clients kept for /probe targets.
+const maxProbeClients = 64
+
+type probeClient struct {
+	client *hitron.ModemClient
+	used   time.Time
+}
+
+func newProbeHandler(cfg *Config) *probeHandler {
+	h := &probeHandler{}
+	h.configure(cfg)
+	return h
+}
+
+// configure changes the settings used for probes, dropping cached clients.
+// Probed modems share the timeout, collectors and credentials of cfg.
+func (h *probeHandler) configure(cfg *Config) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.cfg = cfg
+	h.opts = cfg.collectorOptions()
+	// Each probe is a fresh collector with no history to judge a modem's
+	// signal or failures by, so probed modems are never rebooted
+	// automatically and have no watchdog.
+	h.opts.RebootPolicy = RebootPolicy{}
+	h.opts.Watchdog = WatchdogPolicy{}
+	h.clients = map[string]*probeClient{}
+}
+
+func (h *probeHandler) client(target string) (*hitron.ModemClient, CollectorOptions, *Config) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	c, ok := h.clients[target]
+	if !ok {
+		if len(h.clients) >= maxProbeClients {
+			h.evictOldest()
+		}
+		c = &probeClient{client: h.cfg.newClient(target, h.credentials(target)...)}
+		h.clients[target] = c
+	}
+	c.used = time.Now()
+	return c.client, h.opts, h.cfg
+}
+
+// credentials returns the options for logging in to target. /probe is open
+// to anyone who can reach the exporter and the target is theirs to choose,
+// so the modem credentials are only sent to the modem at modem_host, never
+// to a host that merely asks for them with a login page.
+func (h *probeHandler) credentials(target string) []hitron.Option {
+	host, _ := hitron.NormalizeBaseURL(h.cfg.ModemHost)
+	if u, err := hitron.NormalizeBaseURL(target); err == nil && u == host {
+		return nil
+	}
+	return []hitron.Option{hitron.WithCredentials("", "")}
+}
+
+// evictOldest drops the least recently used client and closes its idle
+// connections.
+func (h *probeHandler) evictOldest() {
+	var oldest string
+	for target, c := range h.clients {
+		if oldest == "" || c.used.Before(h.clients[oldest].used) {
+			oldest = target
+		}
+	}
+	h.clients[oldest].client.ResetConnections()
+	delete(h.clients, oldest)
+}
+
+func validateTarget(target string) error {
+	if target == "" {
+		return fmt.Errorf("target parameter is missing")
+	}
+	_, err := hitron.NormalizeBaseURL(target)
+	return err
+}
+
+func (h *probeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Has("modem") {
+		h.serveModem(w, r)
+		return
+	}
+	target := r.URL.Query().Get("target")
+	if err := validateTarget(target); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	client, opts, cfg := h.client(target)
+	labels := prometheus.Labels{"target": target}
+	for k, v := range cfg.Labels {
+		labels[k] = v
+	}
+	ctx, cancel := scrapeContext(r, *scrapeTimeoutOffset)
+	defer cancel()
+	reg := prometheus.NewRegistry()
+	cfg.registerer(reg, labels).MustRegister(
+		scrapeCollector{NewMetricsCollector(client, nil, opts), ctx},
+	)
+	promhttp.HandlerFor(reg, exposition()).ServeHTTP(w, r)
+}
+
+// serveModem answers /probe?modem=<name>.
+func (h *probeHandler) serveModem(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("modem")
+	if r.URL.Query().Has("target") {
+		http.Error(w, "target and modem parameters can't be combined", http.StatusBadRequest)
+		return
+	}
+	var c *MetricsCollector
+	if name != "" && h.named != nil {
+		c = h.named(name)
+	}
+	if c == nil {
+		http.Error(w, fmt.Sprintf("unknown modem %q", name), http.StatusNotFound)
+		return
+	}
+	ctx, cancel := scrapeContext(r, *scrapeTimeoutOffset)
+	defer cancel()
+	reg := prometheus.NewRegistry()
+	h.mu.Lock()
+	cfg := h.cfg
+	h.mu.Unlock()
+	cfg.registerer(reg, nil).MustRegister(scrapeCollector{c, ctx})
+	promhttp.HandlerFor(reg, exposition()).ServeHTTP(w, r)
+}
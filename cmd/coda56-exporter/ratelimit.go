@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// tokenBucket is a classic token bucket refilled continuously at rate per second.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// ClientRateLimiter limits each client address to a steady request rate with
+// a burst allowance, so a runaway curl loop can't starve the modem poller.
+type ClientRateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func NewClientRateLimiter(rate float64, burst int) *ClientRateLimiter {
+	return &ClientRateLimiter{
+		rate:    rate,
+		burst:   float64(max(burst, 1)),
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+// allow reports whether a request from client may proceed and, if not, how
+// long until a token becomes available.
+func (l *ClientRateLimiter) allow(client string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Forget clients whose buckets have been full for a while
+	if now.Sub(l.lastSweep) > time.Minute {
+		for k, b := range l.buckets {
+			if now.Sub(b.last).Seconds()*l.rate > l.burst {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+func clientAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Wrap rejects requests over the limit with 429 Too Many Requests.
+func (l *ClientRateLimiter) Wrap(next http.Handler) http.Handler {
+	if l == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok, wait := l.allow(clientAddr(r), time.Now())
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, fmt.Sprintf("rate limit exceeded, retry in %s", wait.Round(time.Millisecond)), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
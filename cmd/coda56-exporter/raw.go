@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// registerRawHandler serves the body of each data page from the modem's
+// most recent poll at /raw/{endpoint}, such as /raw/dsinfo.asp, so that
+// debugging tools can read it without a session of their own on the modem.
+// The modem query parameter selects a modem from the config file.
+func registerRawHandler(mux *http.ServeMux, client func(name string) *hitron.ModemClient, auth *APIAuth) {
+	mux.Handle("GET /raw/{endpoint}", auth.Require(ScopeRead, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("modem")
+		c := client(name)
+		if c == nil {
+			writeJSONError(w, http.StatusNotFound, fmt.Errorf("unknown modem %q", name))
+			return
+		}
+		endpoint := r.PathValue("endpoint")
+		body, fetched, ok := c.Latest(endpoint)
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, fmt.Errorf("%s has not been fetched from the modem", endpoint))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Last-Modified", fetched.UTC().Format(http.TimeFormat))
+		w.Header().Set("Age", fmt.Sprint(int(time.Since(fetched).Seconds())))
+		w.Write(body)
+	})))
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ready reports whether every modem has been polled in full, with no page
+// failing, at least once. Standby HA instances don't poll and count as
+// ready. Without a background poller, modems not yet polled are polled now.
+// Once ready, the exporter stays ready across configuration reloads.
+func (e *exporter) ready(ctx context.Context) bool {
+	if e.wasReady.Load() {
+		return true
+	}
+	collectors := e.collectors.Load()
+	if collectors == nil {
+		return false
+	}
+	for _, c := range *collectors {
+		if !c.polledOK.Load() && c.isLeader() {
+			c.pollOnDemand(ctx)
+		}
+		if !c.polledOK.Load() && c.isLeader() {
+			return false
+		}
+	}
+	e.wasReady.Store(true)
+	return true
+}
+
+// degraded reports whether the watchdog has given up on any modem this
+// instance polls; see WatchdogPolicy.
+func (e *exporter) degraded() bool {
+	collectors := e.collectors.Load()
+	if collectors == nil {
+		return false
+	}
+	for _, c := range *collectors {
+		if c.watchdog.isDegraded() {
+			return true
+		}
+	}
+	return false
+}
+
+// registerHealthHandlers serves /-/healthy, which answers as long as the
+// process does, and /-/ready, which fails until the modem has been polled
+// successfully, and again while the watchdog reports the exporter degraded.
+func registerHealthHandlers(mux *http.ServeMux, e *exporter) {
+	mux.HandleFunc("/-/healthy", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "OK")
+	})
+	mux.HandleFunc("/-/ready", func(w http.ResponseWriter, r *http.Request) {
+		if e.degraded() {
+			http.Error(w, "The modem keeps failing to answer; the exporter is degraded", http.StatusServiceUnavailable)
+			return
+		}
+		if !e.ready(r.Context()) {
+			http.Error(w, "The modem has not been polled successfully yet", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "OK")
+	})
+}
+
+// requireReady answers with 503 Service Unavailable until the exporter is
+// ready, so that the first scrapes don't return empty modem metrics. It
+// doesn't apply without a background poller, where scrapes poll the modem
+// themselves.
+func requireReady(e *exporter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if e.config.Load().background() && !e.ready(r.Context()) {
+			http.Error(w, "The modem has not been polled successfully yet", http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// Recordings are stored one response per file, named
+// "<time>_<endpoint>" so that sorting the names orders them by time.
+const recordTimeFormat = "20060102T150405.000000000Z"
+
+// captureOptions returns the client options for -record and -replay. With
+// several modems each one gets its own subdirectory, named after it.
+func captureOptions(cfg *Config, modem string) ([]hitron.Option, error) {
+	if *recordDir != "" && *replayDir != "" {
+		return nil, errors.New("-record and -replay can't be combined")
+	}
+	switch {
+	case *recordDir != "":
+		dir := filepath.Join(*recordDir, modem)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create recording directory: %w", err)
+		}
+		return []hitron.Option{hitron.WithResponseHook((&recorder{dir: dir}).record)}, nil
+	case *replayDir != "":
+		src, err := NewReplaySource(filepath.Join(*replayDir, modem), *cfg.Collectors.CombinedEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		return []hitron.Option{hitron.WithDataSource(src)}, nil
+	}
+	return nil, nil
+}
+
+// recorder saves the raw body of every data page the modem returns.
+type recorder struct {
+	dir string
+}
+
+func (r *recorder) record(endpoint string, body []byte) {
+	if !ingestEndpointRe.MatchString(endpoint) {
+		slog.Warn("Not recording page with an unexpected name", "endpoint", endpoint)
+		return
+	}
+	name := time.Now().UTC().Format(recordTimeFormat) + "_" + endpoint
+	if err := os.WriteFile(filepath.Join(r.dir, name), body, 0o644); err != nil {
+		slog.Warn("Failed to record modem response", "endpoint", endpoint, "err", err)
+	}
+}
+
+// ReplaySource serves responses saved by -record in the order they were
+// recorded. Each endpoint is replayed independently, so every poll gets the
+// next recording of each page; once a page's recordings run out, fetching
+// it fails. Recordings of the combined status page are split into the pages
+// they carry.
+type ReplaySource struct {
+	mu    sync.Mutex
+	pages map[string][][]byte
+	next  map[string]int
+}
+
+func NewReplaySource(dir, combined string) (*ReplaySource, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recordings: %w", err)
+	}
+	// ReadDir sorts by name, and so by time.
+	s := &ReplaySource{pages: map[string][][]byte{}, next: map[string]int{}}
+	count := 0
+	for _, entry := range entries {
+		stamp, endpoint, ok := strings.Cut(entry.Name(), "_")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		if _, err := time.Parse(recordTimeFormat, stamp); err != nil {
+			continue
+		}
+		body, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recording: %w", err)
+		}
+		count++
+		if endpoint == combined {
+			pages, err := hitron.SplitCombined(body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse recording %s: %w", entry.Name(), err)
+			}
+			for page, payload := range pages {
+				s.pages[page] = append(s.pages[page], payload)
+			}
+			continue
+		}
+		s.pages[endpoint] = append(s.pages[endpoint], body)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("no recordings found in %s", dir)
+	}
+
+	endpoints := make([]string, 0, len(s.pages))
+	for endpoint := range s.pages {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	for _, endpoint := range endpoints {
+		slog.Info("Loaded recordings", "endpoint", endpoint, "count", len(s.pages[endpoint]), "dir", dir)
+	}
+	return s, nil
+}
+
+func (s *ReplaySource) Fetch(endpoint string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	recordings, ok := s.pages[endpoint]
+	if !ok {
+		// Like a page the firmware doesn't have, so optional collectors
+		// switch themselves off.
+		return nil, fmt.Errorf("%w: %s", hitron.ErrEndpointNotFound, endpoint)
+	}
+	i := s.next[endpoint]
+	if i >= len(recordings) {
+		return nil, fmt.Errorf("replay of %s finished after %d recordings", endpoint, len(recordings))
+	}
+	s.next[endpoint] = i + 1
+	return recordings[i], nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// Sample log formats.
+const (
+	sampleLogJSONL = "jsonl"
+	sampleLogCSV   = "csv"
+)
+
+// sampleLogHeader is the first line of every CSV file.
+var sampleLogHeader = []string{"time", "modem", "measurement", "channel", "field", "value"}
+
+func init() {
+	registerSink("sample_log", sinkType{
+		fromFlags: func(cfg *Config) (sink, error) {
+			if *sampleLogPath == "" {
+				return nil, nil
+			}
+			return newSampleLogSink(*sampleLogPath, *sampleLogFormat, int64(*sampleLogMaxSize)<<20, *sampleLogMaxFiles)
+		},
+		fromConfig: func(cfg *Config, node *yaml.Node) (sink, error) {
+			s := sampleLogSettings{Format: *sampleLogFormat, MaxSizeMB: *sampleLogMaxSize, MaxFiles: *sampleLogMaxFiles}
+			if err := decodeSinkSettings(node, &s); err != nil {
+				return nil, err
+			}
+			if s.Path == "" {
+				return nil, fmt.Errorf("path is required")
+			}
+			return newSampleLogSink(s.Path, s.Format, int64(s.MaxSizeMB)<<20, s.MaxFiles)
+		},
+	})
+}
+
+// sampleLogSettings configure a sample log, as with the -log-samples.*
+// flags.
+type sampleLogSettings struct {
+	Path      string `yaml:"path"`
+	Format    string `yaml:"format"`
+	MaxSizeMB int    `yaml:"max_size_mb"`
+	MaxFiles  int    `yaml:"max_files"`
+}
+
+// sampleLogSink appends every poll's values to a file, for handing raw
+// signal logs to the provider. The file is rotated once it would grow past
+// maxSize: path becomes path.1, path.1 becomes path.2 and so on, keeping
+// maxFiles old files.
+type sampleLogSink struct {
+	path     string
+	format   string
+	maxSize  int64
+	maxFiles int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func newSampleLogSink(path, format string, maxSize int64, maxFiles int) (*sampleLogSink, error) {
+	switch format {
+	case sampleLogJSONL, sampleLogCSV:
+	default:
+		return nil, fmt.Errorf("unknown sample log format %q (want %s or %s)", format, sampleLogJSONL, sampleLogCSV)
+	}
+	if maxSize < 0 || maxFiles < 0 {
+		return nil, fmt.Errorf("sample log size and file limits must not be negative")
+	}
+	return &sampleLogSink{path: path, format: format, maxSize: maxSize, maxFiles: maxFiles}, nil
+}
+
+func (s *sampleLogSink) Name() string { return "sample log" }
+
+func (s *sampleLogSink) Write(snap *hitron.Snapshot, tags map[string]string) error {
+	var buf bytes.Buffer
+	ts := snap.Time.UTC().Format(time.RFC3339)
+	switch s.format {
+	case sampleLogCSV:
+		w := csv.NewWriter(&buf)
+		for _, p := range snapshotPoints(snap) {
+			channel := ""
+			for _, t := range p.tags {
+				if identityTags[t.key] {
+					channel = t.value
+				}
+			}
+			for _, f := range p.fields {
+				w.Write([]string{ts, tags["modem"], p.measurement, channel, f.key, strconv.FormatFloat(f.value, 'f', -1, 64)})
+			}
+		}
+		w.Flush()
+	default:
+		enc := json.NewEncoder(&buf)
+		for _, p := range snapshotPoints(snap) {
+			pointTags := map[string]string{}
+			for _, t := range p.tags {
+				pointTags[t.key] = t.value
+			}
+			values := map[string]float64{}
+			for _, f := range p.fields {
+				values[f.key] = f.value
+			}
+			enc.Encode(struct {
+				Time        string             `json:"time"`
+				Labels      map[string]string  `json:"labels,omitempty"`
+				Measurement string             `json:"measurement"`
+				Tags        map[string]string  `json:"tags,omitempty"`
+				Values      map[string]float64 `json:"values"`
+			}{ts, tags, p.measurement, pointTags, values})
+		}
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file != nil && s.maxSize > 0 && s.size > 0 && s.size+int64(buf.Len()) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return fmt.Errorf("failed to rotate sample log: %w", err)
+		}
+	}
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return fmt.Errorf("failed to open sample log: %w", err)
+		}
+	}
+	n, err := s.file.Write(buf.Bytes())
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write sample log: %w", err)
+	}
+	return nil
+}
+
+// open opens the log for appending, starting a CSV file with its header.
+func (s *sampleLogSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.file, s.size = f, info.Size()
+	if s.size == 0 && s.format == sampleLogCSV {
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		w.Write(sampleLogHeader)
+		w.Flush()
+		n, err := f.Write(buf.Bytes())
+		s.size += int64(n)
+		return err
+	}
+	return nil
+}
+
+// rotate closes the log and shifts the old files along, dropping the
+// oldest.
+func (s *sampleLogSink) rotate() error {
+	s.file.Close()
+	s.file = nil
+	if s.maxFiles == 0 {
+		return os.Remove(s.path)
+	}
+	os.Remove(fmt.Sprintf("%s.%d", s.path, s.maxFiles))
+	for i := s.maxFiles - 1; i >= 1; i-- {
+		old := fmt.Sprintf("%s.%d", s.path, i)
+		if _, err := os.Stat(old); err == nil {
+			if err := os.Rename(old, fmt.Sprintf("%s.%d", s.path, i+1)); err != nil {
+				return err
+			}
+		}
+	}
+	return os.Rename(s.path, s.path+".1")
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// scrapeCollector polls the modem, when it is polled on scrape, with the
+// context of a single scrape.
+type scrapeCollector struct {
+	*MetricsCollector
+	ctx context.Context
+}
+
+func (s scrapeCollector) Collect(ch chan<- prometheus.Metric) {
+	s.collect(s.ctx, ch)
+}
+
+// scrapeContext returns the context for polling the modem during r. When
+// Prometheus sends its scrape timeout, the context ends offset before it, so
+// that a slow modem yields a partial result, with the failed pages marked
+// down, instead of a failed scrape.
+func scrapeContext(r *http.Request, offset time.Duration) (context.Context, context.CancelFunc) {
+	seconds, err := strconv.ParseFloat(r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds"), 64)
+	if err != nil || seconds <= 0 {
+		return context.WithCancel(r.Context())
+	}
+	timeout := time.Duration(seconds * float64(time.Second))
+	if timeout > offset {
+		timeout -= offset
+	}
+	return context.WithTimeout(r.Context(), timeout)
+}
+
+// exposition returns the settings of the /metrics and /probe handlers.
+// OpenMetrics is off by default: scrapers prefer it when offered, and in
+// its text format counters whose name doesn't end in _total, such as the
+// modem's, lose their type and created timestamp.
+func exposition() promhttp.HandlerOpts {
+	return promhttp.HandlerOpts{
+		EnableOpenMetrics:                   *webOpenMetrics,
+		EnableOpenMetricsTextCreatedSamples: *webOpenMetrics,
+	}
+}
+
+// withTimestamp returns a channel that passes the metrics sent to it on to
+// ch with the timestamp t, and a function that waits for them to be passed
+// on once all have been sent.
+func withTimestamp(ch chan<- prometheus.Metric, t time.Time) (chan<- prometheus.Metric, func()) {
+	in := make(chan prometheus.Metric)
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		for m := range in {
+			ch <- prometheus.NewMetricWithTimestamp(t, m)
+		}
+	}()
+	return in, func() {
+		close(in)
+		<-finished
+	}
+}
+
+// scrapeHandler serves the gatherer returned by gatherer for each request,
+// polling with the scrape's context. At most maxInFlight requests are served
+// at once; 0 means no limit.
+func scrapeHandler(gatherer func(ctx context.Context) prometheus.Gatherer, offset time.Duration, maxInFlight int, opts promhttp.HandlerOpts) http.Handler {
+	var inFlight chan struct{}
+	if maxInFlight > 0 {
+		inFlight = make(chan struct{}, maxInFlight)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if inFlight != nil {
+			select {
+			case inFlight <- struct{}{}:
+				defer func() { <-inFlight }()
+			default:
+				http.Error(w, fmt.Sprintf("Limit of concurrent requests reached (%d), try again later.", maxInFlight), http.StatusServiceUnavailable)
+				return
+			}
+		}
+		ctx, cancel := scrapeContext(r, offset)
+		defer cancel()
+		ctx, span := tracer.Start(ctx, "scrape")
+		defer span.End()
+		promhttp.HandlerFor(gatherer(ctx), opts).ServeHTTP(w, r)
+	})
+}
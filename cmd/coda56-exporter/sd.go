@@ -0,0 +1,48 @@
+package main
+
+import (
+	"net/http"
+)
+
+// sdTargetGroup is a target group in the format of Prometheus's HTTP service
+// discovery.
+type sdTargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// sdTargets lists a target group per modem of the config file, for
+// scraping each of them through /probe?modem=<name> at host, the address
+// the exporter was reached at. The groups carry the modem's labels, which
+// the probe leaves off its metrics.
+func sdTargets(collectors []*MetricsCollector, host string) []sdTargetGroup {
+	groups := []sdTargetGroup{}
+	for _, c := range collectors {
+		name := c.tags["modem"]
+		if name == "" {
+			continue
+		}
+		labels := map[string]string{
+			"__metrics_path__": "/probe",
+			"__param_modem":    name,
+		}
+		for k, v := range c.tags {
+			labels[k] = v
+		}
+		groups = append(groups, sdTargetGroup{Targets: []string{host}, Labels: labels})
+	}
+	return groups
+}
+
+// registerSDHandler serves /sd/targets for Prometheus's http_sd_configs, so
+// that the modems of a config file can be scraped as separate targets. It is
+// empty without modems in the config file.
+func registerSDHandler(mux *http.ServeMux, e *exporter) {
+	mux.HandleFunc("GET /sd/targets", func(w http.ResponseWriter, r *http.Request) {
+		var collectors []*MetricsCollector
+		if c := e.collectors.Load(); c != nil {
+			collectors = *c
+		}
+		writeJSON(w, http.StatusOK, sdTargets(collectors, r.Host))
+	})
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// serviceOptions describe the service installed by install-service.
+type serviceOptions struct {
+	// Name is the systemd unit or Windows service name.
+	Name string
+	// Binary is the absolute path of the exporter executable.
+	Binary string
+	// Args are the exporter's command line options.
+	Args []string
+	// User runs the service as an existing account instead of a dynamic
+	// one; systemd only.
+	User string
+}
+
+const defaultServiceName = "coda56-exporter"
+
+// serviceNameRe matches the service names install-service accepts: ones
+// that are valid systemd unit names and stay a single path element.
+var serviceNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,199}$`)
+
+func validateServiceName(name string) error {
+	if !serviceNameRe.MatchString(name) {
+		return fmt.Errorf("invalid service name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// runInstallServiceCommand implements the "install-service" subcommand. The
+// arguments after the subcommand's own options, or after "--", are the
+// exporter options the service is started with.
+func runInstallServiceCommand(args []string) error {
+	fs := flag.NewFlagSet("install-service", flag.ContinueOnError)
+	name := fs.String("name", defaultServiceName, "Service name")
+	binary := fs.String("binary", "", "Path of the exporter executable the service runs (default: this executable)")
+	user := fs.String("user", "", "Run as this existing user instead of a dynamic one (systemd only)")
+	printOnly := fs.Bool("print", false, "Print the systemd unit instead of installing it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := validateServiceName(*name); err != nil {
+		return err
+	}
+	opts := serviceOptions{Name: *name, Binary: *binary, Args: fs.Args(), User: *user}
+	if opts.Binary == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to find the executable: %w", err)
+		}
+		if exe, err = filepath.EvalSymlinks(exe); err != nil {
+			return fmt.Errorf("failed to find the executable: %w", err)
+		}
+		opts.Binary = exe
+	}
+	if !filepath.IsAbs(opts.Binary) {
+		return fmt.Errorf("-binary must be an absolute path")
+	}
+	if *printOnly {
+		fmt.Print(systemdUnit(opts))
+		return nil
+	}
+	return installService(opts)
+}
+
+// runUninstallServiceCommand implements the "uninstall-service" subcommand,
+// which stops and removes a service set up by install-service.
+func runUninstallServiceCommand(args []string) error {
+	fs := flag.NewFlagSet("uninstall-service", flag.ContinueOnError)
+	name := fs.String("name", defaultServiceName, "Service name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := validateServiceName(*name); err != nil {
+		return err
+	}
+	return uninstallService(*name)
+}
+
+// systemdUnit returns a unit file running the exporter with the sandboxing
+// systemd offers. The exporter only needs the network and a state directory,
+// which is also its working directory so the default -snapshot-dir works.
+// Netlink is how Go lists the network interfaces, which a link-local modem
+// address needs, and CAP_NET_RAW is kept for an ICMP probe given in args,
+// since the unprivileged ping socket is usually not open to the service's
+// user.
+func systemdUnit(opts serviceOptions) string {
+	exec := []string{systemdQuote(opts.Binary)}
+	for _, arg := range opts.Args {
+		exec = append(exec, systemdQuote(arg))
+	}
+	user := "DynamicUser=yes"
+	if opts.User != "" {
+		user = "User=" + opts.User
+	}
+	caps := ""
+	if icmpProbeArgs(opts.Args) {
+		caps = "CAP_NET_RAW"
+	}
+	return fmt.Sprintf(`[Unit]
+Description=Hitron CODA56 Prometheus exporter
+Documentation=https://github.com/anupcshan/coda56-exporter
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+ExecStart=%[1]s
+ExecReload=/bin/kill -HUP $MAINPID
+Restart=on-failure
+RestartSec=5s
+%[2]s
+StateDirectory=%[3]s
+WorkingDirectory=/var/lib/%[3]s
+UMask=0077
+
+NoNewPrivileges=yes
+CapabilityBoundingSet=%[4]s
+AmbientCapabilities=%[4]s
+ProtectSystem=strict
+ProtectHome=yes
+PrivateTmp=yes
+PrivateDevices=yes
+ProtectClock=yes
+ProtectHostname=yes
+ProtectKernelTunables=yes
+ProtectKernelModules=yes
+ProtectKernelLogs=yes
+ProtectControlGroups=yes
+RestrictAddressFamilies=AF_INET AF_INET6 AF_UNIX AF_NETLINK
+RestrictNamespaces=yes
+RestrictRealtime=yes
+RestrictSUIDSGID=yes
+LockPersonality=yes
+MemoryDenyWriteExecute=yes
+SystemCallArchitectures=native
+SystemCallFilter=@system-service
+SystemCallFilter=~@privileged
+SystemCallErrorNumber=EPERM
+
+[Install]
+WantedBy=multi-user.target
+`, strings.Join(exec, " "), user, opts.Name, caps)
+}
+
+// icmpProbeArgs reports whether the exporter options args select the ICMP
+// round-trip probe. One set in a config file isn't seen.
+func icmpProbeArgs(args []string) bool {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name != "modem-rtt-probe" || !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			value = args[i+1]
+		}
+		if value == "icmp" {
+			return true
+		}
+	}
+	return false
+}
+
+// systemdQuote quotes an ExecStart argument when it needs it, and escapes
+// the specifiers and variables systemd would otherwise expand.
+func systemdQuote(arg string) string {
+	arg = strings.NewReplacer("%", "%%", "$", "$$").Replace(arg)
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'\\;") {
+		return arg
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+const systemdUnitDir = "/etc/systemd/system"
+
+func unitPath(name string) string {
+	return filepath.Join(systemdUnitDir, name+".service")
+}
+
+// installService writes the systemd unit, then enables and starts it.
+func installService(opts serviceOptions) error {
+	path := unitPath(opts.Name)
+	if err := os.WriteFile(path, []byte(systemdUnit(opts)), 0o644); err != nil {
+		return fmt.Errorf("failed to write unit: %w", err)
+	}
+	slog.Info("Wrote systemd unit", "path", path)
+	if err := systemctl("daemon-reload"); err != nil {
+		return err
+	}
+	if err := systemctl("enable", "--now", opts.Name+".service"); err != nil {
+		return err
+	}
+	slog.Info("Started service", "name", opts.Name)
+	return nil
+}
+
+// uninstallService stops and disables the unit and removes it.
+func uninstallService(name string) error {
+	path := unitPath(name)
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s is not installed", path)
+	}
+	if err := systemctl("disable", "--now", name+".service"); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove unit: %w", err)
+	}
+	slog.Info("Removed systemd unit", "path", path)
+	return systemctl("daemon-reload")
+}
+
+func systemctl(args ...string) error {
+	cmd := exec.Command("systemctl", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("systemctl %s failed: %w", args[0], err)
+	}
+	return nil
+}
+
+// startServiceHandler is only needed on Windows.
+func startServiceHandler() {}
@@ -0,0 +1,17 @@
+//go:build !linux && !windows
+
+package main
+
+import (
+	"errors"
+	"runtime"
+)
+
+var errServiceUnsupported = errors.New("install-service supports systemd on Linux and Windows services, not " + runtime.GOOS + "; use -print for a systemd unit")
+
+func installService(serviceOptions) error { return errServiceUnsupported }
+
+func uninstallService(string) error { return errServiceUnsupported }
+
+// startServiceHandler is only needed on Windows.
+func startServiceHandler() {}
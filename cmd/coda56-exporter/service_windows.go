@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// installService registers the exporter with the service manager, to start
+// automatically and be restarted if it fails, and starts it.
+func installService(opts serviceOptions) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the service manager: %w", err)
+	}
+	defer m.Disconnect()
+	if s, err := m.OpenService(opts.Name); err == nil {
+		s.Close()
+		return fmt.Errorf("service %s already exists", opts.Name)
+	}
+	s, err := m.CreateService(opts.Name, opts.Binary, mgr.Config{
+		DisplayName: "Hitron CODA56 Prometheus exporter",
+		Description: "Exports the signal and error statistics of a Hitron cable modem to Prometheus.",
+		StartType:   mgr.StartAutomatic,
+	}, opts.Args...)
+	if err != nil {
+		return fmt.Errorf("failed to create service: %w", err)
+	}
+	defer s.Close()
+	restart := mgr.RecoveryAction{Type: mgr.ServiceRestart, Delay: 5 * time.Second}
+	if err := s.SetRecoveryActions([]mgr.RecoveryAction{restart, restart, restart}, uint32((24 * time.Hour).Seconds())); err != nil {
+		slog.Warn("Failed to set service recovery actions", "err", err)
+	}
+	slog.Info("Installed service", "name", opts.Name)
+	if err := s.Start(); err != nil {
+		return fmt.Errorf("failed to start service: %w", err)
+	}
+	slog.Info("Started service", "name", opts.Name)
+	return nil
+}
+
+// uninstallService stops the service, if it is running, and removes it.
+func uninstallService(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the service manager: %w", err)
+	}
+	defer m.Disconnect()
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	defer s.Close()
+	if _, err := s.Control(svc.Stop); err != nil {
+		slog.Debug("Service was not running", "name", name, "err", err)
+	}
+	if err := s.Delete(); err != nil {
+		return fmt.Errorf("failed to remove service: %w", err)
+	}
+	slog.Info("Removed service", "name", name)
+	return nil
+}
+
+// startServiceHandler answers the service manager when the exporter runs as
+// a Windows service, which otherwise kills it for not reporting in. The
+// exporter itself keeps running on the main goroutine, and the process exits
+// when the service is stopped.
+func startServiceHandler() {
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		return
+	}
+	go func() {
+		if err := svc.Run(defaultServiceName, windowsService{}); err != nil {
+			fatal("Failed to run as a service", "err", err)
+		}
+		os.Exit(0)
+	}()
+}
+
+type windowsService struct{}
+
+func (windowsService) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for r := range requests {
+		switch r.Cmd {
+		case svc.Interrogate:
+			status <- r.CurrentStatus
+		case svc.Stop, svc.Shutdown:
+			status <- svc.Status{State: svc.StopPending}
+			return false, 0
+		}
+	}
+	return false, 0
+}
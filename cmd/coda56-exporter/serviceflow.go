@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// optionalEndpoint is a data page that not every firmware serves. The first
+// 404 disables it so unsupported modems aren't asked again on every scrape.
+type optionalEndpoint struct {
+	name    string
+	missing atomic.Bool
+}
+
+func (e *optionalEndpoint) enabled() bool {
+	return e != nil && e.name != "" && !e.missing.Load()
+}
+
+// check records whether err means the page doesn't exist on this firmware.
+func (e *optionalEndpoint) check(err error) {
+	if errors.Is(err, hitron.ErrEndpointNotFound) {
+		slog.Info("Endpoint is not exposed by this firmware, disabling it", "endpoint", e.name)
+		e.missing.Store(true)
+	}
+}
+
+// serviceFlowCollector exports the upstream and downstream service flow
+// tables. Flows come and go as the CMTS reprovisions the modem, so metrics are
+// rebuilt from the latest poll rather than kept in long-lived vectors.
+type serviceFlowCollector struct {
+	upstream   *optionalEndpoint
+	downstream *optionalEndpoint
+
+	mu              sync.Mutex
+	upstreamFlows   []hitron.UpstreamServiceFlow
+	downstreamFlows []hitron.DownstreamServiceFlow
+
+	usScheduled *prometheus.Desc
+	usGranted   *prometheus.Desc
+	usT3        *prometheus.Desc
+
+	dsMaxRate     *prometheus.Desc
+	dsMaxBurst    *prometheus.Desc
+	dsMinReserved *prometheus.Desc
+	dsPackets     *prometheus.Desc
+	dsOctets      *prometheus.Desc
+}
+
+func newServiceFlowCollector(upstreamEndpoint, downstreamEndpoint string) *serviceFlowCollector {
+	usLabels := []string{"sfid", "sid", "schedule_type"}
+	dsLabels := []string{"sfid"}
+	return &serviceFlowCollector{
+		upstream:   &optionalEndpoint{name: upstreamEndpoint},
+		downstream: &optionalEndpoint{name: downstreamEndpoint},
+		usScheduled: prometheus.NewDesc(
+			"upstream_service_flow_scheduled_bps",
+			"Provisioned maximum sustained rate of the upstream service flow in bits per second",
+			usLabels, nil,
+		),
+		usGranted: prometheus.NewDesc(
+			"upstream_service_flow_granted_bps",
+			"Bandwidth currently granted to the upstream service flow in bits per second",
+			usLabels, nil,
+		),
+		usT3: prometheus.NewDesc(
+			"upstream_service_flow_t3_timeouts",
+			"Number of T3 (ranging request) retries reported for the upstream service flow",
+			usLabels, nil,
+		),
+		dsMaxRate: prometheus.NewDesc(
+			"downstream_service_flow_max_rate_bps",
+			"Provisioned maximum sustained rate of the downstream service flow in bits per second",
+			dsLabels, nil,
+		),
+		dsMaxBurst: prometheus.NewDesc(
+			"downstream_service_flow_max_burst_bytes",
+			"Provisioned maximum traffic burst of the downstream service flow in bytes",
+			dsLabels, nil,
+		),
+		dsMinReserved: prometheus.NewDesc(
+			"downstream_service_flow_min_reserved_rate_bps",
+			"Provisioned minimum reserved rate of the downstream service flow in bits per second",
+			dsLabels, nil,
+		),
+		dsPackets: prometheus.NewDesc(
+			"downstream_service_flow_packets_total",
+			"Packets the modem has counted on the downstream service flow",
+			dsLabels, nil,
+		),
+		dsOctets: prometheus.NewDesc(
+			"downstream_service_flow_octets_total",
+			"Bytes the modem has counted on the downstream service flow",
+			dsLabels, nil,
+		),
+	}
+}
+
+func (c *serviceFlowCollector) update(ctx context.Context, client *hitron.ModemClient) {
+	if c.upstream.enabled() {
+		flows, err := client.GetUpstreamServiceFlows(ctx, c.upstream.name)
+		if err != nil {
+			slog.Warn("Failed to get upstream service flows", "err", err)
+			c.upstream.check(err)
+			flows = nil
+		}
+		c.mu.Lock()
+		c.upstreamFlows = flows
+		c.mu.Unlock()
+	}
+
+	if c.downstream.enabled() {
+		flows, err := client.GetDownstreamServiceFlows(ctx, c.downstream.name)
+		if err != nil {
+			slog.Warn("Failed to get downstream service flows", "err", err)
+			c.downstream.check(err)
+			flows = nil
+		}
+		c.mu.Lock()
+		c.downstreamFlows = flows
+		c.mu.Unlock()
+	}
+}
+
+func (c *serviceFlowCollector) describe(ch chan<- *prometheus.Desc) {
+	ch <- c.usScheduled
+	ch <- c.usGranted
+	ch <- c.usT3
+	ch <- c.dsMaxRate
+	ch <- c.dsMaxBurst
+	ch <- c.dsMinReserved
+	ch <- c.dsPackets
+	ch <- c.dsOctets
+}
+
+func (c *serviceFlowCollector) collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, flow := range c.upstreamFlows {
+		labels := []string{strings.TrimSpace(flow.SFID), strings.TrimSpace(flow.SID), strings.TrimSpace(flow.ScheduleType)}
+		if v, err := hitron.ParseRate(flow.MaxTrafficRate); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.usScheduled, prometheus.GaugeValue, v, labels...)
+		}
+		if v, err := hitron.ParseRate(flow.GrantedRate); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.usGranted, prometheus.GaugeValue, v, labels...)
+		}
+		if v, err := strconv.ParseFloat(strings.TrimSpace(flow.T3Timeouts), 64); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.usT3, prometheus.GaugeValue, v, labels...)
+		}
+	}
+
+	for _, flow := range c.downstreamFlows {
+		sfid := strings.TrimSpace(flow.SFID)
+		if v, err := hitron.ParseRate(flow.MaxTrafficRate); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.dsMaxRate, prometheus.GaugeValue, v, sfid)
+		}
+		if v, err := strconv.ParseFloat(strings.TrimSpace(flow.MaxTrafficBurst), 64); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.dsMaxBurst, prometheus.GaugeValue, v, sfid)
+		}
+		if v, err := hitron.ParseRate(flow.MinReservedRate); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.dsMinReserved, prometheus.GaugeValue, v, sfid)
+		}
+		// The modem keeps these totals itself, so they are exported as-is
+		if v, err := strconv.ParseFloat(strings.TrimSpace(flow.Packets), 64); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.dsPackets, prometheus.CounterValue, v, sfid)
+		}
+		if v, err := strconv.ParseFloat(strings.TrimSpace(flow.Octets), 64); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.dsOctets, prometheus.CounterValue, v, sfid)
+		}
+	}
+}
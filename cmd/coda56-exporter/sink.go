@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// sink receives the modem data after every poll, for outputs that are
+// pushed rather than scraped. tags holds the modem's static labels.
+type sink interface {
+	Name() string
+	Write(snap *hitron.Snapshot, tags map[string]string) error
+}
+
+// sinkType is one kind of output. Each registers itself with registerSink
+// from its own file, so adding an output doesn't touch newSinks or the
+// collector.
+type sinkType struct {
+	// fromFlags returns the output set up on the command line, or nil if
+	// its flags leave it off.
+	fromFlags func(cfg *Config) (sink, error)
+	// fromConfig returns an output for an entry under sinks in the config
+	// file, decoding its settings from node.
+	fromConfig func(cfg *Config, node *yaml.Node) (sink, error)
+}
+
+var (
+	sinkTypes = map[string]sinkType{}
+	// sinkOrder is the order the types registered in, which is the order
+	// of their files.
+	sinkOrder []string
+)
+
+// registerSink adds an output type, named as in the type field of a sinks
+// entry. It is called from init functions.
+func registerSink(name string, t sinkType) {
+	if _, ok := sinkTypes[name]; ok {
+		panic("sink type " + name + " registered twice")
+	}
+	sinkTypes[name] = t
+	sinkOrder = append(sinkOrder, name)
+}
+
+// sinkTypeNames lists the registered output types, sorted.
+func sinkTypeNames() []string {
+	names := slices.Clone(sinkOrder)
+	slices.Sort(names)
+	return names
+}
+
+// decodeSinkSettings decodes the settings of a sinks entry into v, which
+// holds the defaults. Unlike the rest of the config file, unknown settings
+// are rejected, since a misspelt one would otherwise silently fall back to
+// its flag.
+func decodeSinkSettings(node *yaml.Node, v any) error {
+	settings := *node
+	settings.Content = nil
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "type" {
+			settings.Content = append(settings.Content, node.Content[i], node.Content[i+1])
+		}
+	}
+	data, err := yaml.Marshal(&settings)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// namespacePrefix is the metrics namespace as a name prefix, such as
+// "hitron_".
+func (c *Config) namespacePrefix() string {
+	if c.Namespace == "" {
+		return ""
+	}
+	return c.Namespace + "_"
+}
+
+// newSinks sets up the outputs enabled on the command line, followed by
+// those listed under sinks in the config file.
+func newSinks(cfg *Config) ([]sink, error) {
+	var sinks []sink
+	for _, name := range sinkOrder {
+		s, err := sinkTypes[name].fromFlags(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if s != nil {
+			sinks = append(sinks, s)
+		}
+	}
+	for i, entry := range cfg.Sinks {
+		// validate has checked the type
+		s, err := sinkTypes[entry.Type].fromConfig(cfg, &entry.node)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sinks entry %d (%s): %w", i+1, entry.Type, err)
+		}
+		sinks = append(sinks, s)
+	}
+	return sinks, nil
+}
+
+// poll updates the metrics and then hands the new data to the sinks. Sinks
+// run in the background so a slow output doesn't hold up scrapes.
+func (c *MetricsCollector) poll(ctx context.Context) {
+	c.update(ctx)
+	if c.counterStore != nil {
+		if err := c.counterStore.save(); err != nil {
+			slog.Warn("Failed to save counter state", "path", c.counterStore.path, "err", err)
+		}
+	}
+	if len(c.sinks) == 0 {
+		return
+	}
+	snap := c.snapshot()
+	if snap == nil {
+		return
+	}
+	for _, s := range c.sinks {
+		go func(s sink) {
+			if err := s.Write(snap, c.tags); err != nil {
+				slog.Warn("Failed to write metrics", "sink", s.Name(), "err", err)
+			}
+		}(s)
+	}
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+var snapshotNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// SnapshotStore persists named snapshots as JSON files in a directory.
+type SnapshotStore struct {
+	dir string
+}
+
+func NewSnapshotStore(dir string) *SnapshotStore {
+	return &SnapshotStore{dir: dir}
+}
+
+func (s *SnapshotStore) path(name string) (string, error) {
+	if !snapshotNameRe.MatchString(name) {
+		return "", fmt.Errorf("invalid snapshot name %q", name)
+	}
+	return filepath.Join(s.dir, name+".json"), nil
+}
+
+func (s *SnapshotStore) Save(snap *hitron.Snapshot) error {
+	path, err := s.path(snap.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated snapshot
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+func (s *SnapshotStore) Load(name string) (*hitron.Snapshot, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %q: %w", name, err)
+	}
+	var snap hitron.Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %q: %w", name, err)
+	}
+	return &snap, nil
+}
+
+// List returns all stored snapshots ordered by capture time.
+func (s *SnapshotStore) List() ([]*hitron.Snapshot, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	var snaps []*hitron.Snapshot
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if e.IsDir() || !ok {
+			continue
+		}
+		snap, err := s.Load(name)
+		if err != nil {
+			return nil, err
+		}
+		snaps = append(snaps, snap)
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Time.Before(snaps[j].Time) })
+	return snaps, nil
+}
+
+// ChannelDelta holds the change in each numeric field of a single channel
+// between two snapshots. Values are only set for fields present on both sides.
+type ChannelDelta struct {
+	Type    string             `json:"type"`
+	Channel string             `json:"channel"`
+	Status  string             `json:"status"` // "changed", "added" or "removed"
+	Deltas  map[string]float64 `json:"deltas,omitempty"`
+}
+
+type SnapshotComparison struct {
+	From     string         `json:"from"`
+	To       string         `json:"to"`
+	Elapsed  string         `json:"elapsed"`
+	Channels []ChannelDelta `json:"channels"`
+}
+
+func parseSnapshotFloat(s string) float64 {
+	v, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return v
+}
+
+// compareChannels diffs two sets of channels keyed by their channel identifier.
+func compareChannels(typ string, from, to map[string]map[string]float64) []ChannelDelta {
+	var keys []string
+	for k := range from {
+		keys = append(keys, k)
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, errA := strconv.Atoi(keys[i])
+		b, errB := strconv.Atoi(keys[j])
+		if errA == nil && errB == nil {
+			return a < b
+		}
+		return keys[i] < keys[j]
+	})
+
+	var deltas []ChannelDelta
+	for _, k := range keys {
+		f, inFrom := from[k]
+		t, inTo := to[k]
+		switch {
+		case !inFrom:
+			deltas = append(deltas, ChannelDelta{Type: typ, Channel: k, Status: "added"})
+		case !inTo:
+			deltas = append(deltas, ChannelDelta{Type: typ, Channel: k, Status: "removed"})
+		default:
+			d := ChannelDelta{Type: typ, Channel: k, Status: "changed", Deltas: map[string]float64{}}
+			for field, v := range t {
+				d.Deltas[field] = v - f[field]
+			}
+			deltas = append(deltas, d)
+		}
+	}
+	return deltas
+}
+
+func downstreamFields(channels []hitron.DownstreamInfo) map[string]map[string]float64 {
+	m := map[string]map[string]float64{}
+	for _, c := range channels {
+		m[c.ChannelID] = map[string]float64{
+			"power_dbmv":     parseSnapshotFloat(c.SignalStrength),
+			"snr_db":         parseSnapshotFloat(c.SNR),
+			"correctables":   parseSnapshotFloat(c.Correcteds),
+			"uncorrectables": parseSnapshotFloat(c.Uncorrect),
+		}
+	}
+	return m
+}
+
+func upstreamFields(channels []hitron.UpstreamInfo) map[string]map[string]float64 {
+	m := map[string]map[string]float64{}
+	for _, c := range channels {
+		m[c.ChannelID] = map[string]float64{
+			"power_dbmv": parseSnapshotFloat(c.SignalStrength),
+		}
+	}
+	return m
+}
+
+func ofdmDownstreamFields(channels []hitron.OFDMDownstreamInfo) map[string]map[string]float64 {
+	m := map[string]map[string]float64{}
+	for _, c := range channels {
+		m[c.Receive] = map[string]float64{
+			"power_dbmv":     parseSnapshotFloat(c.PLCPower),
+			"snr_db":         parseSnapshotFloat(c.SNR),
+			"correctables":   parseSnapshotFloat(c.Correcteds),
+			"uncorrectables": parseSnapshotFloat(c.Uncorrect),
+		}
+	}
+	return m
+}
+
+func ofdmUpstreamFields(channels []hitron.OFDMUpstreamInfo) map[string]map[string]float64 {
+	m := map[string]map[string]float64{}
+	for _, c := range channels {
+		// Disabled OFDMA channels report a zero frequency and carry no signal data
+		if parseSnapshotFloat(c.Frequency) == 0 {
+			continue
+		}
+		m[c.USCHIndex] = map[string]float64{
+			"power_dbmv": parseSnapshotFloat(c.RepPower),
+		}
+	}
+	return m
+}
+
+// CompareSnapshots reports per-channel deltas going from one snapshot to another.
+func CompareSnapshots(from, to *hitron.Snapshot) *SnapshotComparison {
+	cmp := &SnapshotComparison{
+		From:    from.Name,
+		To:      to.Name,
+		Elapsed: to.Time.Sub(from.Time).Round(time.Second).String(),
+	}
+	cmp.Channels = append(cmp.Channels, compareChannels("downstream", downstreamFields(from.Downstream), downstreamFields(to.Downstream))...)
+	cmp.Channels = append(cmp.Channels, compareChannels("upstream", upstreamFields(from.Upstream), upstreamFields(to.Upstream))...)
+	cmp.Channels = append(cmp.Channels, compareChannels("ofdm_downstream", ofdmDownstreamFields(from.OFDMDownstream), ofdmDownstreamFields(to.OFDMDownstream))...)
+	cmp.Channels = append(cmp.Channels, compareChannels("ofdm_upstream", ofdmUpstreamFields(from.OFDMUpstream), ofdmUpstreamFields(to.OFDMUpstream))...)
+	return cmp
+}
+
+// WriteReport prints the comparison as a human-readable table.
+func (c *SnapshotComparison) WriteReport(w io.Writer) {
+	fmt.Fprintf(w, "Comparing %s -> %s (%s apart)\n\n", c.From, c.To, c.Elapsed)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tCHANNEL\tPOWER (dBmV)\tSNR (dB)\tCORRECTABLES\tUNCORRECTABLES")
+	for _, ch := range c.Channels {
+		if ch.Status != "changed" {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t\t\t\n", ch.Type, ch.Channel, ch.Status)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", ch.Type, ch.Channel,
+			formatDelta(ch.Deltas, "power_dbmv", "%+.1f"),
+			formatDelta(ch.Deltas, "snr_db", "%+.1f"),
+			formatDelta(ch.Deltas, "correctables", "%+.0f"),
+			formatDelta(ch.Deltas, "uncorrectables", "%+.0f"))
+	}
+	tw.Flush()
+}
+
+func formatDelta(deltas map[string]float64, field, format string) string {
+	v, ok := deltas[field]
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf(format, v)
+}
+
+// runSnapshotCommand implements the "snapshot" subcommand.
+func runSnapshotCommand(client *hitron.ModemClient, store *SnapshotStore, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: snapshot capture <name> | list | compare <from> <to>")
+	}
+
+	switch args[0] {
+	case "capture":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: snapshot capture <name>")
+		}
+		snap, err := client.CaptureSnapshot(context.Background(), args[1])
+		if err != nil {
+			return err
+		}
+		if err := store.Save(snap); err != nil {
+			return err
+		}
+		fmt.Printf("Captured snapshot %q at %s\n", snap.Name, snap.Time.Format(time.RFC3339))
+		for endpoint, msg := range snap.Errors {
+			fmt.Printf("  warning: %s: %s\n", endpoint, msg)
+		}
+		return nil
+
+	case "list":
+		snaps, err := store.List()
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tCAPTURED\tDOWNSTREAM\tUPSTREAM")
+		for _, s := range snaps {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\n", s.Name, s.Time.Format(time.RFC3339),
+				len(s.Downstream)+len(s.OFDMDownstream), len(s.Upstream)+len(s.OFDMUpstream))
+		}
+		return tw.Flush()
+
+	case "compare":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: snapshot compare <from> <to>")
+		}
+		from, err := store.Load(args[1])
+		if err != nil {
+			return err
+		}
+		to, err := store.Load(args[2])
+		if err != nil {
+			return err
+		}
+		CompareSnapshots(from, to).WriteReport(os.Stdout)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown snapshot command %q", args[0])
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// registerSnapshotHandlers exposes the snapshot workflow under /api/v1/snapshots.
+func registerSnapshotHandlers(mux *http.ServeMux, client func() *hitron.ModemClient, store *SnapshotStore, auth *APIAuth) {
+	mux.Handle("GET /api/v1/snapshots", auth.Require(ScopeRead, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		snaps, err := store.List()
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+		type entry struct {
+			Name string    `json:"name"`
+			Time time.Time `json:"time"`
+		}
+		list := []entry{}
+		for _, s := range snaps {
+			list = append(list, entry{s.Name, s.Time})
+		}
+		writeJSON(w, http.StatusOK, list)
+	})))
+
+	mux.Handle("POST /api/v1/snapshots", auth.Require(ScopeActions, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("name")
+		if !snapshotNameRe.MatchString(name) {
+			writeJSONError(w, http.StatusBadRequest, fmt.Errorf("invalid snapshot name %q", name))
+			return
+		}
+		snap, err := client().CaptureSnapshot(r.Context(), name)
+		if err != nil {
+			writeJSONError(w, http.StatusBadGateway, err)
+			return
+		}
+		if err := store.Save(snap); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, snap)
+	})))
+
+	mux.Handle("GET /api/v1/snapshots/{name}", auth.Require(ScopeRead, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		snap, err := store.Load(r.PathValue("name"))
+		if err != nil {
+			writeJSONError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, snap)
+	})))
+
+	mux.Handle("GET /api/v1/snapshots/compare/{from}/{to}", auth.Require(ScopeRead, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		from, err := store.Load(r.PathValue("from"))
+		if err != nil {
+			writeJSONError(w, http.StatusNotFound, err)
+			return
+		}
+		to, err := store.Load(r.PathValue("to"))
+		if err != nil {
+			writeJSONError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, CompareSnapshots(from, to))
+	})))
+}
@@ -0,0 +1,23 @@
+package main
+
+// SpecRanges are the signal levels counted as within the DOCSIS
+// specification by the *_in_spec metrics. The defaults are the commonly
+// quoted acceptable ranges; ISPs often publish tighter ones.
+type SpecRanges struct {
+	// DownstreamPowerMin and DownstreamPowerMax bound the power of QAM and
+	// OFDM downstream channels in dBmV.
+	DownstreamPowerMin, DownstreamPowerMax float64
+	// DownstreamSNRMin is the lowest acceptable QAM downstream SNR in dB.
+	DownstreamSNRMin float64
+	// UpstreamPowerMin and UpstreamPowerMax bound the transmit power of QAM
+	// and OFDMA upstream channels in dBmV.
+	UpstreamPowerMin, UpstreamPowerMax float64
+}
+
+// inSpec returns 1 if v is within [min, max] and 0 otherwise.
+func inSpec(v, min, max float64) float64 {
+	if v >= min && v <= max {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// counterStore keeps the last value and reset time of every modem counter
+// in the -state-file, so that a restarted or reloaded exporter still notices
+// a counter that went backwards while it was down, and series that reset
+// before keep their created timestamps. Without a path it only carries the
+// counters over configuration reloads.
+type counterStore struct {
+	path string
+
+	mu sync.Mutex
+	// modems holds each modem's trackers by modem name ("" for a single
+	// modem) and then by tracker name.
+	modems map[string]map[string]*counterResets
+	// boots is each modem's last boot time, to notice a reboot while the
+	// exporter was down.
+	boots map[string]time.Time
+	// rebootPolicies holds each modem's automatic reboot state. It only
+	// lasts as long as the process.
+	rebootPolicies map[string]*rebootPolicyState
+}
+
+// counterFile is the layout of the state file.
+type counterFile struct {
+	Modems map[string]map[string]map[string]counterState `json:"modems"`
+	Boots  map[string]time.Time                          `json:"boots"`
+}
+
+func openCounterStore(path string) (*counterStore, error) {
+	s := &counterStore{
+		path:           path,
+		modems:         map[string]map[string]*counterResets{},
+		boots:          map[string]time.Time{},
+		rebootPolicies: map[string]*rebootPolicyState{},
+	}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	var f counterFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		// Starting over only loses resets that happen while the exporter is
+		// down, which is better than not starting.
+		slog.Warn("Ignoring unreadable state file", "path", path, "err", err)
+		return s, nil
+	}
+	for modem, boot := range f.Boots {
+		s.boots[modem] = boot
+	}
+	for modem, trackers := range f.Modems {
+		s.modems[modem] = map[string]*counterResets{}
+		for name, series := range trackers {
+			s.modems[modem][name] = &counterResets{series: series}
+		}
+	}
+	return s, nil
+}
+
+// useCounterStore switches c to the trackers kept in s for its modem, so
+// they carry over from earlier collectors of the same modem. It must be
+// called before c polls, and after its tags are set.
+func (c *MetricsCollector) useCounterStore(s *counterStore) {
+	if s == nil {
+		return
+	}
+	modem := c.tags["modem"]
+	c.dsResets = s.resets(modem, "downstream")
+	c.ofdmDsResets = s.resets(modem, "ofdm_downstream")
+	c.uncorrectablesTotalResets = s.resets(modem, "uncorrectables_total")
+	c.trafficResets = s.resets(modem, "traffic")
+	s.mu.Lock()
+	c.bootTime = s.boots[modem]
+	if s.rebootPolicies[modem] == nil {
+		s.rebootPolicies[modem] = c.autoReboot.state
+	}
+	c.autoReboot.state = s.rebootPolicies[modem]
+	s.mu.Unlock()
+	c.counterStore = s
+}
+
+// setBoot records the boot time of a modem.
+func (s *counterStore) setBoot(modem string, boot time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.boots[modem] = boot
+}
+
+// resets returns the named tracker of a modem, creating it if need be.
+func (s *counterStore) resets(modem, name string) *counterResets {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	trackers := s.modems[modem]
+	if trackers == nil {
+		trackers = map[string]*counterResets{}
+		s.modems[modem] = trackers
+	}
+	r := trackers[name]
+	if r == nil {
+		r = newCounterResets()
+		trackers[name] = r
+	}
+	return r
+}
+
+// save writes every tracker to the state file, if there is one.
+func (s *counterStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := counterFile{Modems: map[string]map[string]map[string]counterState{}, Boots: s.boots}
+	for modem, trackers := range s.modems {
+		f.Modems[modem] = map[string]map[string]counterState{}
+		for name, r := range trackers {
+			// track replaces the map rather than modifying it, so it can be
+			// encoded after unlocking.
+			r.mu.Lock()
+			f.Modems[modem][name] = r.series
+			r.mu.Unlock()
+		}
+	}
+	return writeFileAtomic(s.path, func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(f)
+	})
+}
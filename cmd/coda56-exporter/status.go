@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// recordStatus keeps the data of each page fetched in this poll. Like the
+// metrics, a page that failed keeps its previous data, and the failure is
+// listed in Errors by endpoint. c.mu must be held.
+func (c *MetricsCollector) recordStatus(start time.Time, errs map[hitron.Page]error,
+	ds []hitron.DownstreamInfo, us []hitron.UpstreamInfo,
+	ofdmDs []hitron.OFDMDownstreamInfo, ofdmUs []hitron.OFDMUpstreamInfo,
+	sys *hitron.SystemInfo, link *hitron.LinkStatus) {
+	c.status.Time = start
+	c.status.Errors = nil
+	for page, err := range errs {
+		if err != nil {
+			endpoint := c.client.Endpoint(page)
+			if c.status.Errors == nil {
+				c.status.Errors = map[string]string{}
+			}
+			c.status.Errors[endpoint] = err.Error()
+		}
+	}
+
+	if errs[hitron.PageDownstream] == nil {
+		c.status.Downstream = ds
+	}
+	if errs[hitron.PageUpstream] == nil {
+		c.status.Upstream = us
+	}
+	if errs[hitron.PageOFDMDownstream] == nil {
+		c.status.OFDMDownstream = ofdmDs
+	}
+	if errs[hitron.PageOFDMUpstream] == nil {
+		c.status.OFDMUpstream = ofdmUs
+	}
+	if errs[hitron.PageSystemInfo] == nil {
+		c.status.System = sys
+	}
+	if errs[hitron.PageLinkStatus] == nil {
+		c.status.Link = link
+	}
+}
+
+// Status returns the modem data from the most recent poll, polling first
+// when there is no background poller, or nil if the modem hasn't been polled
+// yet.
+func (c *MetricsCollector) Status(ctx context.Context) *hitron.Snapshot {
+	c.pollOnDemand(ctx)
+	return c.snapshot()
+}
+
+// snapshot returns the data from the most recent poll, or nil if there
+// hasn't been one.
+func (c *MetricsCollector) snapshot() *hitron.Snapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.status.Time.IsZero() {
+		return nil
+	}
+	// Each poll replaces the slices rather than modifying them, so a shallow
+	// copy is safe to encode after the lock is released.
+	status := c.status
+	return &status
+}
+
+// registerStatusHandler exposes the latest parsed modem data at
+// /api/v1/status, for scripts that would rather not parse the metrics.
+func registerStatusHandler(mux *http.ServeMux, collector func() *MetricsCollector, auth *APIAuth) {
+	mux.Handle("GET /api/v1/status", auth.Require(ScopeRead, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := collector().Status(r.Context())
+		if status == nil {
+			writeJSONError(w, http.StatusServiceUnavailable, errors.New("the modem has not been polled yet"))
+			return
+		}
+		writeJSON(w, http.StatusOK, status)
+	})))
+}
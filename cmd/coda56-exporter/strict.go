@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anupcshan/coda56-exporter/pkg/collector"
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// errInvalidValue fails a page in strict mode when one of its values can't
+// be parsed.
+var errInvalidValue = errors.New("invalid value")
+
+// strictCheck collects the first field of a page that fails to parse. The
+// failures are counted in hitron_parse_errors_total as usual.
+type strictCheck struct {
+	parse collector.Parser
+	err   error
+}
+
+func (s *strictCheck) check(field, value string, err error) {
+	if s.parse.Check(field, err) || s.err != nil {
+		return
+	}
+	s.err = fmt.Errorf("%w in %s: %s %q", errInvalidValue, s.parse.Endpoint, field, value)
+}
+
+func (s *strictCheck) number(field, value string) {
+	_, err := hitron.ParseNumber(value)
+	s.check(field, value, err)
+}
+
+func (s *strictCheck) frequency(field, value string) {
+	_, err := hitron.ParseFrequency(value)
+	s.check(field, value, err)
+}
+
+// The check* functions return an error for the first value of a page that
+// update would skip, so that with -strict the page counts as failed rather
+// than being exported with gaps. trackFetched then drops the previous series
+// of a channel page; the link and system pages keep theirs, marked stale.
+
+func (c *MetricsCollector) checkDownstream(channels []hitron.DownstreamInfo) error {
+	s := &strictCheck{parse: c.parser(hitron.PageDownstream)}
+	for _, channel := range channels {
+		s.number("signalStrength", channel.SignalStrength)
+		s.number("snr", channel.SNR)
+		s.frequency("frequency", channel.Frequency)
+		s.number("correcteds", channel.Correcteds)
+		s.number("uncorrect", channel.Uncorrect)
+		_, err := hitron.ParseComplexOctets(channel.DSoctets)
+		s.check("dsoctets", channel.DSoctets, err)
+	}
+	return s.err
+}
+
+func (c *MetricsCollector) checkUpstream(channels []hitron.UpstreamInfo) error {
+	s := &strictCheck{parse: c.parser(hitron.PageUpstream)}
+	for _, channel := range channels {
+		s.number("signalStrength", channel.SignalStrength)
+		s.frequency("frequency", channel.Frequency)
+		s.frequency("bandwidth", channel.Bandwidth)
+		if _, ok := hitron.ParseSCDMAMode(channel.ScdmaMode); !ok {
+			s.check("scdmaMode", channel.ScdmaMode, fmt.Errorf("unrecognized SCDMA mode %q", channel.ScdmaMode))
+		}
+	}
+	return s.err
+}
+
+func (c *MetricsCollector) checkOFDMDownstream(channels []hitron.OFDMDownstreamInfo) error {
+	s := &strictCheck{parse: c.parser(hitron.PageOFDMDownstream)}
+	for _, channel := range channels {
+		s.number("plcpower", channel.PLCPower)
+		s.number("SNR", channel.SNR)
+		s.frequency("Subcarr0freqFreq", channel.Subcarr0freqFreq)
+		s.number("correcteds", channel.Correcteds)
+		s.number("uncorrect", channel.Uncorrect)
+		s.number("dsoctets", channel.DSoctets)
+	}
+	return s.err
+}
+
+func (c *MetricsCollector) checkOFDMUpstream(channels []hitron.OFDMUpstreamInfo) error {
+	s := &strictCheck{parse: c.parser(hitron.PageOFDMUpstream)}
+	for _, channel := range channels {
+		frequency, err := hitron.ParseFrequency(channel.Frequency)
+		s.check("frequency", channel.Frequency, err)
+		// Channels not in use report a frequency of 0, and their levels
+		// aren't exported.
+		if err == nil && frequency > 0 {
+			s.number("repPower", channel.RepPower)
+			s.frequency("channelBw", channel.ChannelBw)
+		}
+	}
+	return s.err
+}
+
+func (c *MetricsCollector) checkSystemInfo(info *hitron.SystemInfo) error {
+	s := &strictCheck{parse: c.parser(hitron.PageSystemInfo)}
+	_, err := hitron.ParseUptime(info.SystemUptime)
+	s.check("systemUptime", info.SystemUptime, err)
+	if strings.TrimSpace(info.SystemTime) != "" {
+		_, err := hitron.ParseSystemTime(info.SystemTime, info.Timezone, time.Local)
+		s.check("systemTime", info.SystemTime, err)
+	}
+	return s.err
+}
+
+func (c *MetricsCollector) checkLinkStatus(link *hitron.LinkStatus) error {
+	s := &strictCheck{parse: c.parser(hitron.PageLinkStatus)}
+	_, err := hitron.ParseLinkSpeed(link.LinkSpeed)
+	s.check("LinkSpeed", link.LinkSpeed, err)
+	return s.err
+}
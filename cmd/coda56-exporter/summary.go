@@ -0,0 +1,37 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// summarize replaces vec's series with the min, max and average of values,
+// labelled by stat. Nothing is exported when there are no values.
+func summarize(vec *prometheus.GaugeVec, values []float64) {
+	vec.Reset()
+	if len(values) == 0 {
+		return
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	vec.WithLabelValues("min").Set(slices.Min(values))
+	vec.WithLabelValues("max").Set(slices.Max(values))
+	vec.WithLabelValues("avg").Set(sum / float64(len(values)))
+}
+
+// sumUncorrectables adds up the uncorrectable counters of every downstream
+// channel, QAM and OFDM, as of the last successful poll of each page.
+func (c *MetricsCollector) sumUncorrectables() []modemCounter {
+	total := 0.0
+	for _, counters := range [][]modemCounter{c.dsCounters, c.ofdmDsCounters} {
+		for _, counter := range counters {
+			if counter.desc == c.downstreamUncorrectables || counter.desc == c.ofdmDownstreamUncorrectables {
+				total += counter.value
+			}
+		}
+	}
+	return []modemCounter{{desc: c.uncorrectablesTotalDesc, value: total}}
+}
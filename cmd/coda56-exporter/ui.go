@@ -0,0 +1,76 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+//go:embed ui/index.html
+var uiPage []byte
+
+// liveData is what the web UI refreshes from: the latest poll, how each
+// endpoint fared in it, and the history behind its charts.
+type liveData struct {
+	Model     string              `json:"model"`
+	Host      string              `json:"host"`
+	Endpoints []endpointStatus    `json:"endpoints"`
+	Status    *hitron.Snapshot    `json:"status"`
+	History   map[string][]sample `json:"history"`
+}
+
+// endpointStatus is the outcome of one data page in the latest poll.
+type endpointStatus struct {
+	Endpoint string `json:"endpoint"`
+	Up       bool   `json:"up"`
+	Error    string `json:"error,omitempty"`
+}
+
+// liveHistory is how much history /api/v1/live returns by default, enough for
+// the charts without sending the whole store every refresh.
+const liveHistory = time.Hour
+
+// registerUIHandlers serves the web UI at / and its data at /api/v1/live.
+// The page itself is public; the data needs the read scope like the rest of
+// /api/v1, and the page asks for a token when it gets a 401.
+func registerUIHandlers(mux *http.ServeMux, collector func() *MetricsCollector, history *historyStore, auth *APIAuth) {
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(uiPage)
+	})
+	mux.Handle("GET /api/v1/live", auth.Require(ScopeRead, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := collector()
+		status := c.Status(r.Context())
+		if status == nil {
+			writeJSONError(w, http.StatusServiceUnavailable, errors.New("the modem has not been polled yet"))
+			return
+		}
+		since := time.Now().Add(-liveHistory)
+		if s := r.URL.Query().Get("since"); s != "" {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				writeJSONError(w, http.StatusBadRequest, errors.New("since must be a duration such as 30m"))
+				return
+			}
+			since = time.Now().Add(-d)
+		}
+		data := liveData{
+			Model:   c.client.Adapter().Model(),
+			Host:    c.client.BaseURL(),
+			Status:  status,
+			History: history.series(c.tags, since),
+		}
+		for _, endpoint := range c.client.Endpoints() {
+			msg, failed := status.Errors[endpoint]
+			data.Endpoints = append(data.Endpoints, endpointStatus{Endpoint: endpoint, Up: !failed, Error: msg})
+		}
+		writeJSON(w, http.StatusOK, data)
+	})))
+}
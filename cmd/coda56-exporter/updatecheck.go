@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// version is set at build time with -ldflags "-X main.version=v1.2.3".
+var version = "dev"
+
+const releasesURL = "https://api.github.com/repos/anupcshan/coda56-exporter/releases/latest"
+
+// UpdateChecker periodically looks up the latest GitHub release and exports
+// whether it is newer than the running build.
+type UpdateChecker struct {
+	client   *http.Client
+	interval time.Duration
+
+	mu     sync.Mutex
+	latest string
+
+	desc *prometheus.Desc
+}
+
+func NewUpdateChecker(interval time.Duration) *UpdateChecker {
+	return &UpdateChecker{
+		client:   &http.Client{Timeout: 30 * time.Second},
+		interval: interval,
+		desc: prometheus.NewDesc(
+			"exporter_update_available",
+			"Whether a newer exporter release is available (1 = yes, 0 = no)",
+			[]string{"current_version", "latest_version"}, nil,
+		),
+	}
+}
+
+func (u *UpdateChecker) check() error {
+	req, err := http.NewRequest(http.MethodGet, releasesURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "coda56-exporter/"+version)
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch latest release: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d fetching latest release", resp.StatusCode)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return fmt.Errorf("failed to parse latest release: %w", err)
+	}
+
+	u.mu.Lock()
+	u.latest = release.TagName
+	u.mu.Unlock()
+	return nil
+}
+
+// Run checks for updates immediately and then on every interval.
+func (u *UpdateChecker) Run(ctx context.Context) {
+	t := time.NewTicker(u.interval)
+	defer t.Stop()
+	for {
+		if err := u.check(); err != nil {
+			slog.Warn("Update check failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// parseVersion splits "v1.2.3" into its numeric components.
+func parseVersion(v string) ([]int, bool) {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
+
+// newerVersion reports whether latest is a higher version than current.
+// Unparseable versions (such as "dev" builds) are never considered outdated.
+func newerVersion(current, latest string) bool {
+	c, ok1 := parseVersion(current)
+	l, ok2 := parseVersion(latest)
+	if !ok1 || !ok2 {
+		return false
+	}
+	for i := 0; i < max(len(c), len(l)); i++ {
+		var a, b int
+		if i < len(c) {
+			a = c[i]
+		}
+		if i < len(l) {
+			b = l[i]
+		}
+		if a != b {
+			return b > a
+		}
+	}
+	return false
+}
+
+func (u *UpdateChecker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- u.desc
+}
+
+func (u *UpdateChecker) Collect(ch chan<- prometheus.Metric) {
+	u.mu.Lock()
+	latest := u.latest
+	u.mu.Unlock()
+	if latest == "" {
+		return
+	}
+
+	available := 0.0
+	if newerVersion(version, latest) {
+		available = 1
+	}
+	ch <- prometheus.MustNewConstMetric(u.desc, prometheus.GaugeValue, available, version, latest)
+}
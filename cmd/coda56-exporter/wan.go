@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// wanCollector exports the addresses the ISP provisioned on the DOCSIS WAN,
+// to follow dual-stack provisioning that comes and goes.
+type wanCollector struct {
+	endpoint *optionalEndpoint
+
+	mu  sync.Mutex
+	wan *hitron.DocsisWAN
+	// leaseExpires is when the DHCPv4 lease runs out, or zero if the page
+	// doesn't say.
+	leaseExpires time.Time
+
+	ipv4Info        *prometheus.Desc
+	ipv6Info        *prometheus.Desc
+	ipv4Provisioned *prometheus.Desc
+	ipv6Provisioned *prometheus.Desc
+	prefixDelegated *prometheus.Desc
+	leaseDuration   *prometheus.Desc
+	leaseRemaining  *prometheus.Desc
+}
+
+func newWANCollector(endpoint string) *wanCollector {
+	return &wanCollector{
+		endpoint: &optionalEndpoint{name: endpoint},
+		ipv4Info: prometheus.NewDesc(
+			"wan_ipv4_info",
+			"Always 1, with the WAN IPv4 address, netmask and gateway as reported",
+			[]string{"address", "netmask", "gateway"}, nil,
+		),
+		ipv6Info: prometheus.NewDesc(
+			"wan_ipv6_info",
+			"Always 1, with the WAN IPv6 address, gateway and delegated prefix as reported",
+			[]string{"address", "gateway", "delegated_prefix"}, nil,
+		),
+		ipv4Provisioned: prometheus.NewDesc(
+			"wan_ipv4_provisioned",
+			"Whether the modem has a WAN IPv4 address (1 = yes, 0 = no)",
+			nil, nil,
+		),
+		ipv6Provisioned: prometheus.NewDesc(
+			"wan_ipv6_provisioned",
+			"Whether the modem has a global WAN IPv6 address (1 = yes, 0 = no)",
+			nil, nil,
+		),
+		prefixDelegated: prometheus.NewDesc(
+			"wan_ipv6_prefix_delegated",
+			"Whether the ISP delegated an IPv6 prefix (1 = yes, 0 = no)",
+			nil, nil,
+		),
+		leaseDuration: prometheus.NewDesc(
+			"wan_dhcp_lease_duration_seconds",
+			"Length of the WAN DHCPv4 lease granted by the ISP",
+			nil, nil,
+		),
+		leaseRemaining: prometheus.NewDesc(
+			"wan_dhcp_lease_remaining_seconds",
+			"Time left until the WAN DHCPv4 lease expires",
+			nil, nil,
+		),
+	}
+}
+
+func (c *wanCollector) update(ctx context.Context, client *hitron.ModemClient) {
+	if !c.endpoint.enabled() {
+		return
+	}
+	wan, err := client.GetDocsisWAN(ctx, c.endpoint.name)
+	fetched := time.Now()
+	var expires time.Time
+	if err != nil {
+		slog.Warn("Failed to get DOCSIS WAN details", "err", err)
+		c.endpoint.check(err)
+		wan = nil
+	} else if remaining, err := wan.LeaseRemaining(fetched, time.Local); err != nil {
+		slog.Debug("Failed to parse DHCP lease expiry", "err", err)
+	} else {
+		// Kept as a time so the remaining time counts down between polls
+		expires = fetched.Add(remaining)
+	}
+	c.mu.Lock()
+	c.wan = wan
+	c.leaseExpires = expires
+	c.mu.Unlock()
+}
+
+func (c *wanCollector) describe(ch chan<- *prometheus.Desc) {
+	ch <- c.ipv4Info
+	ch <- c.ipv6Info
+	ch <- c.ipv4Provisioned
+	ch <- c.ipv6Provisioned
+	ch <- c.prefixDelegated
+	ch <- c.leaseDuration
+	ch <- c.leaseRemaining
+}
+
+func (c *wanCollector) collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.wan == nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.ipv4Info, prometheus.GaugeValue, 1,
+		strings.TrimSpace(c.wan.IPv4Address), strings.TrimSpace(c.wan.IPv4Netmask), strings.TrimSpace(c.wan.IPv4Gateway))
+	ch <- prometheus.MustNewConstMetric(c.ipv6Info, prometheus.GaugeValue, 1,
+		strings.TrimSpace(c.wan.IPv6Address), strings.TrimSpace(c.wan.IPv6Gateway), strings.TrimSpace(c.wan.DelegatedPrefix))
+	for _, f := range []struct {
+		desc *prometheus.Desc
+		ok   bool
+	}{
+		{c.ipv4Provisioned, c.wan.IPv4Provisioned()},
+		{c.ipv6Provisioned, c.wan.IPv6Provisioned()},
+		{c.prefixDelegated, c.wan.PrefixDelegated()},
+	} {
+		v := 0.0
+		if f.ok {
+			v = 1
+		}
+		ch <- prometheus.MustNewConstMetric(f.desc, prometheus.GaugeValue, v)
+	}
+	if d, err := hitron.ParseLeaseTime(c.wan.LeaseDuration); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.leaseDuration, prometheus.GaugeValue, d.Seconds())
+	}
+	if !c.leaseExpires.IsZero() {
+		ch <- prometheus.MustNewConstMetric(c.leaseRemaining, prometheus.GaugeValue, max(time.Until(c.leaseExpires), 0).Seconds())
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// WatchdogPolicy reacts to a modem that keeps failing to answer: some
+// firmware wedges TLS sessions until the client reconnects from scratch.
+// The zero value does nothing.
+type WatchdogPolicy struct {
+	// Polls is how many polls in a row that fetch no page at all set the
+	// watchdog off; 0 disables it.
+	Polls int
+	// ResetConnections makes the watchdog reset the client's connections
+	// and session first, and report the exporter degraded only if the poll
+	// after that fails too. Without it the exporter is reported degraded at
+	// once.
+	ResetConnections bool
+}
+
+func (p WatchdogPolicy) enabled() bool {
+	return p.Polls > 0
+}
+
+// watchdogCollector applies a WatchdogPolicy to every poll.
+type watchdogCollector struct {
+	policy WatchdogPolicy
+
+	mu       sync.Mutex
+	failures int
+	// reset is set once the connections were reset during the current run
+	// of failed polls.
+	reset    bool
+	degraded bool
+	resets   float64
+
+	degradedDesc *prometheus.Desc
+	resetsDesc   *prometheus.Desc
+}
+
+func newWatchdogCollector(policy WatchdogPolicy) *watchdogCollector {
+	return &watchdogCollector{
+		policy: policy,
+		degradedDesc: prometheus.NewDesc(
+			"exporter_degraded",
+			"Whether the modem has failed every poll since the watchdog went off (1 = degraded, 0 = ok)",
+			nil, nil,
+		),
+		resetsDesc: prometheus.NewDesc(
+			"exporter_connection_resets_total",
+			"Times the watchdog reset the modem connections after consecutive failed polls",
+			nil, nil,
+		),
+	}
+}
+
+// update counts one poll, ok if it fetched at least one page. The first
+// poll that does ends the run of failures and clears the degraded state.
+func (c *watchdogCollector) update(client *hitron.ModemClient, ok bool) {
+	if !c.policy.enabled() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ok {
+		if c.degraded {
+			slog.Info("Modem is answering again, no longer degraded", "modem", client.BaseURL())
+		}
+		c.failures, c.reset, c.degraded = 0, false, false
+		return
+	}
+	c.failures++
+	if c.failures < c.policy.Polls || c.degraded {
+		return
+	}
+	if c.policy.ResetConnections && !c.reset {
+		slog.Warn("Modem failed consecutive polls, resetting connections", "modem", client.BaseURL(), "polls", c.failures)
+		c.reset = true
+		c.resets++
+		client.ResetConnections()
+		return
+	}
+	slog.Error("Modem keeps failing, reporting the exporter degraded", "modem", client.BaseURL(), "polls", c.failures)
+	c.degraded = true
+}
+
+// isDegraded reports whether the watchdog has given up on the modem until
+// it answers again.
+func (c *watchdogCollector) isDegraded() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.degraded
+}
+
+func (c *watchdogCollector) describe(ch chan<- *prometheus.Desc) {
+	if c.policy.enabled() {
+		ch <- c.degradedDesc
+		ch <- c.resetsDesc
+	}
+}
+
+func (c *watchdogCollector) collect(ch chan<- prometheus.Metric) {
+	if !c.policy.enabled() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	degraded := 0.0
+	if c.degraded {
+		degraded = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.degradedDesc, prometheus.GaugeValue, degraded)
+	ch <- prometheus.MustNewConstMetric(c.resetsDesc, prometheus.CounterValue, c.resets)
+}
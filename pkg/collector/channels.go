@@ -0,0 +1,260 @@
+package collector
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// Parser parses the numeric fields of one page, counting the values that
+// aren't numbers in parse_errors_total. The caller skips those samples rather
+// than report them as 0.
+type Parser struct {
+	Endpoint string
+	errors   *prometheus.CounterVec
+}
+
+// NewParser returns a parser for the page at endpoint that counts failures
+// in errors, a counter vector with the field as its only variable label, or
+// nowhere if errors is nil.
+func NewParser(endpoint string, errors *prometheus.CounterVec) Parser {
+	return Parser{Endpoint: endpoint, errors: errors}
+}
+
+// Check counts err, if any, against field, and reports whether it is nil.
+func (p Parser) Check(field string, err error) bool {
+	if err != nil {
+		slog.Debug("Skipping unparseable value", "endpoint", p.Endpoint, "field", field, "err", err)
+		if p.errors != nil {
+			p.errors.WithLabelValues(field).Inc()
+		}
+		return false
+	}
+	return true
+}
+
+// Value is a number of a page, with OK false if the modem didn't report it
+// or it didn't parse.
+type Value struct {
+	V  float64
+	OK bool
+}
+
+func (p Parser) number(field, s string) Value {
+	v, err := hitron.ParseNumber(s)
+	return Value{v, p.Check(field, err)}
+}
+
+// byteCount parses a traffic figure. Sources without the field, such as
+// SNMP, leave it empty, which isn't counted as an error.
+func (p Parser) byteCount(field, s string) Value {
+	if strings.TrimSpace(s) == "" {
+		return Value{}
+	}
+	v, err := hitron.ParseByteCount(s)
+	return Value{v, p.Check(field, err)}
+}
+
+func (p Parser) frequency(field, s string) Value {
+	v, err := hitron.ParseFrequency(s)
+	return Value{v, p.Check(field, err)}
+}
+
+// DownstreamChannel is a downstream SC-QAM channel of dsinfo.asp.
+type DownstreamChannel struct {
+	// ID, Frequency and Modulation label the channel's series, the latter
+	// normalized with hitron.NormalizeModulation.
+	ID, Frequency, Modulation string
+
+	Power, SNR, FrequencyHz, ModulationBits Value
+	Correcteds, Uncorrectables, Octets      Value
+	// OctetsMayWrap is set when the octets are a plain number, which may be
+	// a 32-bit count; the split "53 * 2e32 + 4142950845" format carries all
+	// 64 bits.
+	OctetsMayWrap bool
+}
+
+// ParseDownstream parses the values of the downstream SC-QAM channels.
+func ParseDownstream(p Parser, channels []hitron.DownstreamInfo) []DownstreamChannel {
+	out := make([]DownstreamChannel, 0, len(channels))
+	for _, channel := range channels {
+		c := DownstreamChannel{
+			ID:             channel.ChannelID,
+			Frequency:      channel.Frequency,
+			Modulation:     hitron.NormalizeModulation(channel.Modulation),
+			Power:          p.number("signalStrength", channel.SignalStrength),
+			SNR:            p.number("snr", channel.SNR),
+			FrequencyHz:    p.frequency("frequency", channel.Frequency),
+			Correcteds:     p.number("correcteds", channel.Correcteds),
+			Uncorrectables: p.number("uncorrect", channel.Uncorrect),
+			OctetsMayWrap:  !strings.Contains(channel.DSoctets, "*"),
+		}
+		c.ModulationBits.V, c.ModulationBits.OK = hitron.ModulationBits(channel.Modulation)
+		octets, err := hitron.ParseComplexOctets(channel.DSoctets)
+		c.Octets = Value{float64(octets), p.Check("dsoctets", err)}
+		out = append(out, c)
+	}
+	return out
+}
+
+// UpstreamChannel is an upstream SC-QAM channel of usinfo.asp.
+type UpstreamChannel struct {
+	// ID, Frequency and Modulation label the channel's series, the latter
+	// normalized with hitron.NormalizeModulation.
+	ID, Frequency, Modulation string
+	// ScdmaMode is the channel's scdmaMode as the modem reports it.
+	ScdmaMode string
+
+	Power, FrequencyHz, Bandwidth, ModulationBits Value
+	// SymbolRate is the bandwidth field as a plain number, for the
+	// deprecated series that exported it unconverted. It isn't counted as a
+	// parse error a second time.
+	SymbolRate Value
+	// SCDMA is 1 if the channel runs S-CDMA and 0 if it runs ATDMA.
+	SCDMA Value
+}
+
+// ParseUpstream parses the values of the upstream SC-QAM channels.
+func ParseUpstream(p Parser, channels []hitron.UpstreamInfo) []UpstreamChannel {
+	out := make([]UpstreamChannel, 0, len(channels))
+	for _, channel := range channels {
+		c := UpstreamChannel{
+			ID:          channel.ChannelID,
+			Frequency:   channel.Frequency,
+			Modulation:  hitron.NormalizeModulation(channel.ModType),
+			ScdmaMode:   channel.ScdmaMode,
+			Power:       p.number("signalStrength", channel.SignalStrength),
+			FrequencyHz: p.frequency("frequency", channel.Frequency),
+			Bandwidth:   p.frequency("bandwidth", channel.Bandwidth),
+		}
+		if raw, err := hitron.ParseNumber(channel.Bandwidth); err == nil {
+			c.SymbolRate = Value{raw, true}
+		}
+		c.ModulationBits.V, c.ModulationBits.OK = hitron.ModulationBits(channel.ModType)
+		if scdma, ok := hitron.ParseSCDMAMode(channel.ScdmaMode); ok {
+			c.SCDMA = Value{flag(scdma), true}
+		} else {
+			p.Check("scdmaMode", fmt.Errorf("unrecognized SCDMA mode %q", channel.ScdmaMode))
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// OFDMDownstreamChannel is an OFDM receiver of dsofdminfo.asp.
+type OFDMDownstreamChannel struct {
+	// Receive, Frequency and FFTType label the channel's series.
+	Receive, Frequency, FFTType string
+
+	Power, SNR, FrequencyHz            Value
+	Correcteds, Uncorrectables, Octets Value
+	// ModulationBits is the highest modulation order the SNR supports. An
+	// unlocked receiver reports no meaningful SNR, and leaves it out.
+	ModulationBits Value
+	// Width and Spacing are the channel width and subcarrier spacing in Hz,
+	// from the FFT type.
+	Width, Spacing Value
+
+	PLCLock, NCPLock, MDC1Lock bool
+	// PLCLockStatus is the PLC lock as the modem reports it, such as "YES".
+	PLCLockStatus string
+}
+
+// ParseOFDMDownstream parses the values of the OFDM receivers.
+func ParseOFDMDownstream(p Parser, channels []hitron.OFDMDownstreamInfo) []OFDMDownstreamChannel {
+	out := make([]OFDMDownstreamChannel, 0, len(channels))
+	for _, channel := range channels {
+		c := OFDMDownstreamChannel{
+			Receive:        channel.Receive,
+			Frequency:      strings.TrimSpace(channel.Subcarr0freqFreq),
+			FFTType:        channel.FFTType,
+			Power:          p.number("plcpower", channel.PLCPower),
+			SNR:            p.number("SNR", channel.SNR),
+			FrequencyHz:    p.frequency("Subcarr0freqFreq", channel.Subcarr0freqFreq),
+			Correcteds:     p.number("correcteds", channel.Correcteds),
+			Uncorrectables: p.number("uncorrect", channel.Uncorrect),
+			// The simple octet format for OFDM: "53196813856"
+			Octets:   p.number("dsoctets", channel.DSoctets),
+			PLCLock:  strings.TrimSpace(channel.PLCLock) == "YES",
+			NCPLock:  strings.TrimSpace(channel.NCPLock) == "YES",
+			MDC1Lock: strings.TrimSpace(channel.MDC1Lock) == "YES",
+
+			PLCLockStatus: strings.TrimSpace(channel.PLCLock),
+		}
+		if c.SNR.OK && c.PLCLock {
+			c.ModulationBits = Value{hitron.OFDMBitLoading(c.SNR.V), true}
+		}
+		if size, spacing, ok := hitron.ParseFFTType(channel.FFTType); ok {
+			c.Width = Value{float64(size) * spacing, true}
+			c.Spacing = Value{spacing, true}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// OFDMUpstreamChannel is an OFDMA channel of usofdminfo.asp.
+type OFDMUpstreamChannel struct {
+	// USCHIndex, Frequency and State label the channel's series, the latter
+	// trimmed.
+	USCHIndex, Frequency, State string
+	// Operating is set for a channel in the OPERATE state.
+	Operating bool
+
+	// FrequencyHz, Power and Bandwidth are only set for channels in use:
+	// the others report a frequency of 0 and meaningless levels.
+	FrequencyHz, Power, Bandwidth Value
+}
+
+// ParseOFDMUpstream parses the values of the OFDMA channels.
+func ParseOFDMUpstream(p Parser, channels []hitron.OFDMUpstreamInfo) []OFDMUpstreamChannel {
+	out := make([]OFDMUpstreamChannel, 0, len(channels))
+	for _, channel := range channels {
+		state := strings.TrimSpace(channel.State)
+		c := OFDMUpstreamChannel{
+			USCHIndex: channel.USCHIndex,
+			Frequency: channel.Frequency,
+			State:     state,
+			Operating: state == "OPERATE",
+		}
+		if frequency := p.frequency("frequency", channel.Frequency); frequency.OK && frequency.V > 0 {
+			c.FrequencyHz = frequency
+			c.Power = p.number("repPower", channel.RepPower)
+			c.Bandwidth = p.frequency("channelBw", channel.ChannelBw)
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// Traffic is a byte count of getSysInfo.asp.
+type Traffic struct {
+	Interface, Direction string
+	Bytes                Value
+}
+
+// ParseTraffic parses the bytes the modem has counted on its WAN and LAN
+// side.
+func ParseTraffic(p Parser, info *hitron.SystemInfo) []Traffic {
+	var out []Traffic
+	for _, f := range []struct {
+		field, value, iface, direction string
+	}{
+		{"WRecPkt", info.WRecPkt, "wan", "received"},
+		{"WSendPkt", info.WSendPkt, "wan", "sent"},
+		{"LRecPkt", info.LRecPkt, "lan", "received"},
+		{"LSendPkt", info.LSendPkt, "lan", "sent"},
+	} {
+		out = append(out, Traffic{Interface: f.iface, Direction: f.direction, Bytes: p.byteCount(f.field, f.value)})
+	}
+	return out
+}
+
+// LinkUp reports whether the modem's Ethernet link is up.
+func LinkUp(link *hitron.LinkStatus) bool {
+	return strings.EqualFold(strings.TrimSpace(link.LinkStatus), "up")
+}
@@ -0,0 +1,154 @@
+// Package collector provides a prometheus.Collector for each group of the
+// modem's data pages, for programs that want some of the exporter's
+// metrics in a registry of their own:
+//
+//	tlsConfig, err := hitron.TLSOptions{Fingerprint: fingerprint}.Config()
+//	...
+//	client := hitron.NewModemClient("https://192.168.100.1", 10*time.Second, hitron.WithTLSConfig(tlsConfig))
+//	reg := prometheus.NewRegistry()
+//	prometheus.WrapRegistererWithPrefix("hitron_", reg).MustRegister(
+//		collector.NewDownstreamCollector(client),
+//		collector.NewOFDMCollector(client),
+//	)
+//
+// The collectors poll the modem on every Collect, with no caching of their
+// own, and carry no namespace, so wrapping the registerer as above gives
+// the exporter's metric names. Each covers the core per-channel metrics of
+// its pages plus endpoint_up and parse_errors_total per page; the
+// exporter's derived metrics, such as counter reset tracking, summaries and
+// in-spec gauges, need the history the exporter keeps and are left out.
+//
+// The Parse functions turn the pages into the values of those metrics, and
+// are what the exporter itself builds its series from.
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// An Option configures a collector.
+type Option func(*options)
+
+type options struct {
+	labels  Labels
+	timeout time.Duration
+}
+
+// WithCompactLabels keys the channel series by channel only, like the
+// exporter's -metrics.compact-labels. The frequency and modulation are in
+// the *_channel_info series either way.
+func WithCompactLabels() Option {
+	return func(o *options) {
+		o.labels.Compact = true
+	}
+}
+
+// WithTimeout bounds each Collect, which otherwise lasts as long as the
+// client's own timeouts allow.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// context returns the context of one Collect.
+func (o options) context() (context.Context, context.CancelFunc) {
+	if o.timeout > 0 {
+		return context.WithTimeout(context.Background(), o.timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// page is one data page a collector fetches.
+type page struct {
+	endpoint    string
+	up          *prometheus.Desc
+	parseErrors *prometheus.CounterVec
+}
+
+// newPage looks up the endpoint of p for the client's model. The model is
+// read once, so a client using hitron.WithAutoDetect should have called
+// DetectModel before the collector is created.
+func newPage(client *hitron.ModemClient, p hitron.Page) page {
+	endpoint := client.Endpoint(p)
+	return page{
+		endpoint: endpoint,
+		up: prometheus.NewDesc(
+			"endpoint_up",
+			"Whether each modem endpoint could be fetched in the last poll",
+			nil, prometheus.Labels{"endpoint": endpoint},
+		),
+		parseErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "parse_errors_total",
+				Help:        "Number of values the modem reported that were not numbers, and so were left out",
+				ConstLabels: prometheus.Labels{"endpoint": endpoint},
+			},
+			[]string{"field"},
+		),
+	}
+}
+
+func (p page) describe(ch chan<- *prometheus.Desc) {
+	if p.endpoint != "" {
+		ch <- p.up
+		p.parseErrors.Describe(ch)
+	}
+}
+
+// fetch runs get unless the client's model lacks the page, and reports the
+// outcome on ch. It returns whether get succeeded.
+func (p page) fetch(ctx context.Context, ch chan<- prometheus.Metric, get func(ctx context.Context) error) bool {
+	if p.endpoint == "" {
+		return false
+	}
+	err := get(ctx)
+	up := 1.0
+	if err != nil {
+		slog.Warn("Failed to get modem page", "endpoint", p.endpoint, "err", err)
+		up = 0
+	}
+	ch <- prometheus.MustNewConstMetric(p.up, prometheus.GaugeValue, up)
+	return err == nil
+}
+
+func (p page) parser() Parser {
+	return NewParser(p.endpoint, p.parseErrors)
+}
+
+// collectErrors sends the page's parse errors, after its values were
+// parsed.
+func (p page) collectErrors(ch chan<- prometheus.Metric) {
+	if p.endpoint != "" {
+		p.parseErrors.Collect(ch)
+	}
+}
+
+// emit sends a metric for v unless it wasn't reported or didn't parse.
+func emit(ch chan<- prometheus.Metric, desc *prometheus.Desc, typ prometheus.ValueType, v Value, labels ...string) {
+	if v.OK {
+		ch <- prometheus.MustNewConstMetric(desc, typ, v.V, labels...)
+	}
+}
+
+// flag returns 1 if ok and 0 otherwise.
+func flag(ok bool) float64 {
+	if ok {
+		return 1
+	}
+	return 0
+}
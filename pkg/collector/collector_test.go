@@ -0,0 +1,150 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// newModem serves the hitron package's fixtures, failing the pages in down.
+func newModem(t *testing.T, down ...string) *hitron.ModemClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint := path.Base(r.URL.Path)
+		for _, d := range down {
+			if d == endpoint {
+				http.Error(w, "busy", http.StatusServiceUnavailable)
+				return
+			}
+		}
+		data, err := os.ReadFile(filepath.Join("..", "hitron", "testdata", strings.TrimSuffix(endpoint, ".asp")+".json"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	return hitron.NewModemClient(srv.URL, time.Second, hitron.WithRetryPolicy(hitron.RetryPolicy{}))
+}
+
+func newRegistry(t *testing.T, client *hitron.ModemClient, opts ...Option) *prometheus.Registry {
+	t.Helper()
+	reg := prometheus.NewRegistry()
+	prometheus.WrapRegistererWithPrefix("hitron_", reg).MustRegister(
+		NewDownstreamCollector(client, opts...),
+		NewUpstreamCollector(client, opts...),
+		NewOFDMCollector(client, opts...),
+		NewSystemCollector(client, opts...),
+	)
+	return reg
+}
+
+func TestCollectors(t *testing.T) {
+	reg := newRegistry(t, newModem(t))
+	want := `
+# HELP hitron_downstream_power_dbmv Downstream channel power level in dBmV
+# TYPE hitron_downstream_power_dbmv gauge
+hitron_downstream_power_dbmv{channel_id="17",frequency="591000000",modulation="QAM256"} 3.2
+hitron_downstream_power_dbmv{channel_id="18",frequency="597000000",modulation="QAM256"} 2.9
+# HELP hitron_downstream_uncorrectables Number of uncorrectable errors on downstream channel
+# TYPE hitron_downstream_uncorrectables counter
+hitron_downstream_uncorrectables{channel_id="17",frequency="591000000",modulation="QAM256"} 3
+hitron_downstream_uncorrectables{channel_id="18",frequency="597000000",modulation="QAM256"} 0
+# HELP hitron_ofdm_downstream_modulation_bits Highest OFDM modulation order, in bits per subcarrier, the downstream channel's SNR supports under DOCSIS 3.1 (e.g. 12 for QAM4096)
+# TYPE hitron_ofdm_downstream_modulation_bits gauge
+hitron_ofdm_downstream_modulation_bits{receive="0"} 12
+# HELP hitron_ofdm_upstream_state OFDM upstream channel state (1 = operate, 0 = disabled)
+# TYPE hitron_ofdm_upstream_state gauge
+hitron_ofdm_upstream_state{frequency="0",usch_index="1"} 0
+hitron_ofdm_upstream_state{frequency="39000000",usch_index="0"} 1
+# HELP hitron_traffic_bytes Bytes the modem has counted on its WAN and LAN side, by interface and direction
+# TYPE hitron_traffic_bytes counter
+hitron_traffic_bytes{direction="received",interface="lan"} 1.048576e+06
+hitron_traffic_bytes{direction="received",interface="wan"} 1.2884901888e+09
+hitron_traffic_bytes{direction="sent",interface="lan"} 2.097152e+06
+hitron_traffic_bytes{direction="sent",interface="wan"} 3.145728e+08
+# HELP hitron_upstream_modulation_bits Upstream channel modulation order in bits per symbol (e.g. 6 for QAM64)
+# TYPE hitron_upstream_modulation_bits gauge
+hitron_upstream_modulation_bits{channel_id="3"} 6
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want),
+		"hitron_downstream_power_dbmv", "hitron_downstream_uncorrectables", "hitron_ofdm_downstream_modulation_bits",
+		"hitron_ofdm_upstream_state", "hitron_traffic_bytes", "hitron_upstream_modulation_bits"); err != nil {
+		t.Error(err)
+	}
+	if n, err := testutil.GatherAndCount(reg, "hitron_endpoint_up"); err != nil || n != 6 {
+		t.Errorf("hitron_endpoint_up has %d series (%v), want one per page", n, err)
+	}
+	if n, err := testutil.GatherAndCount(reg, "hitron_ofdm_upstream_power_dbmv"); err != nil || n != 1 {
+		t.Errorf("hitron_ofdm_upstream_power_dbmv has %d series (%v), want only the channel in use", n, err)
+	}
+}
+
+func TestCollectorEndpointDown(t *testing.T) {
+	reg := newRegistry(t, newModem(t, hitron.EndpointDownstream))
+	want := `
+# HELP hitron_endpoint_up Whether each modem endpoint could be fetched in the last poll
+# TYPE hitron_endpoint_up gauge
+hitron_endpoint_up{endpoint="dsinfo.asp"} 0
+hitron_endpoint_up{endpoint="dsofdminfo.asp"} 1
+hitron_endpoint_up{endpoint="getLinkStatus.asp"} 1
+hitron_endpoint_up{endpoint="getSysInfo.asp"} 1
+hitron_endpoint_up{endpoint="usinfo.asp"} 1
+hitron_endpoint_up{endpoint="usofdminfo.asp"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "hitron_endpoint_up"); err != nil {
+		t.Error(err)
+	}
+	if n, err := testutil.GatherAndCount(reg, "hitron_downstream_power_dbmv"); err != nil || n != 0 {
+		t.Errorf("hitron_downstream_power_dbmv has %d series (%v) with its page down, want none", n, err)
+	}
+}
+
+func TestCollectorCompactLabels(t *testing.T) {
+	reg := newRegistry(t, newModem(t), WithCompactLabels())
+	want := `
+# HELP hitron_downstream_channel_info Frequency and modulation of each downstream channel (always 1)
+# TYPE hitron_downstream_channel_info gauge
+hitron_downstream_channel_info{channel_id="17",frequency="591000000",modulation="QAM256"} 1
+hitron_downstream_channel_info{channel_id="18",frequency="597000000",modulation="QAM256"} 1
+# HELP hitron_downstream_power_dbmv Downstream channel power level in dBmV
+# TYPE hitron_downstream_power_dbmv gauge
+hitron_downstream_power_dbmv{channel_id="17"} 3.2
+hitron_downstream_power_dbmv{channel_id="18"} 2.9
+# HELP hitron_ofdm_upstream_state OFDM upstream channel state (1 = operate, 0 = disabled)
+# TYPE hitron_ofdm_upstream_state gauge
+hitron_ofdm_upstream_state{usch_index="0"} 1
+hitron_ofdm_upstream_state{usch_index="1"} 0
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want),
+		"hitron_downstream_channel_info", "hitron_downstream_power_dbmv", "hitron_ofdm_upstream_state"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseDownstreamErrors(t *testing.T) {
+	errs := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "parse_errors_total"}, []string{"field"})
+	channels := ParseDownstream(NewParser("dsinfo.asp", errs), []hitron.DownstreamInfo{
+		{ChannelID: "1", Frequency: "591000000", Modulation: "QAM256", SignalStrength: "N/A", SNR: "40.1", Correcteds: "1", Uncorrect: "0", DSoctets: "123456"},
+	})
+	if got := channels[0]; got.Power.OK || !got.SNR.OK || got.SNR.V != 40.1 || !got.OctetsMayWrap {
+		t.Errorf("ParseDownstream = %+v, want the power left out", got)
+	}
+	if n := testutil.ToFloat64(errs.WithLabelValues("signalStrength")); n != 1 {
+		t.Errorf("parse errors for signalStrength = %v, want 1", n)
+	}
+	if n := testutil.CollectAndCount(errs); n != 1 {
+		t.Errorf("parse errors have %d series, want only the power's", n)
+	}
+}
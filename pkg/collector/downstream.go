@@ -0,0 +1,77 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// DownstreamCollector exports the downstream SC-QAM channels.
+type DownstreamCollector struct {
+	client *hitron.ModemClient
+	opts   options
+	page   page
+
+	power, snr, frequency, modulationBits *prometheus.Desc
+	correctables, uncorrectables, octets  *prometheus.Desc
+	info                                  *prometheus.Desc
+}
+
+// NewDownstreamCollector returns a collector for the downstream SC-QAM
+// channels of the modem behind client.
+func NewDownstreamCollector(client *hitron.ModemClient, opts ...Option) *DownstreamCollector {
+	o := newOptions(opts)
+	labels := o.labels.Names("channel_id", "frequency", "modulation")
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(name, help, labels, nil)
+	}
+	return &DownstreamCollector{
+		client:         client,
+		opts:           o,
+		page:           newPage(client, hitron.PageDownstream),
+		power:          desc("downstream_power_dbmv", "Downstream channel power level in dBmV"),
+		snr:            desc("downstream_snr_db", "Downstream channel signal-to-noise ratio in dB"),
+		frequency:      prometheus.NewDesc("downstream_frequency_hz", "Downstream channel frequency in Hz", o.labels.Names("channel_id", "modulation"), nil),
+		modulationBits: prometheus.NewDesc("downstream_modulation_bits", "Downstream channel modulation order in bits per symbol (e.g. 8 for QAM256)", []string{"channel_id"}, nil),
+		correctables:   desc("downstream_correctables", "Number of correctable errors on downstream channel"),
+		uncorrectables: desc("downstream_uncorrectables", "Number of uncorrectable errors on downstream channel"),
+		octets:         desc("downstream_octets_bytes", "Number of octets (bytes) received on downstream channel"),
+		info:           prometheus.NewDesc("downstream_channel_info", "Frequency and modulation of each downstream channel (always 1)", []string{"channel_id", "frequency", "modulation"}, nil),
+	}
+}
+
+func (c *DownstreamCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.page.describe(ch)
+	for _, d := range []*prometheus.Desc{c.power, c.snr, c.frequency, c.modulationBits, c.correctables, c.uncorrectables, c.octets, c.info} {
+		ch <- d
+	}
+}
+
+func (c *DownstreamCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := c.opts.context()
+	defer cancel()
+	var channels []hitron.DownstreamInfo
+	if c.page.fetch(ctx, ch, func(ctx context.Context) (err error) {
+		channels, err = c.client.GetDownstreamInfo(ctx)
+		return err
+	}) {
+		c.collect(ch, ParseDownstream(c.page.parser(), channels))
+	}
+	c.page.collectErrors(ch)
+}
+
+func (c *DownstreamCollector) collect(ch chan<- prometheus.Metric, channels []DownstreamChannel) {
+	for _, channel := range channels {
+		labels := c.opts.labels.Values("channel_id", channel.ID, "frequency", channel.Frequency, "modulation", channel.Modulation)
+		ch <- prometheus.MustNewConstMetric(c.info, prometheus.GaugeValue, 1, channel.ID, channel.Frequency, channel.Modulation)
+		emit(ch, c.power, prometheus.GaugeValue, channel.Power, labels...)
+		emit(ch, c.snr, prometheus.GaugeValue, channel.SNR, labels...)
+		emit(ch, c.frequency, prometheus.GaugeValue, channel.FrequencyHz, c.opts.labels.Values("channel_id", channel.ID, "modulation", channel.Modulation)...)
+		emit(ch, c.modulationBits, prometheus.GaugeValue, channel.ModulationBits, channel.ID)
+		emit(ch, c.correctables, prometheus.CounterValue, channel.Correcteds, labels...)
+		emit(ch, c.uncorrectables, prometheus.CounterValue, channel.Uncorrectables, labels...)
+		emit(ch, c.octets, prometheus.CounterValue, channel.Octets, labels...)
+	}
+}
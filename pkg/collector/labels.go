@@ -0,0 +1,39 @@
+package collector
+
+// descriptiveLabels are the channel properties that change when the modem
+// re-scans, each of which would otherwise start a new set of series.
+var descriptiveLabels = map[string]bool{
+	"frequency":  true,
+	"modulation": true,
+	"fft_type":   true,
+	"state":      true,
+}
+
+// Labels selects the labels of channel series. In compact mode the
+// descriptive labels are dropped and channels are keyed by their ID only.
+type Labels struct {
+	Compact bool
+}
+
+// Names filters label names for a metric's definition.
+func (l Labels) Names(names ...string) []string {
+	var out []string
+	for _, name := range names {
+		if !l.Compact || !descriptiveLabels[name] {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// Values filters alternating name, value pairs the same way as Names and
+// returns the values.
+func (l Labels) Values(pairs ...string) []string {
+	var out []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if !l.Compact || !descriptiveLabels[pairs[i]] {
+			out = append(out, pairs[i+1])
+		}
+	}
+	return out
+}
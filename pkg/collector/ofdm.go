@@ -0,0 +1,123 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// OFDMCollector exports the DOCSIS 3.1 channels: the downstream OFDM
+// receivers and the upstream OFDMA channels, each from its own page.
+type OFDMCollector struct {
+	client *hitron.ModemClient
+	opts   options
+	dsPage page
+	usPage page
+
+	dsPower, dsSNR, dsFrequency, dsLocks       *prometheus.Desc
+	dsCorrectables, dsUncorrectables           *prometheus.Desc
+	dsOctets, dsModulationBits, dsInfo         *prometheus.Desc
+	usPower, usFrequency, usBandwidth, usState *prometheus.Desc
+	usInfo                                     *prometheus.Desc
+}
+
+// NewOFDMCollector returns a collector for the OFDM downstream and OFDMA
+// upstream channels of the modem behind client.
+func NewOFDMCollector(client *hitron.ModemClient, opts ...Option) *OFDMCollector {
+	o := newOptions(opts)
+	dsLabels := o.labels.Names("receive", "frequency", "fft_type")
+	usLabels := o.labels.Names("usch_index", "frequency", "state")
+	return &OFDMCollector{
+		client: client,
+		opts:   o,
+		dsPage: newPage(client, hitron.PageOFDMDownstream),
+		usPage: newPage(client, hitron.PageOFDMUpstream),
+
+		dsPower:          prometheus.NewDesc("ofdm_downstream_power_dbmv", "OFDM downstream channel power level in dBmV", dsLabels, nil),
+		dsSNR:            prometheus.NewDesc("ofdm_downstream_snr_db", "OFDM downstream channel signal-to-noise ratio in dB", dsLabels, nil),
+		dsFrequency:      prometheus.NewDesc("ofdm_downstream_frequency_hz", "OFDM downstream channel frequency in Hz", o.labels.Names("receive", "fft_type"), nil),
+		dsLocks:          prometheus.NewDesc("ofdm_downstream_locks", "OFDM downstream channel lock status (1 = locked, 0 = unlocked)", o.labels.Names("receive", "frequency", "lock_type"), nil),
+		dsCorrectables:   prometheus.NewDesc("ofdm_downstream_correctables", "Number of correctable errors on OFDM downstream channel", dsLabels, nil),
+		dsUncorrectables: prometheus.NewDesc("ofdm_downstream_uncorrectables", "Number of uncorrectable errors on OFDM downstream channel", dsLabels, nil),
+		dsOctets:         prometheus.NewDesc("ofdm_downstream_octets_bytes", "Number of octets (bytes) received on OFDM downstream channel", dsLabels, nil),
+		dsModulationBits: prometheus.NewDesc("ofdm_downstream_modulation_bits", "Highest OFDM modulation order, in bits per subcarrier, the downstream channel's SNR supports under DOCSIS 3.1 (e.g. 12 for QAM4096)", []string{"receive"}, nil),
+		dsInfo:           prometheus.NewDesc("ofdm_downstream_channel_info", "Frequency and FFT type of each OFDM downstream channel (always 1)", []string{"receive", "frequency", "fft_type"}, nil),
+
+		usPower:     prometheus.NewDesc("ofdm_upstream_power_dbmv", "OFDM upstream channel power level in dBmV", usLabels, nil),
+		usFrequency: prometheus.NewDesc("ofdm_upstream_frequency_hz", "OFDM upstream channel frequency in Hz", o.labels.Names("usch_index", "state"), nil),
+		usBandwidth: prometheus.NewDesc("ofdm_upstream_bandwidth_hz", "OFDM upstream channel bandwidth in Hz", usLabels, nil),
+		usState:     prometheus.NewDesc("ofdm_upstream_state", "OFDM upstream channel state (1 = operate, 0 = disabled)", o.labels.Names("usch_index", "frequency"), nil),
+		usInfo:      prometheus.NewDesc("ofdm_upstream_channel_info", "Frequency and state of each OFDM upstream channel (always 1)", []string{"usch_index", "frequency", "state"}, nil),
+	}
+}
+
+func (c *OFDMCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.dsPage.describe(ch)
+	c.usPage.describe(ch)
+	for _, d := range []*prometheus.Desc{
+		c.dsPower, c.dsSNR, c.dsFrequency, c.dsLocks, c.dsCorrectables, c.dsUncorrectables, c.dsOctets, c.dsModulationBits, c.dsInfo,
+		c.usPower, c.usFrequency, c.usBandwidth, c.usState, c.usInfo,
+	} {
+		ch <- d
+	}
+}
+
+func (c *OFDMCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := c.opts.context()
+	defer cancel()
+	var downstream []hitron.OFDMDownstreamInfo
+	if c.dsPage.fetch(ctx, ch, func(ctx context.Context) (err error) {
+		downstream, err = c.client.GetOFDMDownstreamInfo(ctx)
+		return err
+	}) {
+		c.collectDownstream(ch, ParseOFDMDownstream(c.dsPage.parser(), downstream))
+	}
+	c.dsPage.collectErrors(ch)
+	var upstream []hitron.OFDMUpstreamInfo
+	if c.usPage.fetch(ctx, ch, func(ctx context.Context) (err error) {
+		upstream, err = c.client.GetOFDMUpstreamInfo(ctx)
+		return err
+	}) {
+		c.collectUpstream(ch, ParseOFDMUpstream(c.usPage.parser(), upstream))
+	}
+	c.usPage.collectErrors(ch)
+}
+
+func (c *OFDMCollector) collectDownstream(ch chan<- prometheus.Metric, channels []OFDMDownstreamChannel) {
+	for _, channel := range channels {
+		labels := c.opts.labels.Values("receive", channel.Receive, "frequency", channel.Frequency, "fft_type", channel.FFTType)
+		ch <- prometheus.MustNewConstMetric(c.dsInfo, prometheus.GaugeValue, 1, channel.Receive, channel.Frequency, channel.FFTType)
+		emit(ch, c.dsPower, prometheus.GaugeValue, channel.Power, labels...)
+		emit(ch, c.dsSNR, prometheus.GaugeValue, channel.SNR, labels...)
+		emit(ch, c.dsModulationBits, prometheus.GaugeValue, channel.ModulationBits, channel.Receive)
+		emit(ch, c.dsFrequency, prometheus.GaugeValue, channel.FrequencyHz, c.opts.labels.Values("receive", channel.Receive, "fft_type", channel.FFTType)...)
+		emit(ch, c.dsCorrectables, prometheus.CounterValue, channel.Correcteds, labels...)
+		emit(ch, c.dsUncorrectables, prometheus.CounterValue, channel.Uncorrectables, labels...)
+		emit(ch, c.dsOctets, prometheus.CounterValue, channel.Octets, labels...)
+		for _, lock := range []struct {
+			typ    string
+			locked bool
+		}{
+			{"plc", channel.PLCLock},
+			{"ncp", channel.NCPLock},
+			{"mdc1", channel.MDC1Lock},
+		} {
+			ch <- prometheus.MustNewConstMetric(c.dsLocks, prometheus.GaugeValue, flag(lock.locked),
+				c.opts.labels.Values("receive", channel.Receive, "frequency", channel.Frequency, "lock_type", lock.typ)...)
+		}
+	}
+}
+
+func (c *OFDMCollector) collectUpstream(ch chan<- prometheus.Metric, channels []OFDMUpstreamChannel) {
+	for _, channel := range channels {
+		labels := c.opts.labels.Values("usch_index", channel.USCHIndex, "frequency", channel.Frequency, "state", channel.State)
+		ch <- prometheus.MustNewConstMetric(c.usInfo, prometheus.GaugeValue, 1, channel.USCHIndex, channel.Frequency, channel.State)
+		emit(ch, c.usFrequency, prometheus.GaugeValue, channel.FrequencyHz, c.opts.labels.Values("usch_index", channel.USCHIndex, "state", channel.State)...)
+		emit(ch, c.usPower, prometheus.GaugeValue, channel.Power, labels...)
+		emit(ch, c.usBandwidth, prometheus.GaugeValue, channel.Bandwidth, labels...)
+		ch <- prometheus.MustNewConstMetric(c.usState, prometheus.GaugeValue, flag(channel.Operating),
+			c.opts.labels.Values("usch_index", channel.USCHIndex, "frequency", channel.Frequency)...)
+	}
+}
@@ -0,0 +1,75 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// SystemCollector exports the modem's identity, uptime and Ethernet link
+// state.
+type SystemCollector struct {
+	client   *hitron.ModemClient
+	opts     options
+	sysPage  page
+	linkPage page
+
+	info, uptime, traffic, linkStatus, linkSpeed *prometheus.Desc
+}
+
+// NewSystemCollector returns a collector for the system information and
+// link status pages of the modem behind client.
+func NewSystemCollector(client *hitron.ModemClient, opts ...Option) *SystemCollector {
+	return &SystemCollector{
+		client:   client,
+		opts:     newOptions(opts),
+		sysPage:  newPage(client, hitron.PageSystemInfo),
+		linkPage: newPage(client, hitron.PageLinkStatus),
+
+		info:       prometheus.NewDesc("system_info", "System information", []string{"hardware_version", "software_version", "serial_number"}, nil),
+		uptime:     prometheus.NewDesc("system_uptime_seconds", "Time since the modem last booted, in seconds", nil, nil),
+		traffic:    prometheus.NewDesc("traffic_bytes", "Bytes the modem has counted on its WAN and LAN side, by interface and direction", []string{"interface", "direction"}, nil),
+		linkStatus: prometheus.NewDesc("link_status", "Link status (1 = up, 0 = down)", nil, nil),
+		linkSpeed:  prometheus.NewDesc("link_speed_bits_per_second", "Link speed in bits per second", nil, nil),
+	}
+}
+
+func (c *SystemCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.sysPage.describe(ch)
+	c.linkPage.describe(ch)
+	for _, d := range []*prometheus.Desc{c.info, c.uptime, c.traffic, c.linkStatus, c.linkSpeed} {
+		ch <- d
+	}
+}
+
+func (c *SystemCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := c.opts.context()
+	defer cancel()
+	var info *hitron.SystemInfo
+	if c.sysPage.fetch(ctx, ch, func(ctx context.Context) (err error) {
+		info, err = c.client.GetSystemInfo(ctx)
+		return err
+	}) {
+		ch <- prometheus.MustNewConstMetric(c.info, prometheus.GaugeValue, 1, info.HWVersion, info.SWVersion, info.SerialNumber)
+		if uptime, err := hitron.ParseUptime(info.SystemUptime); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.uptime, prometheus.GaugeValue, uptime.Seconds())
+		}
+		for _, t := range ParseTraffic(c.sysPage.parser(), info) {
+			emit(ch, c.traffic, prometheus.CounterValue, t.Bytes, t.Interface, t.Direction)
+		}
+	}
+	c.sysPage.collectErrors(ch)
+	var link *hitron.LinkStatus
+	if c.linkPage.fetch(ctx, ch, func(ctx context.Context) (err error) {
+		link, err = c.client.GetLinkStatus(ctx)
+		return err
+	}) {
+		ch <- prometheus.MustNewConstMetric(c.linkStatus, prometheus.GaugeValue, flag(LinkUp(link)))
+		if speed, err := hitron.ParseLinkSpeed(link.LinkSpeed); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.linkSpeed, prometheus.GaugeValue, speed)
+		}
+	}
+	c.linkPage.collectErrors(ch)
+}
@@ -0,0 +1,68 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/anupcshan/coda56-exporter/pkg/hitron"
+)
+
+// UpstreamCollector exports the upstream SC-QAM channels.
+type UpstreamCollector struct {
+	client *hitron.ModemClient
+	opts   options
+	page   page
+
+	power, frequency, bandwidth, scdma, modulationBits, info *prometheus.Desc
+}
+
+// NewUpstreamCollector returns a collector for the upstream SC-QAM
+// channels of the modem behind client.
+func NewUpstreamCollector(client *hitron.ModemClient, opts ...Option) *UpstreamCollector {
+	o := newOptions(opts)
+	labels := o.labels.Names("channel_id", "frequency", "modulation")
+	return &UpstreamCollector{
+		client:         client,
+		opts:           o,
+		page:           newPage(client, hitron.PageUpstream),
+		power:          prometheus.NewDesc("upstream_power_dbmv", "Upstream channel power level in dBmV", labels, nil),
+		frequency:      prometheus.NewDesc("upstream_frequency_hz", "Upstream channel frequency in Hz", o.labels.Names("channel_id", "modulation"), nil),
+		bandwidth:      prometheus.NewDesc("upstream_bandwidth_hz", "Upstream channel width in Hz", labels, nil),
+		scdma:          prometheus.NewDesc("upstream_scdma", "Whether the upstream channel runs S-CDMA (1) rather than ATDMA (0)", []string{"channel_id"}, nil),
+		modulationBits: prometheus.NewDesc("upstream_modulation_bits", "Upstream channel modulation order in bits per symbol (e.g. 6 for QAM64)", []string{"channel_id"}, nil),
+		info:           prometheus.NewDesc("upstream_channel_info", "Frequency and modulation of each upstream channel (always 1)", []string{"channel_id", "frequency", "modulation"}, nil),
+	}
+}
+
+func (c *UpstreamCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.page.describe(ch)
+	for _, d := range []*prometheus.Desc{c.power, c.frequency, c.bandwidth, c.scdma, c.modulationBits, c.info} {
+		ch <- d
+	}
+}
+
+func (c *UpstreamCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := c.opts.context()
+	defer cancel()
+	var channels []hitron.UpstreamInfo
+	if c.page.fetch(ctx, ch, func(ctx context.Context) (err error) {
+		channels, err = c.client.GetUpstreamInfo(ctx)
+		return err
+	}) {
+		c.collect(ch, ParseUpstream(c.page.parser(), channels))
+	}
+	c.page.collectErrors(ch)
+}
+
+func (c *UpstreamCollector) collect(ch chan<- prometheus.Metric, channels []UpstreamChannel) {
+	for _, channel := range channels {
+		labels := c.opts.labels.Values("channel_id", channel.ID, "frequency", channel.Frequency, "modulation", channel.Modulation)
+		ch <- prometheus.MustNewConstMetric(c.info, prometheus.GaugeValue, 1, channel.ID, channel.Frequency, channel.Modulation)
+		emit(ch, c.power, prometheus.GaugeValue, channel.Power, labels...)
+		emit(ch, c.frequency, prometheus.GaugeValue, channel.FrequencyHz, c.opts.labels.Values("channel_id", channel.ID, "modulation", channel.Modulation)...)
+		emit(ch, c.bandwidth, prometheus.GaugeValue, channel.Bandwidth, labels...)
+		emit(ch, c.modulationBits, prometheus.GaugeValue, channel.ModulationBits, channel.ID)
+		emit(ch, c.scdma, prometheus.GaugeValue, channel.SCDMA, channel.ID)
+	}
+}
@@ -0,0 +1,202 @@
+package hitron
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Page identifies a kind of status data independently of the data page a
+// particular model serves it from.
+type Page int
+
+const (
+	PageDownstream Page = iota
+	PageUpstream
+	PageOFDMDownstream
+	PageOFDMUpstream
+	PageSystemInfo
+	PageLinkStatus
+)
+
+// Pages lists every kind of status data in the order it is polled.
+var Pages = []Page{
+	PageDownstream,
+	PageUpstream,
+	PageOFDMDownstream,
+	PageOFDMUpstream,
+	PageSystemInfo,
+	PageLinkStatus,
+}
+
+func (p Page) String() string {
+	switch p {
+	case PageDownstream:
+		return "downstream"
+	case PageUpstream:
+		return "upstream"
+	case PageOFDMDownstream:
+		return "ofdm downstream"
+	case PageOFDMUpstream:
+		return "ofdm upstream"
+	case PageSystemInfo:
+		return "system info"
+	case PageLinkStatus:
+		return "link status"
+	}
+	return fmt.Sprintf("page %d", int(p))
+}
+
+// ModemAdapter maps the data pages of one modem model onto the common
+// structs. Hitron models share most of their web interface but differ in
+// which pages they serve, so the client asks the adapter where each kind of
+// data lives and how to decode it.
+type ModemAdapter interface {
+	// Model is the name used to select the adapter, such as "coda56".
+	Model() string
+	// Endpoint returns the data page that serves p, or "" if the model
+	// doesn't report it.
+	Endpoint(p Page) string
+
+	ParseDownstream(data []byte) ([]DownstreamInfo, error)
+	ParseUpstream(data []byte) ([]UpstreamInfo, error)
+	ParseOFDMDownstream(data []byte) ([]OFDMDownstreamInfo, error)
+	ParseOFDMUpstream(data []byte) ([]OFDMUpstreamInfo, error)
+	ParseSystemInfo(data []byte) (*SystemInfo, error)
+	ParseLinkStatus(data []byte) (*LinkStatus, error)
+}
+
+// WebAdapter is a ModemAdapter for models whose pages use the CODA56's JSON
+// layout, decoding them with the package's Parse* functions. Adapters for
+// models with a different layout can embed it and override the parsers that
+// differ.
+type WebAdapter struct {
+	Name string
+	// Endpoints maps each reported kind of data to its page under /data/.
+	Endpoints map[Page]string
+}
+
+func (a *WebAdapter) Model() string          { return a.Name }
+func (a *WebAdapter) Endpoint(p Page) string { return a.Endpoints[p] }
+
+func (a *WebAdapter) ParseDownstream(data []byte) ([]DownstreamInfo, error) {
+	return ParseDownstreamInfo(data)
+}
+
+func (a *WebAdapter) ParseUpstream(data []byte) ([]UpstreamInfo, error) {
+	return ParseUpstreamInfo(data)
+}
+
+func (a *WebAdapter) ParseOFDMDownstream(data []byte) ([]OFDMDownstreamInfo, error) {
+	return ParseOFDMDownstreamInfo(data)
+}
+
+func (a *WebAdapter) ParseOFDMUpstream(data []byte) ([]OFDMUpstreamInfo, error) {
+	return ParseOFDMUpstreamInfo(data)
+}
+
+func (a *WebAdapter) ParseSystemInfo(data []byte) (*SystemInfo, error) {
+	return ParseSystemInfo(data)
+}
+
+func (a *WebAdapter) ParseLinkStatus(data []byte) (*LinkStatus, error) {
+	return ParseLinkStatus(data)
+}
+
+// dataPages are the pages served by the CODA56.
+var dataPages = map[Page]string{
+	PageDownstream:     EndpointDownstream,
+	PageUpstream:       EndpointUpstream,
+	PageOFDMDownstream: EndpointOFDMDownstream,
+	PageOFDMUpstream:   EndpointOFDMUpstream,
+	PageSystemInfo:     EndpointSystemInfo,
+	PageLinkStatus:     EndpointLinkStatus,
+}
+
+// scQAMPages are the pages of models without DOCSIS 3.1 channels, which have
+// no OFDM pages to poll.
+var scQAMPages = map[Page]string{
+	PageDownstream: EndpointDownstream,
+	PageUpstream:   EndpointUpstream,
+	PageSystemInfo: EndpointSystemInfo,
+	PageLinkStatus: EndpointLinkStatus,
+}
+
+// DefaultModel is the model assumed when none is given.
+const DefaultModel = "coda56"
+
+var adapters = map[string]ModemAdapter{
+	"coda56":   &WebAdapter{Name: "coda56", Endpoints: dataPages},
+	"coda4582": &WebAdapter{Name: "coda4582", Endpoints: dataPages},
+	"coda45":   &WebAdapter{Name: "coda45", Endpoints: scQAMPages},
+	"en2251":   &WebAdapter{Name: "en2251", Endpoints: dataPages},
+}
+
+// AdapterFor returns the adapter for a model name such as "coda56" or
+// "CODA-4582". Case and dashes are ignored.
+func AdapterFor(model string) (ModemAdapter, error) {
+	name := strings.ToLower(strings.ReplaceAll(model, "-", ""))
+	if a, ok := adapters[name]; ok {
+		return a, nil
+	}
+	return nil, fmt.Errorf("unknown modem model %q (known: %s)", model, strings.Join(Models(), ", "))
+}
+
+// Models returns the names of the built-in adapters, sorted.
+func Models() []string {
+	names := make([]string, 0, len(adapters))
+	for name := range adapters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithAdapter sets the model adapter, overriding WithAutoDetect. The default
+// is the CODA56's.
+func WithAdapter(a ModemAdapter) Option {
+	return func(m *ModemClient) {
+		m.adapter = a
+		m.autoDetect.Store(false)
+	}
+}
+
+// Adapter returns the client's model adapter. With WithAutoDetect it is the
+// CODA56's until the model has been detected.
+func (m *ModemClient) Adapter() ModemAdapter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.adapter
+}
+
+// Endpoint returns the data page that serves p on this modem, or "" if the
+// model doesn't report it.
+func (m *ModemClient) Endpoint(p Page) string {
+	return m.Adapter().Endpoint(p)
+}
+
+// Endpoints lists the data pages polled for a full snapshot of this modem.
+func (m *ModemClient) Endpoints() []string {
+	return adapterEndpoints(m.Adapter())
+}
+
+func adapterEndpoints(a ModemAdapter) []string {
+	var endpoints []string
+	for _, p := range Pages {
+		if e := a.Endpoint(p); e != "" {
+			endpoints = append(endpoints, e)
+		}
+	}
+	return endpoints
+}
+
+// page fetches the data page that serves p.
+func (m *ModemClient) page(ctx context.Context, p Page) ([]byte, error) {
+	a := m.Adapter()
+	endpoint := a.Endpoint(p)
+	if endpoint == "" {
+		return nil, fmt.Errorf("%w: %s on %s", ErrPageUnsupported, p, a.Model())
+	}
+	return m.Get(ctx, endpoint)
+}
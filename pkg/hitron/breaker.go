@@ -0,0 +1,109 @@
+package hitron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ErrModemUnreachable is returned without contacting the modem while the
+// circuit breaker is open; see WithCircuitBreaker.
+var ErrModemUnreachable = errors.New("modem unreachable")
+
+// breaker stops requests to a modem that has stopped answering, so that a
+// poll fails at once instead of waiting out the timeout on every page.
+type breaker struct {
+	threshold int
+	interval  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	open     bool
+}
+
+// WithCircuitBreaker makes the client stop requesting pages after threshold
+// consecutive fetches fail without the modem answering at all. While the
+// breaker is open, fetches fail at once with ErrModemUnreachable and the
+// system info page is requested every interval in the background; the first
+// answer closes the breaker again. Errors the modem answers with, such as a
+// missing page, don't count.
+func WithCircuitBreaker(threshold int, interval time.Duration) Option {
+	return func(m *ModemClient) {
+		if threshold > 0 {
+			m.breaker = &breaker{threshold: threshold, interval: interval}
+		}
+	}
+}
+
+// CircuitOpen reports whether requests to the modem are currently
+// suspended by the circuit breaker.
+func (m *ModemClient) CircuitOpen() bool {
+	if m.breaker == nil {
+		return false
+	}
+	m.breaker.mu.Lock()
+	defer m.breaker.mu.Unlock()
+	return m.breaker.open
+}
+
+// checkCircuit returns ErrModemUnreachable if the breaker is open.
+func (m *ModemClient) checkCircuit(endpoint string) error {
+	if m.CircuitOpen() {
+		return fmt.Errorf("%w, not requesting %s", ErrModemUnreachable, endpoint)
+	}
+	return nil
+}
+
+// recordResult counts err towards the breaker and opens it once the modem
+// has failed to answer threshold times in a row. Requests cut short by the
+// caller's context say nothing about the modem and are ignored.
+func (m *ModemClient) recordResult(ctx context.Context, err error) {
+	if m.breaker == nil || ctx.Err() != nil {
+		return
+	}
+	b := m.breaker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !isUnreachable(err) {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.open || b.failures < b.threshold {
+		return
+	}
+	b.open = true
+	slog.Warn("Modem is not answering, suspending requests", "modem", m.baseURL, "failures", b.failures, "probe_interval", b.interval)
+	go m.probeUntilReachable()
+}
+
+// probeUntilReachable requests the system info page every interval until
+// the modem answers, then closes the breaker.
+func (m *ModemClient) probeUntilReachable() {
+	ticker := time.NewTicker(m.breaker.interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		_, err := m.request(context.Background(), m.Adapter().Endpoint(PageSystemInfo))
+		if isUnreachable(err) {
+			slog.Debug("Modem still not answering", "modem", m.baseURL, "err", err)
+			continue
+		}
+		m.breaker.mu.Lock()
+		m.breaker.open = false
+		m.breaker.failures = 0
+		m.breaker.mu.Unlock()
+		slog.Info("Modem is answering again, resuming requests", "modem", m.baseURL)
+		return
+	}
+}
+
+// isUnreachable reports whether err means no response was received from
+// the modem, as opposed to an error response.
+func isUnreachable(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
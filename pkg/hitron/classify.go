@@ -0,0 +1,84 @@
+package hitron
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// Reasons a request to the modem failed, from ClassifyError.
+const (
+	// ErrorTimeout is a request that got no answer in time, usually a slow
+	// or overloaded web server rather than a dead modem.
+	ErrorTimeout = "timeout"
+	// ErrorConnectionRefused is a modem that is reachable but not serving
+	// its web interface, as while it boots.
+	ErrorConnectionRefused = "connection_refused"
+	// ErrorTLS is a failed TLS handshake or certificate check.
+	ErrorTLS = "tls"
+	// ErrorDNS is a modem host name that didn't resolve.
+	ErrorDNS = "dns"
+	// ErrorHTTPStatus is a response with a status other than 200, see
+	// StatusError.
+	ErrorHTTPStatus = "http_status"
+	// ErrorLoginRequired is a data page answered with the login page.
+	ErrorLoginRequired = "login_required"
+	// ErrorInvalidResponse is a 200 response that isn't JSON.
+	ErrorInvalidResponse = "invalid_response"
+	// ErrorOther is any other failure, such as a reset connection.
+	ErrorOther = "other"
+)
+
+// StatusError is returned when a data page answers with an unexpected HTTP
+// status. 404 and the login statuses have their own errors.
+type StatusError struct {
+	Endpoint string
+	Code     int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d for %s", e.Code, e.Endpoint)
+}
+
+// ClassifyError returns one of the Error* reasons for an error from the
+// client, or "" for nil.
+func ClassifyError(err error) string {
+	var (
+		status    *StatusError
+		netErr    net.Error
+		dnsErr    *net.DNSError
+		verifyErr *tls.CertificateVerificationError
+		recordErr tls.RecordHeaderError
+		alertErr  tls.AlertError
+		hostErr   x509.HostnameError
+		authErr   x509.UnknownAuthorityError
+		certErr   x509.CertificateInvalidError
+	)
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &status), errors.Is(err, ErrEndpointNotFound):
+		return ErrorHTTPStatus
+	case errors.Is(err, ErrLoginRequired):
+		return ErrorLoginRequired
+	case errors.Is(err, ErrNotJSON):
+		return ErrorInvalidResponse
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorTimeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorConnectionRefused
+	case errors.As(err, &dnsErr):
+		return ErrorDNS
+	case errors.Is(err, ErrCertificateMismatch), errors.As(err, &verifyErr), errors.As(err, &recordErr),
+		errors.As(err, &alertErr), errors.As(err, &hostErr), errors.As(err, &authErr), errors.As(err, &certErr):
+		return ErrorTLS
+	default:
+		return ErrorOther
+	}
+}
@@ -0,0 +1,563 @@
+// Package hitron queries the data endpoints of Hitron cable modems such as
+// the CODA56 and parses their JSON into Go structs.
+//
+// The modem's web interface serves each status page as JSON under /data/.
+// ModemClient fetches those pages and the Parse* functions decode them, so
+// programs that already have the raw JSON can use the parsers on their own.
+package hitron
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+)
+
+// Data endpoints served by the CODA56 under /data/. Other models may serve
+// the same data elsewhere; see ModemAdapter.
+const (
+	EndpointDownstream     = "dsinfo.asp"
+	EndpointUpstream       = "usinfo.asp"
+	EndpointOFDMDownstream = "dsofdminfo.asp"
+	EndpointOFDMUpstream   = "usofdminfo.asp"
+	EndpointSystemInfo     = "getSysInfo.asp"
+	EndpointLinkStatus     = "getLinkStatus.asp"
+)
+
+// Endpoints lists every data endpoint polled for a full snapshot of a
+// CODA56. ModemClient.Endpoints gives the list for the client's model.
+var Endpoints = []string{
+	EndpointDownstream,
+	EndpointUpstream,
+	EndpointOFDMDownstream,
+	EndpointOFDMUpstream,
+	EndpointSystemInfo,
+	EndpointLinkStatus,
+}
+
+// ErrEndpointNotFound is returned when the modem answers 404 for a data page,
+// which usually means the firmware doesn't provide it.
+var ErrEndpointNotFound = errors.New("endpoint not found")
+
+// ErrPageUnsupported is returned when asking for data that the modem's
+// model doesn't report, such as OFDM channels on a DOCSIS 3.0 modem.
+var ErrPageUnsupported = errors.New("not reported by this model")
+
+// ErrLoginRequired is returned when the modem answers a data page with its
+// login page, either because the page needs a session and no credentials
+// were given or because logging in did not help.
+var ErrLoginRequired = errors.New("modem login required")
+
+// ErrNotJSON is returned when a data page answers with something other than
+// JSON or the login page, such as an error page from a proxy or a firmware
+// build that serves the page elsewhere.
+var ErrNotJSON = errors.New("response is not JSON")
+
+// DataSource supplies raw endpoint payloads in place of HTTP requests to the
+// modem. The bytes go through the same parsers as a live fetch.
+type DataSource interface {
+	Fetch(endpoint string) ([]byte, error)
+}
+
+// ModemClient fetches data pages from a single modem. It is safe for
+// concurrent use; concurrent requests for the same page share one HTTP
+// round trip.
+type ModemClient struct {
+	baseURL string
+	client  *http.Client
+
+	// addressFallbacks are the schemes and ports given with
+	// WithAddressFallbacks, and addresses the URLs tried, baseURL first.
+	// current indexes the one in use.
+	addressFallbacks []string
+	addresses        []string
+	current          atomic.Int32
+	fallbackState    *fallbackState
+
+	// adapter is guarded by mu once the client is in use, since
+	// DetectModel replaces it.
+	adapter    ModemAdapter
+	autoDetect atomic.Bool
+
+	// source, when set, replaces HTTP requests to the modem.
+	source DataSource
+	// fallback, when set, is tried for pages the modem fails to serve.
+	fallback DataSource
+
+	// hook, when set, is handed every data page the modem returns.
+	hook func(endpoint string, body []byte)
+	// redact blanks the modem's identifiers in every page.
+	redact bool
+	// observe, when set, is told how long each request to the modem took.
+	observe func(endpoint string, d time.Duration, code int, err error)
+
+	// username and password, when set, are used to log in whenever the
+	// modem asks for a session; see login.
+	username string
+	password string
+	jar      *sessionJar
+
+	tlsConfig *tls.Config
+	// dial, when set, opens the connections to the modem or its proxy.
+	dial  func(ctx context.Context, network, addr string) (net.Conn, error)
+	proxy *url.URL
+	// resolved is the address set with WithResolvedAddress, for ProbeRTT.
+	resolved string
+	// tracerProvider, when set, replaces the global one; see
+	// WithTracerProvider.
+	tracerProvider trace.TracerProvider
+
+	retry   RetryPolicy
+	breaker *breaker
+	conns   ConnectionOptions
+
+	// combined is the optional aggregate status page; see PrefetchCombined.
+	combined         string
+	combinedMissing  atomic.Bool
+	combinedFailures atomic.Int32
+
+	// flight coalesces concurrent requests for the same page, so
+	// overlapping scrapes share one round trip instead of piling onto a
+	// modem that copes badly with bursts.
+	flight singleflight.Group
+
+	// validators caches responses that carried an ETag or Last-Modified
+	// header so they can be revalidated with a conditional request.
+	mu         sync.Mutex
+	validators map[string]*cachedResponse
+	prefetched map[string][]byte
+	// latest holds the last body Get returned for each page; see Latest.
+	latest map[string]latestPage
+}
+
+type latestPage struct {
+	body []byte
+	time time.Time
+}
+
+type cachedResponse struct {
+	etag         string
+	lastModified string
+	body         []byte
+}
+
+// Option configures a ModemClient.
+type Option func(*ModemClient)
+
+// WithDataSource makes the client read payloads from src instead of
+// requesting them from the modem.
+func WithDataSource(src DataSource) Option {
+	return func(m *ModemClient) { m.source = src }
+}
+
+// WithFallbackSource makes the client read a page from src when requesting
+// it from the modem fails, for example from an SNMPSource when the web
+// interface is down.
+func WithFallbackSource(src DataSource) Option {
+	return func(m *ModemClient) { m.fallback = src }
+}
+
+// WithResponseHook calls hook with the raw body of every data page the modem
+// returns, including the combined status page, before it is parsed. hook
+// must not modify body.
+func WithResponseHook(hook func(endpoint string, body []byte)) Option {
+	return func(m *ModemClient) { m.hook = hook }
+}
+
+// WithRequestObserver calls observe after every HTTP request for a data
+// page, including each retry, with the time from sending the request to
+// reading the whole response, the response's status code, or 0 if there was
+// no response, and the request's error if it failed.
+func WithRequestObserver(observe func(endpoint string, d time.Duration, code int, err error)) Option {
+	return func(m *ModemClient) { m.observe = observe }
+}
+
+// WithCombinedEndpoint sets the aggregate status page tried by
+// PrefetchCombined. An empty name disables it.
+func WithCombinedEndpoint(endpoint string) Option {
+	return func(m *ModemClient) { m.combined = endpoint }
+}
+
+// WithTLSConfig sets the TLS settings used to connect to the modem, for
+// example from TLSOptions.Config.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(m *ModemClient) { m.tlsConfig = cfg }
+}
+
+// RetryPolicy controls how failed requests to the modem are repeated. The
+// modem's web server occasionally drops a request, typically the first one
+// after it has been idle, so a single retry avoids most spurious failures.
+// Pages the firmware doesn't have and login failures are not retried.
+type RetryPolicy struct {
+	// Retries is how many times a failed request is repeated.
+	Retries int
+	// Delay is the wait before the first retry; it doubles for each
+	// further one.
+	Delay time.Duration
+	// Jitter randomizes each wait by up to this fraction either way, from
+	// 0 to 1, so that several exporters don't retry in lockstep.
+	Jitter float64
+}
+
+// backoff returns the wait before the given retry, counting from 1.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := float64(p.Delay) * math.Pow(2, float64(retry-1))
+	if p.Jitter > 0 {
+		d *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(d)
+}
+
+// WithRetryPolicy makes the client repeat failed requests according to p.
+// By default requests are not retried.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(m *ModemClient) { m.retry = p }
+}
+
+// ConnectionOptions controls how connections to the modem are reused. The
+// modem's web server copes badly with many open connections, so by default
+// at most two idle connections are kept, each for up to 90 seconds.
+type ConnectionOptions struct {
+	// DisableKeepAlive closes the connection after every request, sending
+	// "Connection: close".
+	DisableKeepAlive bool
+	// MaxIdleConns is the number of idle connections kept for reuse.
+	MaxIdleConns int
+	// IdleTimeout closes idle connections after this long.
+	IdleTimeout time.Duration
+}
+
+// WithConnectionOptions sets how connections to the modem are reused.
+func WithConnectionOptions(o ConnectionOptions) Option {
+	return func(m *ModemClient) { m.conns = o }
+}
+
+// WithCredentials makes the client log in to the modem's web interface when
+// a page requires a session, and again whenever the session expires.
+func WithCredentials(username, password string) Option {
+	return func(m *ModemClient) {
+		m.username = username
+		m.password = password
+	}
+}
+
+// NewModemClient returns a client for the modem at baseURL, for example
+// "https://192.168.100.1", or a host name or IP address as accepted by
+// NormalizeBaseURL. Unless WithTLSConfig is given the modem's certificate is
+// verified against the system roots, which the stock self-signed certificate
+// fails with ErrSelfSigned; TLSOptions can pin it instead.
+func NewModemClient(baseURL string, timeout time.Duration, opts ...Option) *ModemClient {
+	// An address NormalizeBaseURL rejects is kept as given, so that requests
+	// fail with the reason.
+	if u, err := NormalizeBaseURL(baseURL); err == nil {
+		baseURL = u
+	}
+	m := &ModemClient{
+		baseURL:    baseURL,
+		adapter:    adapters[DefaultModel],
+		tlsConfig:  &tls.Config{},
+		conns:      ConnectionOptions{MaxIdleConns: 2, IdleTimeout: 90 * time.Second},
+		validators: map[string]*cachedResponse{},
+		latest:     map[string]latestPage{},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	m.setAddresses()
+
+	// The jar holds the session cookie once logged in.
+	m.jar = newSessionJar()
+	m.client = &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         m.dial,
+			Proxy:               http.ProxyURL(m.proxy),
+			TLSClientConfig:     m.tlsConfig,
+			DisableKeepAlives:   m.conns.DisableKeepAlive,
+			MaxIdleConnsPerHost: m.conns.MaxIdleConns,
+			IdleConnTimeout:     m.conns.IdleTimeout,
+		},
+		Jar: m.jar,
+	}
+	return m
+}
+
+// BaseURL returns the modem URL the client was created with.
+func (m *ModemClient) BaseURL() string {
+	return m.baseURL
+}
+
+// Get returns the raw body of a data page such as "dsinfo.asp". The request
+// is abandoned when ctx is done.
+func (m *ModemClient) Get(ctx context.Context, endpoint string) ([]byte, error) {
+	data, err := m.get(ctx, endpoint)
+	if err == nil {
+		data = m.redacted(data)
+		m.mu.Lock()
+		m.latest[endpoint] = latestPage{body: data, time: time.Now()}
+		m.mu.Unlock()
+	}
+	return data, err
+}
+
+// Latest returns the body of the page from the last time Get succeeded for
+// it, and when that was, without contacting the modem. The body must not be
+// modified.
+func (m *ModemClient) Latest(endpoint string) ([]byte, time.Time, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.latest[endpoint]
+	return p.body, p.time, ok
+}
+
+func (m *ModemClient) get(ctx context.Context, endpoint string) ([]byte, error) {
+	if m.source != nil {
+		return m.source.Fetch(endpoint)
+	}
+	if data, ok := m.takePrefetched(endpoint); ok {
+		return data, nil
+	}
+	data, err := m.fetch(ctx, endpoint)
+	if err != nil && m.fallback != nil {
+		if fb, fbErr := m.fallback.Fetch(endpoint); fbErr == nil {
+			slog.Debug("Using fallback source", "endpoint", endpoint, "err", err)
+			return fb, nil
+		}
+	}
+	return data, err
+}
+
+// fetch requests a single data page from the modem over HTTP, joining any
+// request for the same page that is already in flight. Callers that share a
+// response must not modify it. A joined request runs with the context of
+// the caller that started it.
+func (m *ModemClient) fetch(ctx context.Context, endpoint string) ([]byte, error) {
+	if err := m.checkCircuit(endpoint); err != nil {
+		return nil, err
+	}
+	v, err, shared := m.flight.Do(endpoint, func() (interface{}, error) {
+		return m.doFetch(ctx, endpoint)
+	})
+	if shared {
+		slog.Debug("Shared in-flight request", "endpoint", endpoint)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// doFetch requests a page, logging in and retrying once if the modem
+// answers with its login page instead.
+func (m *ModemClient) doFetch(ctx context.Context, endpoint string) (body []byte, err error) {
+	ctx, span := m.startSpan(ctx, "fetch "+endpoint, attribute.String("hitron.endpoint", endpoint))
+	defer func() {
+		span.SetAttributes(attribute.Int("http.response.body.size", len(body)))
+		endSpan(span, err)
+	}()
+
+	body, err = m.requestWithRetries(ctx, endpoint)
+	if errors.Is(err, ErrLoginRequired) && m.username != "" {
+		if err := m.login(ctx); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrLoginRequired, err)
+		}
+		body, err = m.requestWithRetries(ctx, endpoint)
+	}
+	return body, err
+}
+
+// requestWithRetries requests a page, repeating the request on failure as
+// allowed by the retry policy.
+func (m *ModemClient) requestWithRetries(ctx context.Context, endpoint string) ([]byte, error) {
+	body, err := m.request(ctx, endpoint)
+	for retry := 1; retry <= m.retry.Retries && err != nil; retry++ {
+		if errors.Is(err, ErrEndpointNotFound) || errors.Is(err, ErrLoginRequired) {
+			break
+		}
+		delay := m.retry.backoff(retry)
+		slog.Debug("Retrying modem request", "endpoint", endpoint, "retry", retry, "delay", delay, "err", err)
+		span := trace.SpanFromContext(ctx)
+		span.SetAttributes(attribute.Int("hitron.retries", retry))
+		span.AddEvent("retry", trace.WithAttributes(
+			attribute.Int("hitron.retry", retry),
+			attribute.String("hitron.retry.delay", delay.String()),
+			attribute.String("error", err.Error()),
+		))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to get %s: %w", endpoint, ctx.Err())
+		}
+		body, err = m.request(ctx, endpoint)
+	}
+	m.recordResult(ctx, err)
+	return body, err
+}
+
+// request makes a single request for a page, falling back to the other
+// addresses of the modem; see WithAddressFallbacks.
+func (m *ModemClient) request(ctx context.Context, endpoint string) ([]byte, error) {
+	return m.requestAny(ctx, endpoint)
+}
+
+// requestFrom makes a single request for a page from the modem at base, as
+// a child span of the page's fetch.
+func (m *ModemClient) requestFrom(ctx context.Context, base, endpoint string) ([]byte, error) {
+	url := fmt.Sprintf("%s/data/%s", base, endpoint)
+	ctx, span := m.startSpan(ctx, http.MethodGet,
+		attribute.String("http.request.method", http.MethodGet),
+		attribute.String("url.full", url),
+	)
+	start := time.Now()
+	body, code, err := m.doRequest(ctx, endpoint, url, span)
+	if m.observe != nil {
+		m.observe(endpoint, time.Since(start), code, err)
+	}
+	endSpan(span, err)
+	return body, err
+}
+
+func (m *ModemClient) doRequest(ctx context.Context, endpoint, url string, span trace.Span) ([]byte, int, error) {
+	slog.Debug("Requesting modem page", "url", url)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request for %s: %w", endpoint, err)
+	}
+
+	m.mu.Lock()
+	cached := m.validators[endpoint]
+	m.mu.Unlock()
+	if cached != nil {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get %s: %w", endpoint, certificateError(err))
+	}
+	defer resp.Body.Close()
+	code := resp.StatusCode
+	span.SetAttributes(attribute.Int("http.response.status_code", code))
+
+	if code == http.StatusNotModified && cached != nil {
+		slog.Debug("Modem page not modified", "url", url)
+		m.callHook(endpoint, cached.body)
+		return cached.body, code, nil
+	}
+
+	if code == http.StatusUnauthorized || code == http.StatusForbidden || isLoginRedirect(resp) {
+		return nil, code, fmt.Errorf("%w: %s", ErrLoginRequired, endpoint)
+	}
+	if code == http.StatusNotFound {
+		return nil, code, fmt.Errorf("%w: %s", ErrEndpointNotFound, endpoint)
+	}
+	if code != http.StatusOK {
+		return nil, code, &StatusError{Endpoint: endpoint, Code: code}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, code, fmt.Errorf("failed to read response body for %s: %w", endpoint, err)
+	}
+	span.SetAttributes(attribute.Int("http.response.body.size", len(body)))
+	if isLoginPage(body) {
+		return nil, code, fmt.Errorf("%w: %s", ErrLoginRequired, endpoint)
+	}
+	if !isJSON(body) {
+		return nil, code, fmt.Errorf("%w: %s returned %q", ErrNotJSON, endpoint, truncate(body, 64))
+	}
+
+	// Only firmware that sends validators benefits; everything else is
+	// fetched unconditionally as before.
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	m.mu.Lock()
+	if etag != "" || lastModified != "" {
+		m.validators[endpoint] = &cachedResponse{etag: etag, lastModified: lastModified, body: body}
+	} else {
+		delete(m.validators, endpoint)
+	}
+	m.mu.Unlock()
+
+	m.callHook(endpoint, body)
+	return body, code, nil
+}
+
+func (m *ModemClient) callHook(endpoint string, body []byte) {
+	if m.hook != nil {
+		m.hook(endpoint, m.redacted(body))
+	}
+}
+
+// GetDownstreamInfo returns the downstream SC-QAM channels.
+func (m *ModemClient) GetDownstreamInfo(ctx context.Context) ([]DownstreamInfo, error) {
+	data, err := m.page(ctx, PageDownstream)
+	if err != nil {
+		return nil, err
+	}
+	return m.Adapter().ParseDownstream(data)
+}
+
+// GetUpstreamInfo returns the upstream SC-QAM channels.
+func (m *ModemClient) GetUpstreamInfo(ctx context.Context) ([]UpstreamInfo, error) {
+	data, err := m.page(ctx, PageUpstream)
+	if err != nil {
+		return nil, err
+	}
+	return m.Adapter().ParseUpstream(data)
+}
+
+// GetSystemInfo returns the modem's hardware, firmware and uptime details.
+func (m *ModemClient) GetSystemInfo(ctx context.Context) (*SystemInfo, error) {
+	data, err := m.page(ctx, PageSystemInfo)
+	if err != nil {
+		return nil, err
+	}
+	return m.Adapter().ParseSystemInfo(data)
+}
+
+// GetOFDMDownstreamInfo returns the downstream OFDM channels.
+func (m *ModemClient) GetOFDMDownstreamInfo(ctx context.Context) ([]OFDMDownstreamInfo, error) {
+	data, err := m.page(ctx, PageOFDMDownstream)
+	if err != nil {
+		return nil, err
+	}
+	return m.Adapter().ParseOFDMDownstream(data)
+}
+
+// GetOFDMUpstreamInfo returns the upstream OFDMA channels.
+func (m *ModemClient) GetOFDMUpstreamInfo(ctx context.Context) ([]OFDMUpstreamInfo, error) {
+	data, err := m.page(ctx, PageOFDMUpstream)
+	if err != nil {
+		return nil, err
+	}
+	return m.Adapter().ParseOFDMUpstream(data)
+}
+
+// GetLinkStatus returns the state of the modem's Ethernet link.
+func (m *ModemClient) GetLinkStatus(ctx context.Context) (*LinkStatus, error) {
+	data, err := m.page(ctx, PageLinkStatus)
+	if err != nil {
+		return nil, err
+	}
+	return m.Adapter().ParseLinkStatus(data)
+}
@@ -0,0 +1,90 @@
+package hitron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// CMInit is the modem's DOCSIS initialization page, with the state of each
+// step a cable modem goes through to come online.
+type CMInit struct {
+	HWInit         string `json:"hwInit"`
+	FindDownstream string `json:"findDownstream"`
+	Ranging        string `json:"ranging"`
+	DHCP           string `json:"dhcp"`
+	TimeOfDay      string `json:"timeOfday"`
+	DownloadCfg    string `json:"downloadCfg"`
+	Registration   string `json:"registration"`
+	BPIStatus      string `json:"bpiStatus"`
+	NetworkAccess  string `json:"networkAccess"`
+}
+
+// CMInitStep is one step of the initialization page.
+type CMInitStep struct {
+	// Name is the step in snake case, such as "find_downstream".
+	Name string
+	// Status is the modem's text for it, such as "Success" or "Process".
+	Status string
+	// Done is whether the step completed.
+	Done bool
+}
+
+// Steps returns the initialization steps in the order the modem goes
+// through them.
+func (i *CMInit) Steps() []CMInitStep {
+	steps := []CMInitStep{
+		{Name: "hw_init", Status: i.HWInit},
+		{Name: "find_downstream", Status: i.FindDownstream},
+		{Name: "ranging", Status: i.Ranging},
+		{Name: "dhcp", Status: i.DHCP},
+		{Name: "time_of_day", Status: i.TimeOfDay},
+		{Name: "download_config", Status: i.DownloadCfg},
+		{Name: "registration", Status: i.Registration},
+		{Name: "bpi", Status: i.BPIStatus},
+		{Name: "network_access", Status: i.NetworkAccess},
+	}
+	for n := range steps {
+		steps[n].Status = strings.TrimSpace(steps[n].Status)
+		steps[n].Done = cmInitDone(steps[n].Name, steps[n].Status)
+	}
+	return steps
+}
+
+// cmInitDone reports whether a step's status means it completed. BPI reads
+// like "Enable,Authorized" and network access "Permitted"; the rest report
+// "Success", or "Process" while in progress.
+func cmInitDone(step, status string) bool {
+	s := strings.ToLower(status)
+	switch step {
+	case "bpi":
+		return strings.Contains(s, "authorized") && !strings.Contains(s, "unauthorized") && !strings.Contains(s, "not authorized")
+	case "network_access":
+		return s == "permitted"
+	}
+	return s == "success" || s == "done" || s == "complete" || s == "completed"
+}
+
+// ParseCMInit decodes a CM initialization page.
+func ParseCMInit(data []byte) (*CMInit, error) {
+	records, err := decodeRecords[CMInit](data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CM init JSON: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty CM init response")
+	}
+	slog.Debug("Parsed CM init")
+	return &records[0], nil
+}
+
+// GetCMInit fetches and parses the CM initialization page. Not every
+// firmware serves it, so the page name is passed in.
+func (m *ModemClient) GetCMInit(ctx context.Context, endpoint string) (*CMInit, error) {
+	data, err := m.Get(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCMInit(data)
+}
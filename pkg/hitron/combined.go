@@ -0,0 +1,108 @@
+package hitron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// combinedFailureLimit is how many polls in a row the aggregate status page
+// may fail before PrefetchCombined stops trying it.
+const combinedFailureLimit = 3
+
+// PrefetchCombined tries the firmware's aggregate status page, which carries
+// several endpoints' data in a single response shaped like
+//
+//	{"dsinfo": [...], "usinfo": [...], "dsofdminfo": [...], ...}
+//
+// Keys may be given with or without the ".asp" suffix. Each payload found is
+// handed to the next Get for that endpoint, so a poll only falls back to
+// individual requests for pages the aggregate lacks.
+//
+// The page is no longer tried once firmware shows it doesn't serve it: a
+// 404, a login page or an answer that isn't JSON, a JSON object carrying
+// none of the data pages, or combinedFailureLimit failures in a row for
+// other reasons. An unreachable modem doesn't count towards the latter.
+func (m *ModemClient) PrefetchCombined(ctx context.Context) {
+	if m.source != nil || m.combined == "" || m.combinedMissing.Load() {
+		return
+	}
+
+	data, err := m.fetch(ctx, m.combined)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEndpointNotFound), errors.Is(err, ErrLoginRequired), errors.Is(err, ErrNotJSON):
+			slog.Info("Combined status page not served, using individual endpoints", "endpoint", m.combined, "reason", ClassifyError(err))
+			m.combinedMissing.Store(true)
+		case unreachable(err):
+			slog.Warn("Failed to get combined status page, falling back to individual endpoints", "endpoint", m.combined, "err", err)
+		case m.combinedFailures.Add(1) >= combinedFailureLimit:
+			slog.Warn("Combined status page failed repeatedly, disabling it", "endpoint", m.combined, "failures", combinedFailureLimit, "err", err)
+			m.combinedMissing.Store(true)
+		default:
+			slog.Warn("Failed to get combined status page, falling back to individual endpoints", "endpoint", m.combined, "err", err)
+		}
+		return
+	}
+	m.combinedFailures.Store(0)
+
+	pages, err := SplitCombined(data)
+	if err != nil {
+		slog.Warn("Combined status page is not a JSON object, disabling it", "endpoint", m.combined, "err", err)
+		m.combinedMissing.Store(true)
+		return
+	}
+	if !slices.ContainsFunc(m.Endpoints(), func(e string) bool { _, ok := pages[e]; return ok }) {
+		slog.Warn("Combined status page carries none of the data pages, disabling it", "endpoint", m.combined, "pages", len(pages))
+		m.combinedMissing.Store(true)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prefetched = pages
+	slog.Debug("Fetched combined status page", "endpoint", m.combined, "pages", len(m.prefetched))
+}
+
+// SplitCombined splits the body of the aggregate status page into the
+// payloads of the endpoints it carries, keyed by endpoint name with the
+// ".asp" suffix.
+func SplitCombined(data []byte) (map[string][]byte, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	pages := make(map[string][]byte, len(raw))
+	for key, payload := range raw {
+		endpoint := key
+		if !strings.HasSuffix(endpoint, ".asp") {
+			endpoint += ".asp"
+		}
+		pages[endpoint] = payload
+	}
+	return pages, nil
+}
+
+// unreachable reports whether err means the modem didn't answer at all,
+// which says nothing about the page asked for.
+func unreachable(err error) bool {
+	switch ClassifyError(err) {
+	case ErrorTimeout, ErrorConnectionRefused, ErrorDNS, ErrorTLS:
+		return true
+	}
+	return false
+}
+
+// takePrefetched returns and forgets the prefetched payload for endpoint.
+func (m *ModemClient) takePrefetched(endpoint string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.prefetched[endpoint]
+	if ok {
+		delete(m.prefetched, endpoint)
+	}
+	return data, ok
+}
@@ -0,0 +1,129 @@
+package hitron
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Firmware builds disagree on the details of the data pages: key casing
+// ("channelId" or "channelID"), a few renamed columns, numbers sent as JSON
+// numbers rather than strings, and extra columns. decodeRecords smooths these
+// over so the page structs only need to describe one layout.
+//
+// Keys are matched ignoring case, underscores and dashes, against a field's
+// json tag and then the comma-separated names in its alt tag. A key matching
+// the json tag wins over one matching an alternate. Keys matching no field
+// are logged at debug level.
+
+// recordField is a string field of a page struct and whether a key names it
+// by its json tag rather than an alternate.
+type recordField struct {
+	index   int
+	primary bool
+}
+
+var recordFieldCache sync.Map // reflect.Type -> map[string]recordField
+
+// normalizeKey folds the spelling differences between firmware builds.
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+}
+
+func recordFields(t reflect.Type) map[string]recordField {
+	if cached, ok := recordFieldCache.Load(t); ok {
+		return cached.(map[string]recordField)
+	}
+	fields := map[string]recordField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Type.Kind() != reflect.String {
+			continue
+		}
+		for _, alt := range strings.Split(f.Tag.Get("alt"), ",") {
+			if alt != "" {
+				fields[normalizeKey(alt)] = recordField{index: i}
+			}
+		}
+		if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" && name != "-" {
+			fields[normalizeKey(name)] = recordField{index: i, primary: true}
+		}
+	}
+	recordFieldCache.Store(t, fields)
+	return fields
+}
+
+// decodeRecords decodes a data page holding an array of records, or a single
+// record object, into page structs whose fields are all strings.
+func decodeRecords[T any](data []byte) ([]T, error) {
+	var raw []map[string]json.RawMessage
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var record map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &record); err != nil {
+			return nil, err
+		}
+		raw = append(raw, record)
+	} else if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	fields := recordFields(t)
+	records := make([]T, len(raw))
+	unknown := map[string]bool{}
+	for i, record := range raw {
+		v := reflect.ValueOf(&records[i]).Elem()
+		set := map[int]bool{} // fields set by their json tag
+		for key, value := range record {
+			f, ok := fields[normalizeKey(key)]
+			if !ok {
+				unknown[key] = true
+				continue
+			}
+			if set[f.index] {
+				continue
+			}
+			s, err := recordValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", key, err)
+			}
+			if f.primary || v.Field(f.index).String() == "" {
+				v.Field(f.index).SetString(s)
+			}
+			if f.primary {
+				set[f.index] = true
+			}
+		}
+	}
+	if len(unknown) > 0 {
+		keys := make([]string, 0, len(unknown))
+		for k := range unknown {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		slog.Debug("Ignoring unknown fields", "type", t.Name(), "fields", keys)
+	}
+	return records, nil
+}
+
+// recordValue returns a value as the string the page structs keep: strings
+// as they are, numbers and booleans as written, and null as "".
+func recordValue(raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case len(raw) == 0, bytes.Equal(raw, []byte("null")):
+		return "", nil
+	case raw[0] == '"':
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return s, err
+	case raw[0] == '{', raw[0] == '[':
+		return "", fmt.Errorf("unexpected nested value %s", raw)
+	}
+	return string(raw), nil
+}
@@ -0,0 +1,117 @@
+package hitron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// modelKeys are the system info fields that name the model on firmware that
+// reports it.
+var modelKeys = []string{"modelname", "model", "productname", "productmodel"}
+
+// WithAutoDetect makes the client identify the modem's model instead of
+// assuming a CODA56. Detection happens in DetectModel, which CaptureSnapshot
+// calls; other callers should call it before each poll.
+func WithAutoDetect() Option {
+	return func(m *ModemClient) { m.autoDetect.Store(true) }
+}
+
+// DetectModel identifies the modem's model and switches to its adapter, if
+// the client was created with WithAutoDetect and the model hasn't been
+// detected yet. Detection is retried on later calls until it succeeds; until
+// then the CODA56 adapter is used. Concurrent callers share one attempt.
+// Call it before PrefetchCombined, whose pages it would otherwise use up.
+func (m *ModemClient) DetectModel(ctx context.Context) {
+	if !m.autoDetect.Load() {
+		return
+	}
+	m.flight.Do("\x00detect", func() (interface{}, error) {
+		if !m.autoDetect.Load() {
+			return nil, nil
+		}
+		a, err := m.detectAdapter(ctx)
+		if err != nil {
+			slog.Warn("Failed to detect modem model, assuming "+DefaultModel, "modem", m.baseURL, "err", err)
+			return nil, err
+		}
+		m.mu.Lock()
+		m.adapter = a
+		m.mu.Unlock()
+		m.autoDetect.Store(false)
+		return nil, nil
+	})
+}
+
+// detectAdapter picks the adapter for the modem. The system info page,
+// which every model serves, names the model on some firmware. Otherwise the
+// model is told apart by whether it serves the OFDM pages.
+func (m *ModemClient) detectAdapter(ctx context.Context) (ModemAdapter, error) {
+	data, err := m.Get(ctx, EndpointSystemInfo)
+	if err != nil {
+		return nil, err
+	}
+	info, err := ParseSystemInfo(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := modelName(data); name != "" {
+		if a := matchAdapter(name); a != nil {
+			slog.Info("Detected modem model", "modem", m.baseURL, "model", a.Model(), "reported", name,
+				"hw_version", info.HWVersion, "sw_version", info.SWVersion)
+			return a, nil
+		}
+		slog.Info("Modem reports an unknown model, detecting from its pages", "modem", m.baseURL, "reported", name)
+	}
+
+	a := adapters[DefaultModel]
+	_, err = m.Get(ctx, EndpointOFDMDownstream)
+	switch {
+	case errors.Is(err, ErrEndpointNotFound):
+		a = adapters["coda45"]
+	case err != nil:
+		return nil, fmt.Errorf("failed to probe %s: %w", EndpointOFDMDownstream, err)
+	}
+	slog.Info("Detected modem model from its pages", "modem", m.baseURL, "model", a.Model(),
+		"hw_version", info.HWVersion, "sw_version", info.SWVersion)
+	return a, nil
+}
+
+// modelName returns the model named in a system info payload, if any.
+func modelName(data []byte) string {
+	var fields []map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil || len(fields) == 0 {
+		return ""
+	}
+	for k, v := range fields[0] {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		for _, key := range modelKeys {
+			if strings.EqualFold(k, key) {
+				return strings.TrimSpace(s)
+			}
+		}
+	}
+	return ""
+}
+
+// matchAdapter returns the adapter for a reported model name such as
+// "CODA-4582U", matching the longest known model it starts with.
+func matchAdapter(name string) ModemAdapter {
+	name = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(name))
+	models := Models()
+	sort.Slice(models, func(i, j int) bool { return len(models[i]) > len(models[j]) })
+	for _, model := range models {
+		if strings.HasPrefix(name, model) {
+			return adapters[model]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package hitron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// NormalizeBaseURL turns a modem address into the base URL the client
+// expects. Besides a full URL it accepts a host name or IP address alone,
+// for which https is assumed, and IPv6 literals with or without brackets.
+// A link-local zone may be written as in "fe80::1%eth0"; it is escaped as the
+// URL syntax requires. A trailing slash is dropped.
+func NormalizeBaseURL(host string) (string, error) {
+	s := strings.TrimSpace(host)
+	if s == "" {
+		return "", errors.New("missing modem host")
+	}
+	if !strings.Contains(s, "://") {
+		if ip, _, _ := strings.Cut(s, "%"); strings.Contains(ip, ":") && net.ParseIP(ip) != nil {
+			s = "[" + s + "]"
+		}
+		s = "https://" + s
+	}
+	// Zones are commonly written unescaped, which url.Parse rejects.
+	if open, end := strings.Index(s, "["), strings.Index(s, "]"); open >= 0 && end > open {
+		literal := s[open:end]
+		if strings.Contains(literal, "%") && !strings.Contains(literal, "%25") {
+			s = s[:open] + strings.Replace(literal, "%", "%25", 1) + s[end:]
+		}
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid modem host %q: %w", host, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid modem host %q: expected an http or https URL", host)
+	}
+	u.Path = strings.TrimRight(u.Path, "/")
+	return u.String(), nil
+}
+
+// WithDialContext makes the client open its connections to the modem with
+// dial, for example to go through a proxy or bind to a particular interface.
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	return func(m *ModemClient) { m.dial = dial }
+}
+
+// WithResolvedAddress makes the client connect to addr, an IP address or
+// host name, in place of the host in the modem URL, like curl's --resolve.
+// The URL's host is still sent in requests and used to verify the
+// certificate. The port is kept from the URL. Connections to a proxy are
+// left alone, so behind one the proxy resolves the modem's host itself.
+func WithResolvedAddress(addr string) Option {
+	addr = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+	var d net.Dialer
+	return func(m *ModemClient) {
+		m.resolved = addr
+		m.dial = func(ctx context.Context, network, hostport string) (net.Conn, error) {
+			host, port, err := net.SplitHostPort(hostport)
+			if err != nil {
+				return nil, err
+			}
+			if u, err := url.Parse(m.baseURL); err == nil && u.Hostname() == host {
+				hostport = net.JoinHostPort(addr, port)
+			}
+			return d.DialContext(ctx, network, hostport)
+		}
+	}
+}
+
+// ParseProxyURL parses the URL of a proxy to reach the modem through: an
+// HTTP or HTTPS proxy that supports CONNECT, or a SOCKS5 proxy, such as
+// "socks5://jumphost:1080". Credentials may be given in the URL.
+func ParseProxyURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: expected an http, https or socks5 URL", u.Redacted())
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", u.Redacted())
+	}
+	return u, nil
+}
+
+// WithProxy makes the client reach the modem through the proxy at u, for
+// example from ParseProxyURL. Proxy environment variables such as
+// HTTPS_PROXY are never used.
+func WithProxy(u *url.URL) Option {
+	return func(m *ModemClient) { m.proxy = u }
+}
@@ -0,0 +1,37 @@
+package hitron
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProxy(t *testing.T) {
+	var requested string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.String()
+		fmt.Fprint(w, "[]")
+	}))
+	defer proxy.Close()
+
+	u, err := ParseProxyURL(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewModemClient("http://modem.invalid", time.Second, WithProxy(u), WithResolvedAddress("192.0.2.1"))
+	if _, err := m.GetUpstreamInfo(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := "http://modem.invalid/data/" + EndpointUpstream; requested != want {
+		t.Errorf("proxy got %q, want %q", requested, want)
+	}
+
+	for _, bad := range []string{"ftp://proxy:21", "socks5://", "proxy:3128"} {
+		if _, err := ParseProxyURL(bad); err == nil {
+			t.Errorf("ParseProxyURL(%q) succeeded", bad)
+		}
+	}
+}
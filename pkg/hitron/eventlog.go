@@ -0,0 +1,110 @@
+package hitron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventLogEntry is one row of the modem's DOCSIS event log.
+type EventLogEntry struct {
+	Index    string `json:"index"`
+	Time     string `json:"time"`
+	Priority string `json:"priority"`
+	Event    string `json:"event"`
+}
+
+// ParseEventLog decodes an event log page.
+func ParseEventLog(data []byte) ([]EventLogEntry, error) {
+	entries, err := decodeRecords[EventLogEntry](data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse event log JSON: %w", err)
+	}
+	slog.Debug("Parsed event log", "entries", len(entries))
+	return entries, nil
+}
+
+// GetEventLog fetches and parses the event log page. The page name differs
+// between firmware builds, so it is passed in.
+func (m *ModemClient) GetEventLog(ctx context.Context, endpoint string) ([]EventLogEntry, error) {
+	data, err := m.Get(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEventLog(data)
+}
+
+// EventType classifies event log messages that point at plant problems.
+type EventType string
+
+const (
+	EventT3Timeout             EventType = "t3_timeout"
+	EventT4Timeout             EventType = "t4_timeout"
+	EventSyncLoss              EventType = "sync_loss"
+	EventDynamicRangeViolation EventType = "dynamic_range_violation"
+	EventOther                 EventType = "other"
+)
+
+// EventTypes lists every EventType.
+var EventTypes = []EventType{
+	EventT3Timeout,
+	EventT4Timeout,
+	EventSyncLoss,
+	EventDynamicRangeViolation,
+	EventOther,
+}
+
+// ClassifyEvent maps an event log message to its EventType, going by the
+// standard DOCSIS event texts, e.g. "No Ranging Response received - T3
+// time-out".
+func ClassifyEvent(message string) EventType {
+	m := strings.ToLower(message)
+	switch {
+	case strings.Contains(m, "t3 time"):
+		return EventT3Timeout
+	case strings.Contains(m, "t4 time"):
+		return EventT4Timeout
+	case strings.Contains(m, "loss of sync") || strings.Contains(m, "synchronization failure"):
+		return EventSyncLoss
+	case strings.Contains(m, "dynamic range window violation"):
+		return EventDynamicRangeViolation
+	}
+	return EventOther
+}
+
+var priorityLevelRe = regexp.MustCompile(`[0-9]+`)
+
+// IsCritical reports whether the entry's priority is critical or worse:
+// emergency (1), alert (2) or critical (3). The modem gives the priority
+// either as a number or as text such as "critical(3)".
+func (e EventLogEntry) IsCritical() bool {
+	p := strings.ToLower(e.Priority)
+	if level, err := strconv.Atoi(priorityLevelRe.FindString(p)); err == nil {
+		return level >= 1 && level <= 3
+	}
+	return strings.Contains(p, "emergency") || strings.Contains(p, "alert") || strings.Contains(p, "critical")
+}
+
+var eventTimeLayouts = []string{
+	"01/02/2006 15:04:05",
+	"Mon Jan 2 15:04:05 2006",
+	"Mon Jan _2 15:04:05 2006",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+}
+
+// ParseEventTime parses an event log timestamp, which has no zone and is
+// taken to be in loc.
+func ParseEventTime(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range eventTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized event time %q", s)
+}
@@ -0,0 +1,207 @@
+package hitron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fallbackAfter is how many times in a row a page has to fail at the
+// address the client was created with before the fallbacks are tried. A
+// poll requests every page once, so that is as many polls.
+const fallbackAfter = 3
+
+// primaryRetryInterval is how often the address the client was created
+// with is tried again while a fallback is in use.
+const primaryRetryInterval = time.Minute
+
+// WithAddressFallbacks makes the client try other schemes and ports of the
+// modem's host, in order, when a page keeps getting no response, a 404 or
+// an answer that isn't JSON, since firmware builds differ in which of them
+// serve the data pages. Each fallback is "http" or "https", optionally
+// followed by ":port", such as "http" or "https:8443"; without a port the
+// scheme's default is used. Invalid fallbacks, which FallbackURL rejects,
+// are ignored.
+//
+// The address the client was created with is preferred: the fallbacks are
+// only tried once a page has failed there three times in a row. The first
+// fallback that answers is then used, logins included, and the original
+// address is tried again every minute until it answers, which switches
+// back to it. Credentials only go over plain HTTP to a fallback listed
+// here.
+func WithAddressFallbacks(fallbacks ...string) Option {
+	return func(m *ModemClient) { m.addressFallbacks = fallbacks }
+}
+
+// FallbackURL returns the modem URL base with the scheme and port of
+// fallback, as given to WithAddressFallbacks.
+func FallbackURL(base, fallback string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid modem URL: %w", err)
+	}
+	scheme, port, _ := strings.Cut(strings.TrimSpace(fallback), ":")
+	if scheme != "http" && scheme != "https" {
+		return "", fmt.Errorf("invalid modem fallback %q: expected http or https, with an optional :port", fallback)
+	}
+	if port != "" {
+		if _, err := net.LookupPort("tcp", port); err != nil {
+			return "", fmt.Errorf("invalid modem fallback %q: %w", fallback, err)
+		}
+	}
+	u.Scheme = scheme
+	if port == "" {
+		// JoinHostPort brackets IPv6 addresses, and the colon is dropped
+		u.Host = strings.TrimSuffix(net.JoinHostPort(u.Hostname(), ""), ":")
+	} else {
+		u.Host = net.JoinHostPort(u.Hostname(), port)
+	}
+	return u.String(), nil
+}
+
+// fallbackState tracks when to leave the address the client was created
+// with, and when to try it again.
+type fallbackState struct {
+	mu sync.Mutex
+	// failures counts the consecutive failures of each page at the
+	// original address.
+	failures map[string]int
+	// retried is when the original address was last tried while a fallback
+	// was in use.
+	retried time.Time
+	// retryInterval is primaryRetryInterval, shortened in tests.
+	retryInterval time.Duration
+}
+
+// failed counts a failure of endpoint at the original address and returns
+// how many there have been in a row.
+func (s *fallbackState) failed(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[endpoint]++
+	return s.failures[endpoint]
+}
+
+// answered resets the count of endpoint.
+func (s *fallbackState) answered(endpoint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.failures, endpoint)
+}
+
+// switched starts the wait before the original address is tried again, or
+// clears the counts after switching back to it.
+func (s *fallbackState) switched(toPrimary bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if toPrimary {
+		clear(s.failures)
+		return
+	}
+	s.retried = time.Now()
+}
+
+// retryDue reports whether it is time to try the original address again,
+// and if so starts the next wait.
+func (s *fallbackState) retryDue() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Since(s.retried) < s.retryInterval {
+		return false
+	}
+	s.retried = time.Now()
+	return true
+}
+
+// setAddresses lists the URL the client was created with and its fallbacks,
+// without duplicates.
+func (m *ModemClient) setAddresses() {
+	m.fallbackState = &fallbackState{failures: map[string]int{}, retryInterval: primaryRetryInterval}
+	m.addresses = []string{m.baseURL}
+	for _, f := range m.addressFallbacks {
+		u, err := FallbackURL(m.baseURL, f)
+		if err != nil {
+			slog.Warn("Ignoring modem fallback", "modem", m.baseURL, "err", err)
+			continue
+		}
+		if !slices.Contains(m.addresses, u) {
+			m.addresses = append(m.addresses, u)
+		}
+	}
+}
+
+// Address returns the modem URL requests currently go to: the one the
+// client was created with, or the fallback that last answered in its place.
+func (m *ModemClient) Address() string {
+	return m.addresses[m.current.Load()]
+}
+
+// shouldFallBack reports whether err from one address is worth trying the
+// others for.
+func shouldFallBack(err error) bool {
+	return isUnreachable(err) || errors.Is(err, ErrEndpointNotFound) || errors.Is(err, ErrNotJSON)
+}
+
+// requestAny requests a page from the current address. While a fallback is
+// in use, the original address is tried first every primaryRetryInterval,
+// and switched back to if it answers. If the current address fails in a way
+// another might not, the others are tried in order, from the original
+// address only once the page has failed there fallbackAfter times in a
+// row. The first that answers becomes the current address. The error is
+// the current address's.
+func (m *ModemClient) requestAny(ctx context.Context, endpoint string) ([]byte, error) {
+	if len(m.addresses) == 1 {
+		return m.requestFrom(ctx, m.baseURL, endpoint)
+	}
+	s := m.fallbackState
+	current := int(m.current.Load())
+	if current != 0 && s.retryDue() {
+		if body, err := m.requestFrom(ctx, m.baseURL, endpoint); err == nil {
+			m.switchAddress(current, 0, nil)
+			return body, nil
+		}
+	}
+	body, err := m.requestFrom(ctx, m.addresses[current], endpoint)
+	if current == 0 {
+		if err == nil || !shouldFallBack(err) {
+			s.answered(endpoint)
+			return body, err
+		}
+		if ctx.Err() != nil || s.failed(endpoint) < fallbackAfter {
+			return body, err
+		}
+	} else if err == nil || !shouldFallBack(err) {
+		return body, err
+	}
+	for i, addr := range m.addresses {
+		if i == current || ctx.Err() != nil {
+			continue
+		}
+		if fb, fbErr := m.requestFrom(ctx, addr, endpoint); fbErr == nil {
+			m.switchAddress(current, i, err)
+			return fb, nil
+		}
+	}
+	return body, err
+}
+
+// switchAddress makes addresses[to] the current address, unless a
+// concurrent request has already moved it off addresses[from].
+func (m *ModemClient) switchAddress(from, to int, err error) {
+	if !m.current.CompareAndSwap(int32(from), int32(to)) {
+		return
+	}
+	m.fallbackState.switched(to == 0)
+	if to == 0 {
+		slog.Info("Switched back to modem address", "modem", m.baseURL, "from", m.addresses[from])
+		return
+	}
+	slog.Warn("Switched modem address", "modem", m.baseURL, "from", m.addresses[from], "to", m.addresses[to], "err", err)
+}
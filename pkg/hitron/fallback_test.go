@@ -0,0 +1,91 @@
+package hitron
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFallbackURL(t *testing.T) {
+	tests := []struct {
+		base, fallback, want string
+		wantErr              bool
+	}{
+		{"https://192.168.100.1", "http", "http://192.168.100.1", false},
+		{"https://192.168.100.1", "https:8443", "https://192.168.100.1:8443", false},
+		{"https://modem.lan:8443", "http", "http://modem.lan", false},
+		{"https://[fd00::1]:8443", "http:8080", "http://[fd00::1]:8080", false},
+		{"https://[fd00::1]", "http", "http://[fd00::1]", false},
+		{"https://192.168.100.1", "ftp", "", true},
+		{"https://192.168.100.1", "http:none", "", true},
+	}
+	for _, tt := range tests {
+		got, err := FallbackURL(tt.base, tt.fallback)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("FallbackURL(%q, %q) = %q, %v, want %q, error %v", tt.base, tt.fallback, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestAddressFallbacks(t *testing.T) {
+	var primaryDown atomic.Bool
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if primaryDown.Load() {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(readFixture(t, "usinfo.json"))
+	}))
+	defer primary.Close()
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(readFixture(t, "usinfo.json"))
+	}))
+	defer fallback.Close()
+	port := fallback.URL[strings.LastIndex(fallback.URL, ":")+1:]
+
+	m := NewModemClient(primary.URL, time.Second, WithRetryPolicy(RetryPolicy{}), WithAddressFallbacks("https:1", "http:"+port))
+	primaryDown.Store(true)
+	for i := 1; i < fallbackAfter; i++ {
+		if _, err := m.GetUpstreamInfo(context.Background()); err == nil {
+			t.Fatalf("request %d succeeded before falling back", i)
+		}
+	}
+	if got := m.Address(); got != primary.URL {
+		t.Errorf("Address() = %q before %d failures, want %q", got, fallbackAfter, primary.URL)
+	}
+	if _, err := m.GetUpstreamInfo(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Address(); got != fallback.URL {
+		t.Errorf("Address() = %q after falling back, want %q", got, fallback.URL)
+	}
+	if got := m.BaseURL(); got != primary.URL {
+		t.Errorf("BaseURL() = %q, want %q", got, primary.URL)
+	}
+
+	// The original address is retried once the interval is up, and taken
+	// back as soon as it answers.
+	m.fallbackState.retryInterval = 0
+	if _, err := m.GetUpstreamInfo(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Address(); got != fallback.URL {
+		t.Errorf("Address() = %q while the original is still down, want %q", got, fallback.URL)
+	}
+	primaryDown.Store(false)
+	if _, err := m.GetUpstreamInfo(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Address(); got != primary.URL {
+		t.Errorf("Address() = %q once the original answers again, want %q", got, primary.URL)
+	}
+
+	m = NewModemClient(strings.Replace(fallback.URL, "http://", "https://", 1), time.Second)
+	if _, err := m.GetUpstreamInfo(context.Background()); err == nil {
+		t.Error("expected an error without fallbacks")
+	}
+}
@@ -0,0 +1,114 @@
+package hitron
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// login performs the web interface's login flow: the login page hands out a
+// preSession cookie that must be echoed back with the credentials, and a
+// successful POST sets the session cookie used by later requests.
+// Concurrent callers share a single attempt.
+func (m *ModemClient) login(ctx context.Context) error {
+	_, err, _ := m.flight.Do("\x00login", func() (interface{}, error) {
+		ctx, span := m.startSpan(ctx, "login")
+		err := m.doLogin(ctx)
+		endSpan(span, err)
+		return nil, err
+	})
+	return err
+}
+
+func (m *ModemClient) doLogin(ctx context.Context) error {
+	slog.Info("Logging in to modem", "modem", m.baseURL, "username", m.username)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.Address()+"/login.html", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create login page request: %w", err)
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to get login page: %w", certificateError(err))
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	form := url.Values{
+		"usr":         {m.username},
+		"pwd":         {m.password},
+		"forcelogoff": {"1"},
+	}
+	if preSession := m.cookie("preSession"); preSession != "" {
+		form.Set("preSession", preSession)
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, m.Address()+"/goform/login", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create login request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err = m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to log in: %w", certificateError(err))
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read login response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || !strings.Contains(strings.ToLower(string(body)), "success") {
+		return fmt.Errorf("modem rejected login (status %d): %q", resp.StatusCode, truncate(body, 64))
+	}
+	slog.Info("Logged in to modem", "modem", m.baseURL)
+	return nil
+}
+
+// cookie returns the value of the named cookie the modem has set, if any.
+func (m *ModemClient) cookie(name string) string {
+	u, err := url.Parse(m.Address())
+	if err != nil {
+		return ""
+	}
+	for _, c := range m.client.Jar.Cookies(u) {
+		if c.Name == name {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+// isLoginRedirect reports whether the request was redirected to the login
+// page, which is how the modem answers once a session has expired.
+func isLoginRedirect(resp *http.Response) bool {
+	return resp.Request != nil && strings.Contains(strings.ToLower(resp.Request.URL.Path), "login")
+}
+
+// isLoginPage reports whether body is HTML rather than the JSON the data
+// pages return. Some firmware serves the login form in place of the page,
+// with status 200.
+func isLoginPage(body []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("<"))
+}
+
+// isJSON reports whether body starts like the JSON array or object of a data
+// page. It doesn't validate the rest, which is the parsers' job.
+func isJSON(body []byte) bool {
+	body = bytes.TrimSpace(body)
+	return len(body) > 0 && (body[0] == '[' || body[0] == '{')
+}
+
+// truncate shortens body to at most n bytes for an error message.
+func truncate(body []byte, n int) string {
+	body = bytes.TrimSpace(body)
+	if len(body) > n {
+		return string(body[:n]) + "..."
+	}
+	return string(body)
+}
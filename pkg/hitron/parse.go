@@ -0,0 +1,421 @@
+package hitron
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/bits"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDownstreamInfo decodes the dsinfo.asp payload.
+func ParseDownstreamInfo(data []byte) ([]DownstreamInfo, error) {
+	channels, err := decodeRecords[DownstreamInfo](data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse downstream info JSON: %w", err)
+	}
+	slog.Debug("Parsed downstream channels", "channels", len(channels))
+	return channels, nil
+}
+
+// ParseUpstreamInfo decodes the usinfo.asp payload.
+func ParseUpstreamInfo(data []byte) ([]UpstreamInfo, error) {
+	channels, err := decodeRecords[UpstreamInfo](data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse upstream info JSON: %w", err)
+	}
+	slog.Debug("Parsed upstream channels", "channels", len(channels))
+	return channels, nil
+}
+
+// ParseSystemInfo decodes the getSysInfo.asp payload, a one-element array.
+func ParseSystemInfo(data []byte) (*SystemInfo, error) {
+	sysInfoArray, err := decodeRecords[SystemInfo](data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse system info JSON: %w", err)
+	}
+	if len(sysInfoArray) == 0 {
+		return nil, fmt.Errorf("empty system info response")
+	}
+	slog.Debug("Parsed system info")
+	return &sysInfoArray[0], nil
+}
+
+// ParseOFDMDownstreamInfo decodes the dsofdminfo.asp payload.
+func ParseOFDMDownstreamInfo(data []byte) ([]OFDMDownstreamInfo, error) {
+	channels, err := decodeRecords[OFDMDownstreamInfo](data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OFDM downstream info JSON: %w", err)
+	}
+	slog.Debug("Parsed OFDM downstream channels", "channels", len(channels))
+	return channels, nil
+}
+
+// ParseOFDMUpstreamInfo decodes the usofdminfo.asp payload.
+func ParseOFDMUpstreamInfo(data []byte) ([]OFDMUpstreamInfo, error) {
+	channels, err := decodeRecords[OFDMUpstreamInfo](data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OFDM upstream info JSON: %w", err)
+	}
+	slog.Debug("Parsed OFDM upstream channels", "channels", len(channels))
+	return channels, nil
+}
+
+// ParseLinkStatus decodes the getLinkStatus.asp payload, a one-element array.
+func ParseLinkStatus(data []byte) (*LinkStatus, error) {
+	linkStatusArray, err := decodeRecords[LinkStatus](data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse link status JSON: %w", err)
+	}
+	if len(linkStatusArray) == 0 {
+		return nil, fmt.Errorf("empty link status response")
+	}
+	slog.Debug("Parsed link status")
+	return &linkStatusArray[0], nil
+}
+
+// ParseComplexOctets parses the QAM downstream octet format, which splits the
+// 64-bit total into a high and a low 32-bit word: "53 * 2e32 + 4142950845".
+// The firmware writes "2e32" for 2^32; a plain integer multiplier is also
+// accepted. Plain numbers are returned as-is.
+func ParseComplexOctets(octetsStr string) (uint64, error) {
+	s := strings.TrimSpace(octetsStr)
+	if simple, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return simple, nil
+	}
+
+	high, low, ok := strings.Cut(s, "+")
+	if !ok {
+		return 0, fmt.Errorf("unrecognized octet count %q", octetsStr)
+	}
+	word, factor, ok := strings.Cut(high, "*")
+	if !ok {
+		return 0, fmt.Errorf("unrecognized octet count %q", octetsStr)
+	}
+
+	hi, err := strconv.ParseUint(strings.TrimSpace(word), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unrecognized octet count %q: %w", octetsStr, err)
+	}
+	mult, err := parseOctetFactor(strings.TrimSpace(factor))
+	if err != nil {
+		return 0, fmt.Errorf("unrecognized octet count %q: %w", octetsStr, err)
+	}
+	lo, err := strconv.ParseUint(strings.TrimSpace(low), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unrecognized octet count %q: %w", octetsStr, err)
+	}
+
+	overflow, product := bits.Mul64(hi, mult)
+	sum, carry := bits.Add64(product, lo, 0)
+	if overflow != 0 || carry != 0 {
+		return 0, fmt.Errorf("octet count %q overflows 64 bits", octetsStr)
+	}
+	return sum, nil
+}
+
+// parseOctetFactor parses the multiplier of the high word.
+func parseOctetFactor(s string) (uint64, error) {
+	switch s {
+	case "2e32", "2^32":
+		return 1 << 32, nil
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+var byteCountRe = regexp.MustCompile(`(?i)^([0-9]+(?:\.[0-9]+)?)\s*([kmgt]?)\s*(?:i?b|bytes?)?$`)
+
+// byteUnits are the multiples of the byte count suffixes, which the modem
+// uses in the binary sense.
+var byteUnits = map[string]float64{"": 1, "k": 1 << 10, "m": 1 << 20, "g": 1 << 30, "t": 1 << 40}
+
+// ParseByteCount parses the WAN and LAN traffic figures of the system info
+// page, such as "812.4 GBytes", "1.0M Bytes" or "512 Bytes", into bytes. The modem
+// rounds them to one decimal, so large counts are only accurate to a tenth
+// of their unit.
+func ParseByteCount(s string) (float64, error) {
+	m := byteCountRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("unrecognized byte count %q", s)
+	}
+	v, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("unrecognized byte count %q: %w", s, err)
+	}
+	return v * byteUnits[strings.ToLower(m[2])], nil
+}
+
+var modulationOrderRe = regexp.MustCompile(`[0-9]+`)
+
+// ModulationBits converts a modulation string such as "QAM256" or "256QAM"
+// into bits per symbol (log2 of the constellation size).
+func ModulationBits(modulation string) (float64, bool) {
+	order, err := strconv.Atoi(modulationOrderRe.FindString(modulation))
+	if err != nil || order < 2 {
+		return 0, false
+	}
+	return math.Log2(float64(order)), true
+}
+
+// ofdmMinimumSNR is the lowest SNR, in dB, at which DOCSIS 3.1 requires a
+// modem to receive each OFDM subcarrier modulation, by bits per subcarrier,
+// highest first.
+var ofdmMinimumSNR = []struct{ bits, snr float64 }{
+	{12, 41}, {11, 37}, {10, 34}, {9, 30.5}, {8, 27}, {7, 24}, {6, 21}, {4, 15},
+}
+
+// OFDMBitLoading returns the bits per subcarrier of the highest OFDM
+// modulation an OFDM downstream channel with the given SNR can carry under
+// the DOCSIS 3.1 minimum CNR requirements, such as 12 (QAM4096) at 41 dB. The
+// modem pages don't report the profiles the CMTS assigned, and a profile
+// above what the SNR supports fails, so this bounds the modulation in use.
+// Below the 15 dB that QAM16 needs it is 0.
+func OFDMBitLoading(snr float64) float64 {
+	for _, m := range ofdmMinimumSNR {
+		if snr >= m.snr {
+			return m.bits
+		}
+	}
+	return 0
+}
+
+var qamOrderRe = regexp.MustCompile(`(?i)(?:([0-9]+)\s*[-_ ]?\s*qam|qam\s*[-_ ]?\s*([0-9]+))`)
+
+// NormalizeModulation gives the modulation and ModType strings of different
+// firmware builds one spelling for labels: any QAM order becomes "QAM<n>"
+// ("64QAM", "qam_64" and "ATDMA 64-QAM" are all "QAM64"), and anything else
+// is upper-cased with runs of spaces and underscores turned into a dash
+// ("sc qam" becomes "SC-QAM", "atdma" becomes "ATDMA").
+func NormalizeModulation(s string) string {
+	if m := qamOrderRe.FindStringSubmatch(s); m != nil {
+		return "QAM" + m[1] + m[2]
+	}
+	fields := strings.FieldsFunc(strings.ToUpper(s), func(r rune) bool { return r == ' ' || r == '_' })
+	return strings.Join(fields, "-")
+}
+
+// ParseSCDMAMode reports whether an upstream channel's scdmaMode says it runs
+// S-CDMA. Firmware builds give either the access mode ("ATDMA", "TDMA",
+// "SCDMA") or whether S-CDMA is on ("Enabled", "Disabled", "1", "0"). ok is
+// false for anything else.
+func ParseSCDMAMode(s string) (scdma, ok bool) {
+	switch strings.ToUpper(strings.NewReplacer("-", "", "_", "", " ", "").Replace(s)) {
+	case "SCDMA", "ENABLED", "ENABLE", "ON", "TRUE", "1":
+		return true, true
+	case "ATDMA", "TDMA", "TDMAANDATDMA", "DISABLED", "DISABLE", "OFF", "FALSE", "0":
+		return false, true
+	}
+	return false, false
+}
+
+// ParseFFTType maps the OFDM FFT type ("4K" or "8K") to its FFT size and
+// subcarrier spacing. DOCSIS 3.1 uses 50 kHz spacing with the 4K FFT and
+// 25 kHz with the 8K FFT, so both span 204.8 MHz from subcarrier zero.
+func ParseFFTType(fftType string) (size int, spacing float64, ok bool) {
+	switch strings.ToUpper(strings.TrimSpace(fftType)) {
+	case "4K":
+		return 4096, 50e3, true
+	case "8K":
+		return 8192, 25e3, true
+	}
+	return 0, 0, false
+}
+
+var linkSpeedRe = regexp.MustCompile(`(?i)^([0-9]+(?:\.[0-9]+)?)\s*([kmgt]?)(?:bps|b/s|bit/s)?$`)
+
+// ParseLinkSpeed normalizes the LinkSpeed formats seen across firmware
+// variants ("2500Mbps", "2.5Gbps", "1000M", "1 Gbps") to bits per second.
+// A bare number is taken to be Mbps, matching the original firmware.
+func ParseLinkSpeed(s string) (float64, error) {
+	m := linkSpeedRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("unrecognized link speed %q", s)
+	}
+	value, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("unrecognized link speed %q: %w", s, err)
+	}
+
+	switch strings.ToLower(m[2]) {
+	case "k":
+		return value * 1e3, nil
+	case "", "m":
+		return value * 1e6, nil
+	case "g":
+		return value * 1e9, nil
+	default: // "t"
+		return value * 1e12, nil
+	}
+}
+
+// ParseDuplex normalizes the LinkDuplex values seen across firmware variants
+// ("Full", "FULL", "Full Duplex", "half-duplex") to "full" or "half". Anything
+// else is returned trimmed and lower-cased, and an empty value stays empty.
+func ParseDuplex(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, mode := range []string{"full", "half"} {
+		if strings.HasPrefix(s, mode) {
+			return mode
+		}
+	}
+	return s
+}
+
+// ErrNoValue is returned for the placeholders the modem shows in place of a
+// number, such as "NA", "----" or an empty string.
+var ErrNoValue = errors.New("no value")
+
+// normalizeDecimal trims s and turns a decimal comma ("3,5") into a point.
+// A string that already has a point, or several commas, is left as is.
+func normalizeDecimal(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.Count(s, ",") == 1 && !strings.Contains(s, ".") {
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	return s
+}
+
+// ParseNumber parses a numeric field such as a power level, SNR or error
+// count. Surrounding whitespace and decimal commas are accepted. Placeholders
+// ("NA", "N/A", "-", "----", "") return an error wrapping ErrNoValue, so that
+// callers can skip the sample rather than report 0.
+func ParseNumber(s string) (float64, error) {
+	v := normalizeDecimal(s)
+	switch {
+	case v == "", strings.Trim(v, "-") == "", strings.EqualFold(v, "NA"), strings.EqualFold(v, "N/A"):
+		return 0, fmt.Errorf("%q: %w", s, ErrNoValue)
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, fmt.Errorf("unrecognized number %q", s)
+	}
+	return n, nil
+}
+
+var frequencyRe = regexp.MustCompile(`(?i)^([0-9]+(?:\.[0-9]+)?)\s*([kmg]?hz)?$`)
+
+// mhzThreshold separates bare numbers given in MHz from those given in Hz.
+// Every DOCSIS frequency and channel width is well above 10 kHz, while no MHz
+// figure comes close to it.
+const mhzThreshold = 1e4
+
+// ParseFrequency normalizes a frequency or channel width to Hz. Most pages
+// report plain Hz ("591000000"), but some report MHz ("44.0" for an OFDMA
+// channel width) or carry a unit ("591 MHz"). Bare numbers below 10000 are
+// taken to be MHz.
+func ParseFrequency(s string) (float64, error) {
+	m := frequencyRe.FindStringSubmatch(normalizeDecimal(s))
+	if m == nil {
+		return 0, fmt.Errorf("unrecognized frequency %q", s)
+	}
+	value, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("unrecognized frequency %q: %w", s, err)
+	}
+
+	switch strings.ToLower(m[2]) {
+	case "hz":
+		return value, nil
+	case "khz":
+		return value * 1e3, nil
+	case "mhz":
+		return value * 1e6, nil
+	case "ghz":
+		return value * 1e9, nil
+	}
+	if value != 0 && value < mhzThreshold {
+		return value * 1e6, nil
+	}
+	return value, nil
+}
+
+var uptimePartRe = regexp.MustCompile(`(?i)([0-9]+)\s*(day|hour|minute|min|second|sec)s?\b`)
+
+// ParseUptime converts the modem's uptime string, such as
+// "05 Days,12 Hours,33 Minutes,00 Seconds", into a duration.
+func ParseUptime(s string) (time.Duration, error) {
+	parts := uptimePartRe.FindAllStringSubmatch(s, -1)
+	if parts == nil {
+		return 0, fmt.Errorf("unrecognized uptime %q", s)
+	}
+
+	var d time.Duration
+	for _, p := range parts {
+		n, err := strconv.ParseInt(p[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unrecognized uptime %q: %w", s, err)
+		}
+		switch strings.ToLower(p[2]) {
+		case "day":
+			d += time.Duration(n) * 24 * time.Hour
+		case "hour":
+			d += time.Duration(n) * time.Hour
+		case "minute", "min":
+			d += time.Duration(n) * time.Minute
+		default:
+			d += time.Duration(n) * time.Second
+		}
+	}
+	return d, nil
+}
+
+var timezoneRe = regexp.MustCompile(`(?i)^(?:gmt|utc)?\s*([+-]?)([0-9]{1,2})(?:(?::([0-9]{2}))|(?:\.([0-9]+)))?$`)
+
+// ParseTimezone parses the modem's timezone setting, an offset from UTC in
+// hours such as "-7", "+5.5", "-07:00" or "GMT+1".
+func ParseTimezone(s string) (*time.Location, error) {
+	s = strings.TrimSpace(s)
+	m := timezoneRe.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("unrecognized timezone %q", s)
+	}
+	hours, _ := strconv.Atoi(m[2])
+	seconds := hours * 3600
+	switch {
+	case m[3] != "":
+		minutes, _ := strconv.Atoi(m[3])
+		seconds += minutes * 60
+	case m[4] != "":
+		fraction, _ := strconv.ParseFloat("0."+m[4], 64)
+		seconds += int(math.Round(fraction * 3600))
+	}
+	if seconds > 14*3600 {
+		return nil, fmt.Errorf("unrecognized timezone %q", s)
+	}
+	if m[1] == "-" {
+		seconds = -seconds
+	}
+	return time.FixedZone(s, seconds), nil
+}
+
+// ParseSystemTime returns the modem's clock from the systemTime and timezone
+// fields of getSysInfo.asp. systemTime has no zone of its own; it is read in
+// the timezone setting, or in fallback when that is empty.
+func ParseSystemTime(systemTime, timezone string, fallback *time.Location) (time.Time, error) {
+	loc := fallback
+	if strings.TrimSpace(timezone) != "" {
+		var err error
+		if loc, err = ParseTimezone(timezone); err != nil {
+			return time.Time{}, err
+		}
+	}
+	t, err := ParseEventTime(systemTime, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unrecognized system time %q", systemTime)
+	}
+	return t, nil
+}
+
+// ParseRate parses a service flow rate. Plain numbers are bits per second;
+// values with a unit suffix ("10Mbps") are normalized like link speeds.
+func ParseRate(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return v, nil
+	}
+	return ParseLinkSpeed(s)
+}
@@ -0,0 +1,937 @@
+package hitron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseDownstreamInfo(t *testing.T) {
+	channels, err := ParseDownstreamInfo(readFixture(t, "dsinfo.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 2 {
+		t.Fatalf("got %d channels, want 2", len(channels))
+	}
+	if got := channels[0]; got.ChannelID != "17" || got.Modulation != "QAM256" || got.DSoctets != "53 * 2e32 + 4142950845" {
+		t.Errorf("unexpected first channel: %+v", got)
+	}
+}
+
+func TestParseUpstreamInfo(t *testing.T) {
+	channels, err := ParseUpstreamInfo(readFixture(t, "usinfo.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 1 || channels[0].ScdmaMode != "ATDMA" || channels[0].SignalStrength != "44.0" {
+		t.Errorf("unexpected channels: %+v", channels)
+	}
+}
+
+func TestParseOFDMDownstreamInfo(t *testing.T) {
+	channels, err := ParseOFDMDownstreamInfo(readFixture(t, "dsofdminfo.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 2 || channels[0].FFTType != "4K" || channels[1].PLCLock != "NO" {
+		t.Errorf("unexpected channels: %+v", channels)
+	}
+}
+
+func TestParseOFDMUpstreamInfo(t *testing.T) {
+	channels, err := ParseOFDMUpstreamInfo(readFixture(t, "usofdminfo.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 2 || channels[0].State != " OPERATE" || channels[0].RepPower != "40.5" {
+		t.Errorf("unexpected channels: %+v", channels)
+	}
+}
+
+func TestParseSystemInfo(t *testing.T) {
+	info, err := ParseSystemInfo(readFixture(t, "getSysInfo.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.SWVersion != "7.2.4.5.2b3" || info.SerialNumber != "ABC123" {
+		t.Errorf("unexpected system info: %+v", info)
+	}
+
+	if _, err := ParseSystemInfo([]byte("[]")); err == nil {
+		t.Error("expected an error for an empty response")
+	}
+}
+
+func TestParseLinkStatus(t *testing.T) {
+	link, err := ParseLinkStatus(readFixture(t, "getLinkStatus.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link.LinkStatus != "Up" || link.LinkSpeed != "2.5Gbps" {
+		t.Errorf("unexpected link status: %+v", link)
+	}
+
+	if _, err := ParseLinkStatus([]byte("[]")); err == nil {
+		t.Error("expected an error for an empty response")
+	}
+}
+
+func TestParseInvalidJSON(t *testing.T) {
+	if _, err := ParseDownstreamInfo([]byte("<html>")); err == nil {
+		t.Error("ParseDownstreamInfo: expected an error")
+	}
+	if _, err := ParseUpstreamInfo([]byte("{")); err == nil {
+		t.Error("ParseUpstreamInfo: expected an error")
+	}
+}
+
+// TestFirmwareVariants decodes the pages under testdata/firmware, one
+// directory per firmware build, which carry the reference fixtures' data with
+// that build's key names, casing, value types and extra columns.
+func TestFirmwareVariants(t *testing.T) {
+	parsers := map[string]func([]byte) (any, error){
+		"dsinfo.json":        func(b []byte) (any, error) { return ParseDownstreamInfo(b) },
+		"usinfo.json":        func(b []byte) (any, error) { return ParseUpstreamInfo(b) },
+		"dsofdminfo.json":    func(b []byte) (any, error) { return ParseOFDMDownstreamInfo(b) },
+		"usofdminfo.json":    func(b []byte) (any, error) { return ParseOFDMUpstreamInfo(b) },
+		"getSysInfo.json":    func(b []byte) (any, error) { return ParseSystemInfo(b) },
+		"getLinkStatus.json": func(b []byte) (any, error) { return ParseLinkStatus(b) },
+	}
+	pages, err := filepath.Glob(filepath.Join("testdata", "firmware", "*", "*.json"))
+	if err != nil || len(pages) == 0 {
+		t.Fatalf("no firmware fixtures: %v", err)
+	}
+	for _, page := range pages {
+		name := filepath.Base(page)
+		parse, ok := parsers[name]
+		if !ok {
+			t.Errorf("%s: no parser for %s", page, name)
+			continue
+		}
+		want, err := parse(readFixture(t, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := parse(data)
+		if err != nil {
+			t.Errorf("%s: %v", page, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", page, got, want)
+		}
+	}
+}
+
+func TestDecodeRecords(t *testing.T) {
+	// The json tag wins over an alternate, whichever comes first
+	channels, err := ParseDownstreamInfo([]byte(`[{"uncorrectables":"1","uncorrect":"2","snr":null,"channelId":17}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := channels[0]; got.Uncorrect != "2" || got.SNR != "" || got.ChannelID != "17" {
+		t.Errorf("unexpected channel: %+v", got)
+	}
+	if _, err := ParseDownstreamInfo([]byte(`[{"snr":{"value":1}}]`)); err == nil {
+		t.Error("expected an error for a nested value")
+	}
+}
+
+func TestParseComplexOctets(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint64
+		wantErr bool
+	}{
+		{"123456", 123456, false},
+		{"53196813856", 53196813856, false},
+		// High and low words as printed by the QAM downstream table
+		{"53 * 2e32 + 4142950845", 53<<32 + 4142950845, false},
+		{"0 * 2e32 + 4142950845", 4142950845, false},
+		{"1 * 2e32 + 0", 1 << 32, false},
+		{"4294967295 * 2e32 + 4294967295", math.MaxUint64, false},
+		{"53*2e32+4142950845", 53<<32 + 4142950845, false},
+		{"2 * 1000000000 + 7", 2000000007, false},
+		{"4294967296 * 2e32 + 0", 0, true},
+		{"4294967295 * 2e32 + 4294967296", 0, true},
+		{"garbage", 0, true},
+		{"1 + 2 + 3", 0, true},
+		{"-1 * 2e32 + 5", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseComplexOctets(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseComplexOctets(%q) = %d, %v, want %d, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseByteCount(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{"812.4 GBytes", 812.4 * (1 << 30), false},
+		{"61.7 GBytes", 61.7 * (1 << 30), false},
+		{"512 Bytes", 512, false},
+		{"1 Byte", 1, false},
+		{"12.5 KBytes", 12.5 * (1 << 10), false},
+		{"3.2 MBytes", 3.2 * (1 << 20), false},
+		{"1.1 TBytes", 1.1 * (1 << 40), false},
+		{" 4 gbytes ", 4 << 30, false},
+		{"1.5M", 1.5 * (1 << 20), false},
+		{"1.0M Bytes", 1 << 20, false},
+		{"7 GB", 7 << 30, false},
+		{"7 GiB", 7 << 30, false},
+		{"0", 0, false},
+		{"12 packets", 0, true},
+		{"-1 KBytes", 0, true},
+		{"GBytes", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseByteCount(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseByteCount(%q) = %v, %v, want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestModulationBits(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"QAM256", 8, true},
+		{"256QAM", 8, true},
+		{"64QAM", 6, true},
+		{"QPSK", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ModulationBits(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ModulationBits(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestOFDMBitLoading(t *testing.T) {
+	tests := []struct {
+		snr, want float64
+	}{
+		{45, 12},
+		{41, 12},
+		{40.9, 11},
+		{31, 9},
+		{27, 8},
+		{15, 4},
+		{14.9, 0},
+		{0, 0},
+	}
+	for _, tt := range tests {
+		if got := OFDMBitLoading(tt.snr); got != tt.want {
+			t.Errorf("OFDMBitLoading(%v) = %v, want %v", tt.snr, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeModulation(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"QAM256", "QAM256"},
+		{"256QAM", "QAM256"},
+		{"64QAM", "QAM64"},
+		{" qam_64 ", "QAM64"},
+		{"ATDMA 64-QAM", "QAM64"},
+		{"atdma", "ATDMA"},
+		{"sc qam", "SC-QAM"},
+		{"SC-QAM", "SC-QAM"},
+		{"QPSK", "QPSK"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeModulation(tt.in); got != tt.want {
+			t.Errorf("NormalizeModulation(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseSCDMAMode(t *testing.T) {
+	tests := []struct {
+		in           string
+		scdma, valid bool
+	}{
+		{"ATDMA", false, true},
+		{" atdma ", false, true},
+		{"TDMA_AND_ATDMA", false, true},
+		{"S-CDMA", true, true},
+		{"SCDMA", true, true},
+		{"Enabled", true, true},
+		{"0", false, true},
+		{"", false, false},
+		{"unknown", false, false},
+	}
+	for _, tt := range tests {
+		scdma, ok := ParseSCDMAMode(tt.in)
+		if scdma != tt.scdma || ok != tt.valid {
+			t.Errorf("ParseSCDMAMode(%q) = %v, %v; want %v, %v", tt.in, scdma, ok, tt.scdma, tt.valid)
+		}
+	}
+}
+
+func TestParseFFTType(t *testing.T) {
+	tests := []struct {
+		in      string
+		size    int
+		spacing float64
+		ok      bool
+	}{
+		{"4K", 4096, 50e3, true},
+		{" 8k ", 8192, 25e3, true},
+		{"NA", 0, 0, false},
+	}
+	for _, tt := range tests {
+		size, spacing, ok := ParseFFTType(tt.in)
+		if size != tt.size || spacing != tt.spacing || ok != tt.ok {
+			t.Errorf("ParseFFTType(%q) = %d, %v, %v", tt.in, size, spacing, ok)
+		}
+	}
+}
+
+func TestParseLinkSpeed(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{"2500Mbps", 2.5e9, false},
+		{"2.5Gbps", 2.5e9, false},
+		{"1000M", 1e9, false},
+		{"1 Gbps", 1e9, false},
+		{"100", 100e6, false},
+		{"10Kb/s", 10e3, false},
+		{"2.5GBPS", 2.5e9, false},
+		{"100mbps", 100e6, false},
+		{"10 kbps", 10e3, false},
+		{"fast", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLinkSpeed(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLinkSpeed(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1 {
+			t.Errorf("ParseLinkSpeed(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseDuplex(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Full", "full"},
+		{"FULL", "full"},
+		{" Full Duplex ", "full"},
+		{"half-duplex", "half"},
+		{"Auto", "auto"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ParseDuplex(tt.in); got != tt.want {
+			t.Errorf("ParseDuplex(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		in        string
+		want      float64
+		wantErr   bool
+		wantEmpty bool
+	}{
+		{"5.1", 5.1, false, false},
+		{" -3.2 ", -3.2, false, false},
+		{"38,9", 38.9, false, false},
+		{"1234567", 1234567, false, false},
+		{"NA", 0, true, true},
+		{"n/a", 0, true, true},
+		{"----", 0, true, true},
+		{"-", 0, true, true},
+		{"", 0, true, true},
+		{"1,234.5", 0, true, false},
+		{"NaN", 0, true, false},
+		{"high", 0, true, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseNumber(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseNumber(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if errors.Is(err, ErrNoValue) != tt.wantEmpty {
+			t.Errorf("ParseNumber(%q) error = %v, want ErrNoValue %v", tt.in, err, tt.wantEmpty)
+		}
+		if got != tt.want {
+			t.Errorf("ParseNumber(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseFrequency(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		// QAM channels and OFDM subcarrier zero, in Hz
+		{"591000000", 591e6, false},
+		{"  690000000", 690e6, false},
+		// OFDMA channel width, in MHz
+		{"44.0", 44e6, false},
+		{"6.4", 6.4e6, false},
+		{"44,0", 44e6, false},
+		// Firmware builds that include a unit
+		{"591 MHz", 591e6, false},
+		{"591MHz", 591e6, false},
+		{"6400 kHz", 6.4e6, false},
+		{"1.2GHz", 1.2e9, false},
+		{"39000000 Hz", 39e6, false},
+		// Disabled channels
+		{"0", 0, false},
+		{"", 0, true},
+		{"NA", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFrequency(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFrequency(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-3 {
+			t.Errorf("ParseFrequency(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseUptime(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"05 Days,12 Hours,33 Minutes,00 Seconds", 5*24*time.Hour + 12*time.Hour + 33*time.Minute, false},
+		{"1 Day,0 Hours,0 Minutes,7 Seconds", 24*time.Hour + 7*time.Second, false},
+		{"00 Days,00 Hours,02 Minutes,30 Seconds", 2*time.Minute + 30*time.Second, false},
+		{"3 hours 5 min", 3*time.Hour + 5*time.Minute, false},
+		{"", 0, true},
+		{"unknown", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseUptime(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseUptime(%q) = %v, %v, want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseSystemTime(t *testing.T) {
+	want := time.Date(2026, 10, 14, 17, 0, 0, 0, time.UTC)
+	tests := []struct {
+		time, zone string
+		want       time.Time
+		wantErr    bool
+	}{
+		{"Wed Oct 14 10:00:00 2026", "-7", want, false},
+		{"Wed Oct 14 10:00:00 2026", "-07:00", want, false},
+		{"Wed Oct 14 10:00:00 2026", "GMT-7", want, false},
+		{"Wed Oct 14 22:30:00 2026", "+5.5", want, false},
+		{"2026-10-14 17:00:00", "", want, false},
+		{"Wed Oct 14 10:00:00 2026", "Pacific", time.Time{}, true},
+		{"Wed Oct 14 10:00:00 2026", "+20", time.Time{}, true},
+		{"soon", "-7", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSystemTime(tt.time, tt.zone, time.UTC)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSystemTime(%q, %q) error = %v, wantErr %v", tt.time, tt.zone, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseSystemTime(%q, %q) = %v, want %v", tt.time, tt.zone, got, tt.want)
+		}
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"1000000", 1e6},
+		{" 20Mbps ", 20e6},
+	}
+	for _, tt := range tests {
+		got, err := ParseRate(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseRate(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseEventLog(t *testing.T) {
+	entries, err := ParseEventLog(readFixture(t, "getEventLog.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []EventType{EventT3Timeout, EventSyncLoss, EventDynamicRangeViolation, EventT4Timeout}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for i, entry := range entries {
+		if got := ClassifyEvent(entry.Event); got != want[i] {
+			t.Errorf("ClassifyEvent(%q) = %s, want %s", entry.Event, got, want[i])
+		}
+	}
+	if !entries[0].IsCritical() || entries[3].IsCritical() {
+		t.Errorf("unexpected priorities: %+v", entries)
+	}
+}
+
+func TestEventLogEntryIsCritical(t *testing.T) {
+	tests := []struct {
+		priority string
+		want     bool
+	}{
+		{"critical(3)", true},
+		{"1", true},
+		{"Alert", true},
+		{"notice(6)", false},
+		{"4", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := (EventLogEntry{Priority: tt.priority}).IsCritical(); got != tt.want {
+			t.Errorf("IsCritical(%q) = %v, want %v", tt.priority, got, tt.want)
+		}
+	}
+}
+
+func TestParseCMInit(t *testing.T) {
+	info, err := ParseCMInit(readFixture(t, "getCMInit.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := info.Steps()
+	if len(steps) != 9 || steps[0].Name != "hw_init" || steps[7].Status != "Enable,Authorized" {
+		t.Fatalf("unexpected steps: %+v", steps)
+	}
+	for _, step := range steps {
+		if !step.Done {
+			t.Errorf("step %s (%q) not done", step.Name, step.Status)
+		}
+	}
+
+	info.Registration = "Process"
+	info.BPIStatus = "Enable,Unauthorized"
+	info.NetworkAccess = "Denied"
+	for _, step := range info.Steps()[6:] {
+		if step.Done {
+			t.Errorf("step %s (%q) done", step.Name, step.Status)
+		}
+	}
+}
+
+func TestParseDocsisWAN(t *testing.T) {
+	wan, err := ParseDocsisWAN(readFixture(t, "getCmDocsisWan.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wan.IPv4Address != "100.72.10.23" || wan.DelegatedPrefix != "2001:db8:8a00:4100::/56" {
+		t.Errorf("unexpected WAN details: %+v", wan)
+	}
+	if !wan.IPv4Provisioned() || !wan.IPv6Provisioned() || !wan.PrefixDelegated() {
+		t.Errorf("WAN not fully provisioned: %+v", wan)
+	}
+}
+
+func TestDocsisWANLeaseRemaining(t *testing.T) {
+	now := time.Date(2026, 10, 14, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expire  string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"D: 06 H: 13 M: 22 S: 10", 6*24*time.Hour + 13*time.Hour + 22*time.Minute + 10*time.Second, false},
+		{"0 Days,01 Hours,30 Minutes,00 Seconds", 90 * time.Minute, false},
+		{"10/14/2026 09:15:00", 75 * time.Minute, false},
+		{"10/13/2026 09:15:00", 0, false},
+		{"N/A", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := (&DocsisWAN{LeaseExpire: tt.expire}).LeaseRemaining(now, time.UTC)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("LeaseRemaining(%q) = %v, %v, want %v, error %v", tt.expire, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestDocsisWANProvisioned(t *testing.T) {
+	tests := []struct {
+		wan      DocsisWAN
+		v4, v6   bool
+		prefixed bool
+	}{
+		{DocsisWAN{IPv4Address: "0.0.0.0", IPv6Address: "::"}, false, false, false},
+		{DocsisWAN{IPv4Address: "N/A", IPv6Address: "fe80::1/64", DelegatedPrefix: "::/0"}, false, false, false},
+		{DocsisWAN{IPv4Address: " 10.0.0.2 ", IPv6Address: "2001:db8::2", DelegatedPrefix: "--"}, true, true, false},
+		{DocsisWAN{IPv6Address: "10.0.0.2", DelegatedPrefix: "2001:db8:1::/48"}, false, false, true},
+	}
+	for _, tt := range tests {
+		if v4, v6, prefixed := tt.wan.IPv4Provisioned(), tt.wan.IPv6Provisioned(), tt.wan.PrefixDelegated(); v4 != tt.v4 || v6 != tt.v6 || prefixed != tt.prefixed {
+			t.Errorf("%+v: provisioned %v, %v, %v, want %v, %v, %v", tt.wan, v4, v6, prefixed, tt.v4, tt.v6, tt.prefixed)
+		}
+	}
+}
+
+func TestRedactIdentifiers(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`[{"hwVersion":"1A","serialNumber":"ABC123","rfMac":"aa:bb:cc:dd:ee:ff","uptime":12.50}]`,
+			`[{"hwVersion":"1A","rfMac":"","serialNumber":"","uptime":12.50}]`},
+		{`{"getSysInfo.asp":[{"serialNumber":"ABC123"}]}`, `{"getSysInfo.asp":[{"serialNumber":""}]}`},
+		// Firmware builds spell the fields differently
+		{`[{"SerialNumber":"ABC123","rf_mac":"aa:bb:cc:dd:ee:ff"}]`, `[{"SerialNumber":"","rf_mac":""}]`},
+		{`[{"event":"T3 time-out;CM-MAC=aa:bb:cc:dd:ee:ff;CMTS-MAC=00:11:22:33:44:55;"}]`,
+			`[{"event":"T3 time-out;CM-MAC=;CMTS-MAC=00:11:22:33:44:55;"}]`},
+		{`[{"channelId":"1"}]`, `[{"channelId":"1"}]`},
+		{`"serialNumber": not json`, `"serialNumber": not json`},
+	}
+	for _, tt := range tests {
+		if got := string(RedactIdentifiers([]byte(tt.in))); got != tt.want {
+			t.Errorf("RedactIdentifiers(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseEventTime(t *testing.T) {
+	want := time.Date(2026, 10, 12, 8, 14, 2, 0, time.UTC)
+	for _, in := range []string{"10/12/2026 08:14:02", "Mon Oct 12 08:14:02 2026", "2026-10-12 08:14:02"} {
+		got, err := ParseEventTime(in, time.UTC)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseEventTime(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseEventTime("Time Not Established", time.UTC); err == nil {
+		t.Error("expected an error for an unset clock")
+	}
+}
+
+func TestSplitCombined(t *testing.T) {
+	pages, err := SplitCombined([]byte(`{"dsinfo":[{"portId":"1"}],"usinfo.asp":[]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 2 || string(pages["dsinfo.asp"]) != `[{"portId":"1"}]` || string(pages["usinfo.asp"]) != "[]" {
+		t.Errorf("unexpected pages: %q", pages)
+	}
+
+	if _, err := SplitCombined([]byte("[]")); err == nil {
+		t.Error("expected an error for a non-object response")
+	}
+}
+
+func TestPrefetchCombinedDisables(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		status int
+		body   string
+		// requests is how many polls reach the page before it is disabled.
+		requests int
+	}{
+		{"working", http.StatusOK, `{"dsinfo":[]}`, 5},
+		{"not found", http.StatusNotFound, "", 1},
+		{"not JSON", http.StatusOK, "<html></html>", 1},
+		{"no data pages", http.StatusOK, `{"status":"ok"}`, 1},
+		{"server error", http.StatusInternalServerError, "", combinedFailureLimit},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			}))
+			defer srv.Close()
+
+			m := NewModemClient(srv.URL, time.Second, WithCombinedEndpoint("getViewInfo.asp"))
+			for i := 0; i < 5; i++ {
+				m.PrefetchCombined(context.Background())
+			}
+			if requests != tc.requests {
+				t.Errorf("combined page requested %d times, want %d", requests, tc.requests)
+			}
+		})
+	}
+}
+
+func TestAdapterFor(t *testing.T) {
+	a, err := AdapterFor("CODA-4582")
+	if err != nil || a.Model() != "coda4582" {
+		t.Fatalf("AdapterFor(CODA-4582) = %v, %v", a, err)
+	}
+	if _, err := AdapterFor("sb8200"); err == nil {
+		t.Error("expected an error for an unknown model")
+	}
+
+	coda45, _ := AdapterFor("coda45")
+	m := NewModemClient("http://modem", time.Second, WithAdapter(coda45))
+	if got := m.Endpoints(); len(got) != 4 || m.Endpoint(PageOFDMDownstream) != "" {
+		t.Errorf("unexpected coda45 endpoints: %v", got)
+	}
+	if _, err := m.GetOFDMDownstreamInfo(context.Background()); !errors.Is(err, ErrPageUnsupported) {
+		t.Errorf("GetOFDMDownstreamInfo on coda45: got %v, want ErrPageUnsupported", err)
+	}
+	if got := NewModemClient("http://modem", time.Second).Endpoints(); len(got) != len(Endpoints) {
+		t.Errorf("default endpoints = %v, want %v", got, Endpoints)
+	}
+}
+
+// pageSource serves fixed payloads and answers 404 for anything else.
+type pageSource map[string]string
+
+func (s pageSource) Fetch(endpoint string) ([]byte, error) {
+	if data, ok := s[endpoint]; ok {
+		return []byte(data), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrEndpointNotFound, endpoint)
+}
+
+func TestDetectModel(t *testing.T) {
+	sysInfo := string(readFixture(t, "getSysInfo.json"))
+	tests := []struct {
+		name  string
+		pages pageSource
+		want  string
+	}{
+		{"reported", pageSource{EndpointSystemInfo: `[{"modelName":"CODA-4582U","swVersion":"7.1.1"}]`}, "coda4582"},
+		{"unknown model with OFDM", pageSource{EndpointSystemInfo: `[{"model":"XYZ"}]`, EndpointOFDMDownstream: "[]"}, "coda56"},
+		{"OFDM pages", pageSource{EndpointSystemInfo: sysInfo, EndpointOFDMDownstream: "[]"}, "coda56"},
+		{"no OFDM pages", pageSource{EndpointSystemInfo: sysInfo}, "coda45"},
+		{"no system info", pageSource{}, "coda56"},
+	}
+	for _, tt := range tests {
+		m := NewModemClient("http://modem", time.Second, WithDataSource(tt.pages), WithAutoDetect())
+		m.DetectModel(context.Background())
+		if got := m.Adapter().Model(); got != tt.want {
+			t.Errorf("%s: detected %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUnexpectedResponses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/data/" + EndpointDownstream:
+			fmt.Fprint(w, "\n<!DOCTYPE html><html><form action=\"/goform/login\"></form></html>")
+		case "/data/" + EndpointUpstream:
+			fmt.Fprint(w, "Service Unavailable")
+		default:
+			fmt.Fprint(w, "[]")
+		}
+	}))
+	defer srv.Close()
+
+	m := NewModemClient(srv.URL, time.Second)
+	if _, err := m.GetDownstreamInfo(context.Background()); !errors.Is(err, ErrLoginRequired) {
+		t.Errorf("login page: got %v, want ErrLoginRequired", err)
+	}
+	if _, err := m.GetUpstreamInfo(context.Background()); !errors.Is(err, ErrNotJSON) {
+		t.Errorf("text page: got %v, want ErrNotJSON", err)
+	}
+	if _, err := m.GetOFDMUpstreamInfo(context.Background()); err != nil {
+		t.Errorf("JSON page: %v", err)
+	}
+}
+
+func TestNormalizeBaseURL(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"https://192.168.100.1", "https://192.168.100.1", false},
+		{"https://192.168.100.1/", "https://192.168.100.1", false},
+		{"192.168.100.1", "https://192.168.100.1", false},
+		{"modem.lan:8443", "https://modem.lan:8443", false},
+		{"http://modem.lan", "http://modem.lan", false},
+		{"fd00::1", "https://[fd00::1]", false},
+		{"[fd00::1]:8443", "https://[fd00::1]:8443", false},
+		{"fe80::1%eth0", "https://[fe80::1%25eth0]", false},
+		{"https://[fe80::1%eth0]:443", "https://[fe80::1%25eth0]:443", false},
+		{"https://[fe80::1%25eth0]", "https://[fe80::1%25eth0]", false},
+		{"", "", true},
+		{"ftp://modem.lan", "", true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeBaseURL(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("NormalizeBaseURL(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestResolvedAddress(t *testing.T) {
+	var host string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		fmt.Fprint(w, "[]")
+	}))
+	defer srv.Close()
+
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	m := NewModemClient("http://modem.invalid:"+port, time.Second, WithResolvedAddress("127.0.0.1"))
+	if _, err := m.GetUpstreamInfo(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := "modem.invalid:" + port; host != want {
+		t.Errorf("Host = %q, want %q", host, want)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(readFixture(t, "usinfo.json"))
+	}))
+	defer srv.Close()
+
+	m := NewModemClient(srv.URL, time.Second)
+	if _, _, ok := m.Latest(EndpointUpstream); ok {
+		t.Fatal("Latest succeeded before any request")
+	}
+	if _, err := m.GetUpstreamInfo(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	fail = true
+	if _, err := m.GetUpstreamInfo(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+	body, at, ok := m.Latest(EndpointUpstream)
+	if !ok || string(body) != string(readFixture(t, "usinfo.json")) || at.IsZero() {
+		t.Errorf("Latest = %d bytes at %v, %v; want the last successful response", len(body), at, ok)
+	}
+}
+
+func TestRequestObserver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "[]")
+	}))
+	defer srv.Close()
+
+	var observed []string
+	m := NewModemClient(srv.URL, time.Second, WithRequestObserver(func(endpoint string, d time.Duration, code int, err error) {
+		if d <= 0 || code != http.StatusOK || err != nil {
+			t.Errorf("observed %s after %v with %d, %v", endpoint, d, code, err)
+		}
+		observed = append(observed, endpoint)
+	}))
+	if _, err := m.GetUpstreamInfo(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{EndpointUpstream}; !reflect.DeepEqual(observed, want) {
+		t.Errorf("observed %v, want %v", observed, want)
+	}
+
+	rtt, err := m.ProbeRTT(context.Background(), RTTProbeTCP)
+	if err != nil || rtt <= 0 {
+		t.Errorf("ProbeRTT(tcp) = %v, %v", rtt, err)
+	}
+	if _, err := m.ProbeRTT(context.Background(), "udp"); err == nil {
+		t.Error("ProbeRTT with an unknown probe succeeded")
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	busy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+	}))
+	defer busy.Close()
+	secure := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "[]")
+	}))
+	defer secure.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	for _, tc := range []struct {
+		url  string
+		want string
+	}{
+		{busy.URL, ErrorHTTPStatus},
+		{secure.URL, ErrorTLS},
+		{closed.URL, ErrorConnectionRefused},
+	} {
+		_, err := NewModemClient(tc.url, time.Second).GetUpstreamInfo(context.Background())
+		if got := ClassifyError(err); got != tc.want {
+			t.Errorf("ClassifyError(%v) = %q, want %q", err, got, tc.want)
+		}
+	}
+	// The test server's certificate is self-signed, like the modem's.
+	if _, err := NewModemClient(secure.URL, time.Second).GetUpstreamInfo(context.Background()); !errors.Is(err, ErrSelfSigned) {
+		t.Errorf("GetUpstreamInfo with a self-signed certificate = %v, want ErrSelfSigned", err)
+	}
+
+	for _, tc := range []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{fmt.Errorf("failed to get x: %w", context.DeadlineExceeded), ErrorTimeout},
+		{&net.DNSError{Err: "no such host", Name: "modem.invalid", IsNotFound: true}, ErrorDNS},
+		{fmt.Errorf("%w: dsinfo.asp", ErrEndpointNotFound), ErrorHTTPStatus},
+		{fmt.Errorf("%w: dsinfo.asp", ErrLoginRequired), ErrorLoginRequired},
+		{fmt.Errorf("%w: dsinfo.asp returned \"<html>\"", ErrNotJSON), ErrorInvalidResponse},
+		{fmt.Errorf("%w: got 00", ErrCertificateMismatch), ErrorTLS},
+		{errors.New("connection reset by peer"), ErrorOther},
+	} {
+		if got := ClassifyError(tc.err); got != tc.want {
+			t.Errorf("ClassifyError(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package hitron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// rebootPath is the form the web interface's restart button posts to.
+const rebootPath = "/goform/Reboot"
+
+// Reboot restarts the modem through its web interface, which requires the
+// credentials given with WithCredentials. The modem drops off the network
+// for a few minutes afterwards, so polls fail until it is back.
+func (m *ModemClient) Reboot(ctx context.Context) error {
+	if m.source != nil {
+		return errors.New("can't reboot a modem read from a data source")
+	}
+	if m.username == "" {
+		return fmt.Errorf("%w: rebooting needs modem credentials", ErrLoginRequired)
+	}
+
+	slog.Warn("Rebooting modem", "modem", m.baseURL)
+	err := m.postReboot(ctx)
+	if errors.Is(err, ErrLoginRequired) {
+		if err := m.login(ctx); err != nil {
+			return err
+		}
+		err = m.postReboot(ctx)
+	}
+	return err
+}
+
+func (m *ModemClient) postReboot(ctx context.Context) error {
+	form := url.Values{"model": {`{"reboot":"1"}`}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.Address()+rebootPath, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create reboot request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reboot modem: %w", certificateError(err))
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read reboot response: %w", err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden || isLoginRedirect(resp) || isLoginPage(body) {
+		return fmt.Errorf("%w: reboot", ErrLoginRequired)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("modem rejected reboot (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package hitron
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"slices"
+)
+
+// identifierFields are the page fields that identify the modem itself
+// rather than describe its state, as normalized by normalizeKey, so that
+// spellings such as SerialNumber and rf_mac are redacted too.
+var identifierFields = []string{"serialnumber", "rfmac"}
+
+// identifierHints are contained in every spelling of an identifier field
+// and in the event log's CM-MAC, for a quick check before decoding a page.
+var identifierHints = [][]byte{[]byte("serial"), []byte("mac")}
+
+// cmMACRe matches the modem's MAC address in event log messages, such as
+// "CM-MAC=aa:bb:cc:dd:ee:ff;".
+var cmMACRe = regexp.MustCompile(`(CM-MAC=)[0-9A-Fa-f:.-]+`)
+
+// WithRedactedIdentifiers makes the client blank the modem's serial number
+// and RF MAC address in every page it returns, and in the bodies handed to
+// the response hook, so they don't reach metrics, APIs or recordings.
+func WithRedactedIdentifiers() Option {
+	return func(m *ModemClient) { m.redact = true }
+}
+
+// RedactIdentifiers returns data with the value of every serialNumber and
+// rfMac field, however it is spelled, replaced by an empty string, wherever
+// it appears, and the
+// CM-MAC address removed from event log messages. A hash would
+// keep series apart, but serial numbers and MAC addresses are few enough to
+// look up from one. Data that isn't JSON is returned unchanged.
+func RedactIdentifiers(data []byte) []byte {
+	lower := bytes.ToLower(data)
+	found := slices.ContainsFunc(identifierHints, func(hint []byte) bool { return bytes.Contains(lower, hint) })
+	if !found || !json.Valid(data) {
+		return data
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// Numbers keep their exact text
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return data
+	}
+	redacted, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return data
+	}
+	return redacted
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if slices.Contains(identifierFields, normalizeKey(key)) {
+				if _, ok := value.(string); ok {
+					v[key] = ""
+				}
+				continue
+			}
+			v[key] = redactValue(value)
+		}
+	case []any:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	case string:
+		return cmMACRe.ReplaceAllString(v, "$1")
+	}
+	return v
+}
+
+// redacted returns body with identifiers blanked if the client redacts them.
+func (m *ModemClient) redacted(body []byte) []byte {
+	if !m.redact {
+		return body
+	}
+	return RedactIdentifiers(body)
+}
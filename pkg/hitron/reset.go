@@ -0,0 +1,53 @@
+package hitron
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync"
+)
+
+// sessionJar is a cookie jar that can be emptied while requests use it.
+type sessionJar struct {
+	mu  sync.Mutex
+	jar *cookiejar.Jar
+}
+
+func newSessionJar() *sessionJar {
+	// An error is only possible with custom options.
+	jar, _ := cookiejar.New(nil)
+	return &sessionJar{jar: jar}
+}
+
+func (j *sessionJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jar.SetCookies(u, cookies)
+}
+
+func (j *sessionJar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.jar.Cookies(u)
+}
+
+// reset forgets every cookie.
+func (j *sessionJar) reset() {
+	jar, _ := cookiejar.New(nil)
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jar = jar
+}
+
+// ResetConnections makes the client start over with the modem: idle
+// connections are closed, the session cookies are forgotten and requests go
+// to the address the client was created with again. The next request dials,
+// completes a full TLS handshake and, with WithCredentials, logs in anew.
+// Some firmware wedges a TLS session until the client reconnects from
+// scratch. Requests in flight are left to finish or time out.
+func (m *ModemClient) ResetConnections() {
+	m.client.CloseIdleConnections()
+	m.jar.reset()
+	m.current.Store(0)
+	m.fallbackState.switched(true)
+}
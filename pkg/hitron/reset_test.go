@@ -0,0 +1,50 @@
+package hitron
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResetConnections(t *testing.T) {
+	var conns atomic.Int32
+	var cookies []string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("session"); err == nil {
+			cookies = append(cookies, c.Value)
+		} else {
+			cookies = append(cookies, "")
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+		w.Write(readFixture(t, "usinfo.json"))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	m := NewModemClient(srv.URL, time.Second)
+	for i := 0; i < 2; i++ {
+		if _, err := m.GetUpstreamInfo(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m.ResetConnections()
+	if _, err := m.GetUpstreamInfo(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := conns.Load(); got != 2 {
+		t.Errorf("%d connections, want one before and one after the reset", got)
+	}
+	if want := []string{"", "abc", ""}; !reflect.DeepEqual(cookies, want) {
+		t.Errorf("session cookies sent = %q, want %q", cookies, want)
+	}
+}
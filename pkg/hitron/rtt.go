@@ -0,0 +1,138 @@
+package hitron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// Round-trip probes for ProbeRTT.
+const (
+	// RTTProbeTCP times connecting to the web interface's port.
+	RTTProbeTCP = "tcp"
+	// RTTProbeICMP times an ICMP echo. It uses unprivileged ping sockets,
+	// which Linux only allows to the groups in net.ipv4.ping_group_range,
+	// and falls back to a raw socket, which needs CAP_NET_RAW.
+	RTTProbeICMP = "icmp"
+)
+
+// ProbeRTT measures the round trip to the modem below HTTP with the given
+// probe, so that a slow network can be told apart from a slow web server.
+// With WithResolvedAddress the probe goes to that address. Proxies are not
+// used, so behind one the result is of little use. DNS lookups are not
+// counted.
+func (m *ModemClient) ProbeRTT(ctx context.Context, probe string) (time.Duration, error) {
+	u, err := url.Parse(m.Address())
+	if err != nil {
+		return 0, fmt.Errorf("invalid modem URL: %w", err)
+	}
+	host := u.Hostname()
+	if m.resolved != "" {
+		host = m.resolved
+	}
+	if _, ok := ctx.Deadline(); !ok && m.client.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.client.Timeout)
+		defer cancel()
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	addr := addrs[0]
+
+	switch probe {
+	case RTTProbeTCP:
+		port := u.Port()
+		if port == "" {
+			port = "443"
+			if u.Scheme == "http" {
+				port = "80"
+			}
+		}
+		var d net.Dialer
+		start := time.Now()
+		conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(addr.String(), port))
+		if err != nil {
+			return 0, fmt.Errorf("failed to connect to the modem: %w", err)
+		}
+		rtt := time.Since(start)
+		conn.Close()
+		return rtt, nil
+	case RTTProbeICMP:
+		return pingICMP(ctx, addr)
+	default:
+		return 0, fmt.Errorf("unknown RTT probe %q, expected %s or %s", probe, RTTProbeTCP, RTTProbeICMP)
+	}
+}
+
+// pingICMP sends one ICMP echo request to addr and waits for the reply.
+func pingICMP(ctx context.Context, addr net.IPAddr) (time.Duration, error) {
+	networks := [2]string{"udp4", "ip4:icmp"}
+	listen, proto := "0.0.0.0", 1
+	var request, reply icmp.Type = ipv4.ICMPTypeEcho, ipv4.ICMPTypeEchoReply
+	if addr.IP.To4() == nil {
+		networks = [2]string{"udp6", "ip6:ipv6-icmp"}
+		listen, proto = "::", 58
+		request, reply = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply
+	}
+	c, err := icmp.ListenPacket(networks[0], listen)
+	privileged := false
+	if err != nil {
+		var rawErr error
+		if c, rawErr = icmp.ListenPacket(networks[1], listen); rawErr != nil {
+			return 0, fmt.Errorf("failed to open an ICMP socket: %w", errors.Join(err, rawErr))
+		}
+		privileged = true
+	}
+	defer c.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		c.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { c.SetDeadline(time.Now()) })
+	defer stop()
+
+	// Unprivileged sockets get their ID from the kernel, so replies are
+	// matched on the sequence number.
+	id, seq := os.Getpid()&0xffff, rand.Intn(1<<16)
+	msg := icmp.Message{Type: request, Body: &icmp.Echo{ID: id, Seq: seq, Data: []byte("coda56-exporter")}}
+	b, err := msg.Marshal(nil)
+	if err != nil {
+		return 0, err
+	}
+	var dst net.Addr = &net.UDPAddr{IP: addr.IP, Zone: addr.Zone}
+	if privileged {
+		dst = &addr
+	}
+
+	start := time.Now()
+	if _, err := c.WriteTo(b, dst); err != nil {
+		return 0, fmt.Errorf("failed to send ICMP echo: %w", err)
+	}
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := c.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			return 0, fmt.Errorf("no ICMP echo reply: %w", err)
+		}
+		rm, err := icmp.ParseMessage(proto, buf[:n])
+		if err != nil || rm.Type != reply {
+			continue
+		}
+		if echo, ok := rm.Body.(*icmp.Echo); ok && echo.Seq == seq && (!privileged || echo.ID == id) {
+			return time.Since(start), nil
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package hitron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// UpstreamServiceFlow is one row of the modem's upstream service flow table.
+// Only some firmware builds expose this page.
+type UpstreamServiceFlow struct {
+	SFID           string `json:"sfid"`
+	SID            string `json:"sid"`
+	ScheduleType   string `json:"scheduleType"`
+	MaxTrafficRate string `json:"maxTrafficRate"`
+	GrantedRate    string `json:"grantedRate"`
+	T3Timeouts     string `json:"t3Timeouts"`
+}
+
+// DownstreamServiceFlow is one row of the modem's downstream QoS/service
+// flow table, including the modem's own per-flow accounting.
+type DownstreamServiceFlow struct {
+	SFID            string `json:"sfid"`
+	MaxTrafficRate  string `json:"maxTrafficRate"`
+	MaxTrafficBurst string `json:"maxTrafficBurst"`
+	MinReservedRate string `json:"minReservedRate"`
+	Packets         string `json:"packets"`
+	Octets          string `json:"octets"`
+}
+
+// ParseUpstreamServiceFlows decodes an upstream service flow page.
+func ParseUpstreamServiceFlows(data []byte) ([]UpstreamServiceFlow, error) {
+	flows, err := decodeRecords[UpstreamServiceFlow](data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse upstream service flow JSON: %w", err)
+	}
+	slog.Debug("Parsed upstream service flows", "flows", len(flows))
+	return flows, nil
+}
+
+// GetUpstreamServiceFlows fetches and parses the upstream service flow page.
+// The page name differs between firmware builds, so it is passed in.
+func (m *ModemClient) GetUpstreamServiceFlows(ctx context.Context, endpoint string) ([]UpstreamServiceFlow, error) {
+	data, err := m.Get(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpstreamServiceFlows(data)
+}
+
+// ParseDownstreamServiceFlows decodes a downstream service flow page.
+func ParseDownstreamServiceFlows(data []byte) ([]DownstreamServiceFlow, error) {
+	flows, err := decodeRecords[DownstreamServiceFlow](data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse downstream service flow JSON: %w", err)
+	}
+	slog.Debug("Parsed downstream service flows", "flows", len(flows))
+	return flows, nil
+}
+
+// GetDownstreamServiceFlows fetches and parses the downstream service flow
+// page.
+func (m *ModemClient) GetDownstreamServiceFlows(ctx context.Context, endpoint string) ([]DownstreamServiceFlow, error) {
+	data, err := m.Get(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDownstreamServiceFlows(data)
+}
@@ -0,0 +1,62 @@
+package hitron
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Snapshot is a point-in-time copy of everything the modem reports.
+type Snapshot struct {
+	Name           string               `json:"name,omitempty"`
+	Time           time.Time            `json:"time"`
+	Downstream     []DownstreamInfo     `json:"downstream"`
+	Upstream       []UpstreamInfo       `json:"upstream"`
+	OFDMDownstream []OFDMDownstreamInfo `json:"ofdmDownstream"`
+	OFDMUpstream   []OFDMUpstreamInfo   `json:"ofdmUpstream"`
+	System         *SystemInfo          `json:"system,omitempty"`
+	Link           *LinkStatus          `json:"link,omitempty"`
+	Errors         map[string]string    `json:"errors,omitempty"`
+}
+
+// CaptureSnapshot fetches every endpoint the modem's model serves. Endpoints that fail
+// are recorded in Errors; an error is only returned if nothing could be fetched.
+func (m *ModemClient) CaptureSnapshot(ctx context.Context, name string) (*Snapshot, error) {
+	s := &Snapshot{Name: name, Time: time.Now(), Errors: map[string]string{}}
+	m.DetectModel(ctx)
+	m.PrefetchCombined(ctx)
+	a := m.Adapter()
+
+	var err error
+	for _, p := range Pages {
+		endpoint := a.Endpoint(p)
+		if endpoint == "" {
+			continue
+		}
+		switch p {
+		case PageDownstream:
+			s.Downstream, err = m.GetDownstreamInfo(ctx)
+		case PageUpstream:
+			s.Upstream, err = m.GetUpstreamInfo(ctx)
+		case PageOFDMDownstream:
+			s.OFDMDownstream, err = m.GetOFDMDownstreamInfo(ctx)
+		case PageOFDMUpstream:
+			s.OFDMUpstream, err = m.GetOFDMUpstreamInfo(ctx)
+		case PageSystemInfo:
+			s.System, err = m.GetSystemInfo(ctx)
+		case PageLinkStatus:
+			s.Link, err = m.GetLinkStatus(ctx)
+		}
+		if err != nil {
+			s.Errors[endpoint] = err.Error()
+		}
+	}
+
+	if len(s.Errors) == len(adapterEndpoints(a)) {
+		return nil, fmt.Errorf("failed to fetch any modem endpoint")
+	}
+	if len(s.Errors) == 0 {
+		s.Errors = nil
+	}
+	return s, nil
+}
@@ -0,0 +1,285 @@
+package hitron
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DOCSIS MIB objects read by SNMPSource.
+var (
+	// DOCS-IF-MIB (RFC 4546)
+	oidDownstreamChannelEntry = mustOID("1.3.6.1.2.1.10.127.1.1.1.1")
+	oidUpstreamChannelEntry   = mustOID("1.3.6.1.2.1.10.127.1.1.2.1")
+	oidSignalQualityEntry     = mustOID("1.3.6.1.2.1.10.127.1.1.4.1")
+	// DOCS-IF3-MIB docsIf3CmStatusUsEntry, for per-channel transmit power
+	oidCmStatusUsEntry = mustOID("1.3.6.1.4.1.4491.2.1.20.1.2.1")
+
+	oidIfHCInOctets     = mustOID("1.3.6.1.2.1.31.1.1.1.6")
+	oidSysDescr         = mustOID("1.3.6.1.2.1.1.1.0")
+	oidSysUpTime        = mustOID("1.3.6.1.2.1.1.3.0")
+	oidDevSerialNumber  = mustOID("1.3.6.1.2.1.69.1.1.4.0")
+	oidDevSwCurrentVers = mustOID("1.3.6.1.2.1.69.1.3.5.0")
+)
+
+// Columns of the DOCS-IF-MIB tables.
+const (
+	colDownChannelID         = 1
+	colDownChannelFrequency  = 2
+	colDownChannelModulation = 4
+	colDownChannelPower      = 6
+
+	colUpChannelID        = 1
+	colUpChannelFrequency = 2
+	colUpChannelWidth     = 3
+	colUpChannelType      = 15
+
+	colSigQCorrecteds        = 3
+	colSigQUncorrectables    = 4
+	colSigQSignalNoise       = 5
+	colSigQExtCorrecteds     = 9
+	colSigQExtUncorrectables = 10
+
+	colCmStatusUsTxPower = 1
+)
+
+// snmpPages are the pages SNMPSource can build.
+var snmpPages = map[Page]string{
+	PageDownstream: EndpointDownstream,
+	PageUpstream:   EndpointUpstream,
+	PageSystemInfo: EndpointSystemInfo,
+}
+
+// SNMPAdapter polls only the pages SNMPSource builds. Use it with an
+// SNMPSource as the client's data source; as a fallback source the model's
+// own adapter applies.
+var SNMPAdapter ModemAdapter = &WebAdapter{Name: "snmp", Endpoints: snmpPages}
+
+// SNMPSource is a DataSource that reads the modem's DOCSIS SNMP agent
+// instead of its web interface. It builds the downstream, upstream and
+// system info pages from the DOCS-IF-MIB and DOCS-IF3-MIB tables, in the
+// same JSON shape the web interface uses, so the rest of the pipeline and
+// the metric names are unchanged. Other pages are reported as not found.
+type SNMPSource struct {
+	// Address is the agent's host, with an optional port (default 161).
+	Address   string
+	Community string
+	// Timeout bounds each SNMP request.
+	Timeout time.Duration
+}
+
+// Fetch implements DataSource.
+func (s *SNMPSource) Fetch(endpoint string) ([]byte, error) {
+	var build func(*snmpSession) (interface{}, error)
+	switch endpoint {
+	case EndpointDownstream:
+		build = snmpDownstream
+	case EndpointUpstream:
+		build = snmpUpstream
+	case EndpointSystemInfo:
+		build = snmpSystemInfo
+	default:
+		return nil, fmt.Errorf("%w: %s over SNMP", ErrEndpointNotFound, endpoint)
+	}
+
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	sess, err := dialSNMP(s.Address, s.Community, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer sess.Close()
+
+	page, err := build(sess)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s over SNMP: %w", endpoint, err)
+	}
+	return json.Marshal(page)
+}
+
+// rowIndexes returns the indexes of a table's rows in ascending order.
+func rowIndexes(t snmpTable) []uint32 {
+	indexes := make([]uint32, 0, len(t))
+	for i := range t {
+		indexes = append(indexes, i)
+	}
+	sort.Slice(indexes, func(a, b int) bool { return indexes[a] < indexes[b] })
+	return indexes
+}
+
+// tenths formats a value in tenths, such as TenthdBmV, as a decimal string.
+func tenths(v snmpValue) string {
+	return strconv.FormatFloat(float64(v.int())/10, 'f', 1, 64)
+}
+
+func snmpDownstream(sess *snmpSession) (interface{}, error) {
+	channels, err := sess.table(oidDownstreamChannelEntry)
+	if err != nil {
+		return nil, err
+	}
+	quality, err := sess.table(oidSignalQualityEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := rowIndexes(channels)
+	var octetOIDs []oid
+	for _, i := range indexes {
+		octetOIDs = append(octetOIDs, oidIfHCInOctets.child(i))
+	}
+	octets := map[uint32]snmpValue{}
+	if len(octetOIDs) > 0 {
+		binds, err := sess.get(octetOIDs...)
+		if err != nil {
+			return nil, err
+		}
+		for _, vb := range binds {
+			octets[vb.name[len(vb.name)-1]] = vb.value
+		}
+	}
+
+	result := []DownstreamInfo{}
+	for n, i := range indexes {
+		row, q := channels[i], quality[i]
+		info := DownstreamInfo{
+			PortID:         strconv.Itoa(n + 1),
+			ChannelID:      strconv.FormatInt(row[colDownChannelID].int(), 10),
+			Frequency:      strconv.FormatInt(row[colDownChannelFrequency].int(), 10),
+			Modulation:     downstreamModulation(row[colDownChannelModulation].int()),
+			SignalStrength: tenths(row[colDownChannelPower]),
+			SNR:            tenths(q[colSigQSignalNoise]),
+			Correcteds:     counter(q, colSigQExtCorrecteds, colSigQCorrecteds),
+			Uncorrect:      counter(q, colSigQExtUncorrectables, colSigQUncorrectables),
+		}
+		if v, ok := octets[i]; ok && v.exists() {
+			info.DSoctets = strconv.FormatUint(v.u, 10)
+		}
+		result = append(result, info)
+	}
+	return result, nil
+}
+
+// counter returns the 64-bit column of a row if the agent has it, and the
+// 32-bit one otherwise.
+func counter(row map[uint32]snmpValue, ext, legacy uint32) string {
+	if v, ok := row[ext]; ok && v.exists() {
+		return strconv.FormatUint(v.u, 10)
+	}
+	return strconv.FormatUint(row[legacy].u, 10)
+}
+
+// downstreamModulation names a docsIfDownChannelModulation value.
+func downstreamModulation(v int64) string {
+	switch v {
+	case 3:
+		return "QAM64"
+	case 4:
+		return "QAM256"
+	}
+	return "Unknown"
+}
+
+// upstreamType names a docsIfUpChannelType value the way usinfo.asp does.
+func upstreamType(v int64) string {
+	switch v {
+	case 1:
+		return "TDMA"
+	case 2:
+		return "ATDMA"
+	case 3:
+		return "SCDMA"
+	case 4:
+		return "TDMA_ATDMA"
+	}
+	return "Unknown"
+}
+
+func snmpUpstream(sess *snmpSession) (interface{}, error) {
+	channels, err := sess.table(oidUpstreamChannelEntry)
+	if err != nil {
+		return nil, err
+	}
+	status, err := sess.table(oidCmStatusUsEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []UpstreamInfo{}
+	for n, i := range rowIndexes(channels) {
+		row := channels[i]
+		info := UpstreamInfo{
+			PortID:    strconv.Itoa(n + 1),
+			ChannelID: strconv.FormatInt(row[colUpChannelID].int(), 10),
+			Frequency: strconv.FormatInt(row[colUpChannelFrequency].int(), 10),
+			Bandwidth: strconv.FormatInt(row[colUpChannelWidth].int(), 10),
+			ScdmaMode: upstreamType(row[colUpChannelType].int()),
+		}
+		if v, ok := status[i][colCmStatusUsTxPower]; ok && v.exists() {
+			info.SignalStrength = tenths(v)
+		}
+		result = append(result, info)
+	}
+	return result, nil
+}
+
+var sysDescrFieldRe = regexp.MustCompile(`([A-Z_]+):\s*([^;>]*)`)
+
+// parseSysDescr splits the DOCSIS sysDescr format,
+// "<<HW_REV: 1A; VENDOR: Hitron; SW_REV: 7.2.4; MODEL: CODA56>>".
+func parseSysDescr(s string) map[string]string {
+	fields := map[string]string{}
+	for _, m := range sysDescrFieldRe.FindAllStringSubmatch(s, -1) {
+		fields[m[1]] = strings.TrimSpace(m[2])
+	}
+	return fields
+}
+
+// formatUptime formats a duration the way getSysInfo.asp reports uptime.
+func formatUptime(d time.Duration) string {
+	s := int64(d / time.Second)
+	return fmt.Sprintf("%d Days,%d Hours,%d Minutes,%d Seconds", s/86400, s/3600%24, s/60%60, s%60)
+}
+
+func snmpSystemInfo(sess *snmpSession) (interface{}, error) {
+	binds, err := sess.get(oidSysDescr, oidSysUpTime, oidDevSerialNumber, oidDevSwCurrentVers)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]snmpValue{}
+	for _, vb := range binds {
+		if vb.value.exists() {
+			values[vb.name.String()] = vb.value
+		}
+	}
+	descr, ok := values[oidSysDescr.String()]
+	if !ok {
+		return nil, fmt.Errorf("agent has no sysDescr")
+	}
+
+	fields := parseSysDescr(string(descr.b))
+	info := struct {
+		SystemInfo
+		// ModelName lets DetectModel pick the adapter.
+		ModelName string `json:"modelName,omitempty"`
+	}{
+		SystemInfo: SystemInfo{HWVersion: fields["HW_REV"], SWVersion: fields["SW_REV"]},
+		ModelName:  fields["MODEL"],
+	}
+	if v, ok := values[oidDevSwCurrentVers.String()]; ok && len(v.b) > 0 {
+		info.SWVersion = string(v.b)
+	}
+	if v, ok := values[oidDevSerialNumber.String()]; ok {
+		info.SerialNumber = string(v.b)
+	}
+	if v, ok := values[oidSysUpTime.String()]; ok {
+		// TimeTicks are hundredths of a second
+		info.SystemUptime = formatUptime(time.Duration(v.u) * 10 * time.Millisecond)
+	}
+	return []interface{}{info}, nil
+}
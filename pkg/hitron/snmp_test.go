@@ -0,0 +1,208 @@
+package hitron
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeAgent answers SNMP GetRequest and GetBulkRequest PDUs from a fixed
+// set of values.
+type fakeAgent map[string]snmpValue
+
+func (a fakeAgent) lookup(name oid, next bool) (oid, snmpValue) {
+	if !next {
+		if v, ok := a[name.String()]; ok {
+			return name, v
+		}
+		return name, snmpValue{tag: berNoSuchInstance}
+	}
+	var best oid
+	for k := range a {
+		o := mustOID(k)
+		if compareOID(o, name) > 0 && (best == nil || compareOID(o, best) < 0) {
+			best = o
+		}
+	}
+	if best == nil {
+		return name, snmpValue{tag: berEndOfMibView}
+	}
+	return best, a[best.String()]
+}
+
+func encodeValue(v snmpValue) []byte {
+	switch v.tag {
+	case berInteger:
+		return berInt(v.n)
+	case berCounter32, berGauge32, berTimeTicks, berCounter64:
+		var b []byte
+		for n := v.u; n > 0; n >>= 8 {
+			b = append([]byte{byte(n)}, b...)
+		}
+		return berTLV(v.tag, append([]byte{0}, b...))
+	}
+	return berTLV(v.tag, v.b)
+}
+
+func (a fakeAgent) serve(conn net.PacketConn) {
+	serveSNMP(conn, a.lookup)
+}
+
+// serveSNMP answers requests with the values lookup returns for each name,
+// or for each name after it in a GetBulkRequest.
+func serveSNMP(conn net.PacketConn, lookup func(name oid, next bool) (oid, snmpValue)) {
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		msg, _, _ := berExpect(buf[:n], berSequence)
+		_, msg, _ = berExpect(msg, berInteger)
+		community, msg, _ := berExpect(msg, berOctetString)
+		tag, pdu, _, _ := berRead(msg)
+		id, pdu, _ := berExpect(pdu, berInteger)
+		_, pdu, _ = berExpect(pdu, berInteger)
+		reps, pdu, _ := berExpect(pdu, berInteger)
+		list, _, _ := berExpect(pdu, berSequence)
+
+		var binds []byte
+		for len(list) > 0 {
+			var bind []byte
+			bind, list, _ = berExpect(list, berSequence)
+			name, _, _ := berExpect(bind, berOID)
+			o := berDecodeOID(name)
+			count := 1
+			if tag == pduGetBulk {
+				count = int(berDecodeInt(reps))
+			}
+			for i := 0; i < count; i++ {
+				var v snmpValue
+				o, v = lookup(o, tag == pduGetBulk)
+				binds = append(binds, berTLV(berSequence, berEncodeOID(o), encodeValue(v))...)
+				if v.tag == berEndOfMibView {
+					break
+				}
+			}
+		}
+		resp := berTLV(berSequence, berInt(snmpVersion2c), berTLV(berOctetString, community),
+			berTLV(pduResponse, berInt(berDecodeInt(id)), berInt(0), berInt(0), berTLV(berSequence, binds)))
+		conn.WriteTo(resp, addr)
+	}
+}
+
+func TestSNMPSource(t *testing.T) {
+	agent := fakeAgent{
+		// Two downstream channels, ifIndex 3 and 4
+		"1.3.6.1.2.1.10.127.1.1.1.1.1.3": {tag: berInteger, n: 17},
+		"1.3.6.1.2.1.10.127.1.1.1.1.1.4": {tag: berInteger, n: 18},
+		"1.3.6.1.2.1.10.127.1.1.1.1.2.3": {tag: berInteger, n: 591000000},
+		"1.3.6.1.2.1.10.127.1.1.1.1.2.4": {tag: berInteger, n: 597000000},
+		"1.3.6.1.2.1.10.127.1.1.1.1.4.3": {tag: berInteger, n: 4},
+		"1.3.6.1.2.1.10.127.1.1.1.1.4.4": {tag: berInteger, n: 3},
+		"1.3.6.1.2.1.10.127.1.1.1.1.6.3": {tag: berInteger, n: 32},
+		"1.3.6.1.2.1.10.127.1.1.1.1.6.4": {tag: berInteger, n: -15},
+		"1.3.6.1.2.1.10.127.1.1.4.1.3.3": {tag: berCounter32, u: 12},
+		"1.3.6.1.2.1.10.127.1.1.4.1.4.3": {tag: berCounter32, u: 3},
+		"1.3.6.1.2.1.10.127.1.1.4.1.5.3": {tag: berInteger, n: 401},
+		"1.3.6.1.2.1.10.127.1.1.4.1.9.3": {tag: berCounter64, u: 1 << 40},
+		"1.3.6.1.2.1.31.1.1.1.6.3":       {tag: berCounter64, u: 53<<32 + 4142950845},
+		// One upstream channel, ifIndex 80
+		"1.3.6.1.2.1.10.127.1.1.2.1.1.80":    {tag: berInteger, n: 3},
+		"1.3.6.1.2.1.10.127.1.1.2.1.2.80":    {tag: berInteger, n: 35600000},
+		"1.3.6.1.2.1.10.127.1.1.2.1.3.80":    {tag: berInteger, n: 6400000},
+		"1.3.6.1.2.1.10.127.1.1.2.1.15.80":   {tag: berInteger, n: 2},
+		"1.3.6.1.4.1.4491.2.1.20.1.2.1.1.80": {tag: berInteger, n: 440},
+		// System
+		"1.3.6.1.2.1.1.1.0":      {tag: berOctetString, b: []byte("<<HW_REV: 1A; VENDOR: Hitron Technologies; SW_REV: 7.2.4.5.2b3; MODEL: CODA56>>")},
+		"1.3.6.1.2.1.1.3.0":      {tag: berTimeTicks, u: 47718000},
+		"1.3.6.1.2.1.69.1.1.4.0": {tag: berOctetString, b: []byte("ABC123")},
+	}
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	go agent.serve(conn)
+
+	src := &SNMPSource{Address: conn.LocalAddr().String(), Community: "public", Timeout: time.Second}
+	m := NewModemClient("http://modem", time.Second, WithDataSource(src), WithAdapter(SNMPAdapter))
+	ctx := context.Background()
+
+	ds, err := m.GetDownstreamInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DownstreamInfo{
+		{PortID: "1", ChannelID: "17", Frequency: "591000000", Modulation: "QAM256", SignalStrength: "3.2", SNR: "40.1",
+			DSoctets: "231776217533", Correcteds: "1099511627776", Uncorrect: "3"},
+		{PortID: "2", ChannelID: "18", Frequency: "597000000", Modulation: "QAM64", SignalStrength: "-1.5", SNR: "0.0",
+			Correcteds: "0", Uncorrect: "0"},
+	}
+	if len(ds) != len(want) || ds[0] != want[0] || ds[1] != want[1] {
+		t.Errorf("downstream = %+v, want %+v", ds, want)
+	}
+
+	us, err := m.GetUpstreamInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(us) != 1 || us[0].ChannelID != "3" || us[0].SignalStrength != "44.0" || us[0].ScdmaMode != "ATDMA" || us[0].Bandwidth != "6400000" {
+		t.Errorf("unexpected upstream: %+v", us)
+	}
+
+	sys, err := m.GetSystemInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sys.SWVersion != "7.2.4.5.2b3" || sys.HWVersion != "1A" || sys.SerialNumber != "ABC123" ||
+		sys.SystemUptime != "5 Days,12 Hours,33 Minutes,0 Seconds" {
+		t.Errorf("unexpected system info: %+v", sys)
+	}
+
+	if _, err := src.Fetch(EndpointLinkStatus); !errors.Is(err, ErrEndpointNotFound) {
+		t.Errorf("link status over SNMP: got %v, want ErrEndpointNotFound", err)
+	}
+}
+
+func TestSNMPWalkMisbehavingAgent(t *testing.T) {
+	root := mustOID("1.3.6.1.2.1.10.127.1.1.1")
+	for _, tc := range []struct {
+		name   string
+		lookup func(name oid, next bool) (oid, snmpValue)
+	}{
+		// Answering every GetBulkRequest with the same OID
+		{"repeating", func(name oid, next bool) (oid, snmpValue) {
+			return append(root[:len(root):len(root)], 1), snmpValue{tag: berInteger, n: 1}
+		}},
+		// Counting up forever
+		{"endless", func(name oid, next bool) (oid, snmpValue) {
+			o := append(oid{}, name...)
+			if len(o) == len(root) {
+				o = append(o, 0)
+			}
+			o[len(o)-1]++
+			return o, snmpValue{tag: berInteger, n: 1}
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			go serveSNMP(conn, tc.lookup)
+
+			sess, err := dialSNMP(conn.LocalAddr().String(), "public", time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sess.Close()
+			if binds, err := sess.walk(root); err == nil {
+				t.Errorf("walk returned %d values and no error", len(binds))
+			}
+		})
+	}
+}
@@ -0,0 +1,422 @@
+package hitron
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A minimal SNMPv2c client: just enough BER to send GetRequest and
+// GetBulkRequest PDUs and read the responses of a cable modem's agent.
+
+// BER tags used by SNMP.
+const (
+	berInteger     = 0x02
+	berOctetString = 0x04
+	berNull        = 0x05
+	berOID         = 0x06
+	berSequence    = 0x30
+	berCounter32   = 0x41
+	berGauge32     = 0x42
+	berTimeTicks   = 0x43
+	berCounter64   = 0x46
+
+	berNoSuchObject   = 0x80
+	berNoSuchInstance = 0x81
+	berEndOfMibView   = 0x82
+
+	pduGetRequest  = 0xa0
+	pduResponse    = 0xa2
+	pduGetBulk     = 0xa5
+	snmpVersion2c  = 1
+	maxRepetitions = 25
+	// maxWalkVarbinds bounds a walk, far above the few hundred values of
+	// the largest table read, in case an agent never ends it.
+	maxWalkVarbinds = 10000
+)
+
+type oid []uint32
+
+func mustOID(s string) oid {
+	var o oid
+	for _, part := range strings.Split(strings.TrimPrefix(s, "."), ".") {
+		n, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			panic(fmt.Sprintf("invalid OID %q", s))
+		}
+		o = append(o, uint32(n))
+	}
+	return o
+}
+
+func (o oid) String() string {
+	parts := make([]string, len(o))
+	for i, n := range o {
+		parts[i] = strconv.FormatUint(uint64(n), 10)
+	}
+	return strings.Join(parts, ".")
+}
+
+// child returns o extended with more sub-identifiers.
+func (o oid) child(ids ...uint32) oid {
+	return append(append(oid{}, o...), ids...)
+}
+
+func (o oid) hasPrefix(prefix oid) bool {
+	if len(o) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if o[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// compareOID orders OIDs lexicographically, as agents walk them: negative
+// if x comes before y, positive if after and 0 if they are equal.
+func compareOID(x, y oid) int {
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if x[i] != y[i] {
+			if x[i] < y[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(x) - len(y)
+}
+
+// snmpValue is a decoded variable binding value. Integers are kept in n,
+// the unsigned application types in u and strings in b.
+type snmpValue struct {
+	tag byte
+	n   int64
+	u   uint64
+	b   []byte
+}
+
+// exists reports whether the agent returned a value rather than one of the
+// noSuch* or endOfMibView exceptions.
+func (v snmpValue) exists() bool {
+	return v.tag < berNoSuchObject
+}
+
+// int returns the value as a signed number, whatever its type.
+func (v snmpValue) int() int64 {
+	if v.tag == berInteger {
+		return v.n
+	}
+	return int64(v.u)
+}
+
+type varbind struct {
+	name  oid
+	value snmpValue
+}
+
+func berLength(n int) []byte {
+	if n < 0x80 {
+		return []byte{byte(n)}
+	}
+	var b []byte
+	for ; n > 0; n >>= 8 {
+		b = append([]byte{byte(n)}, b...)
+	}
+	return append([]byte{0x80 | byte(len(b))}, b...)
+}
+
+func berTLV(tag byte, content ...[]byte) []byte {
+	var body []byte
+	for _, c := range content {
+		body = append(body, c...)
+	}
+	return append(append([]byte{tag}, berLength(len(body))...), body...)
+}
+
+func berInt(v int64) []byte {
+	b := []byte{byte(v)}
+	for (v > 0x7f || v < -0x80) && len(b) < 8 {
+		v >>= 8
+		b = append([]byte{byte(v)}, b...)
+	}
+	return berTLV(berInteger, b)
+}
+
+func berEncodeOID(o oid) []byte {
+	if len(o) < 2 {
+		return berTLV(berOID)
+	}
+	body := []byte{byte(o[0]*40 + o[1])}
+	for _, n := range o[2:] {
+		var sub []byte
+		sub = append(sub, byte(n&0x7f))
+		for n >>= 7; n > 0; n >>= 7 {
+			sub = append([]byte{byte(n&0x7f) | 0x80}, sub...)
+		}
+		body = append(body, sub...)
+	}
+	return berTLV(berOID, body)
+}
+
+var errBERTruncated = errors.New("truncated SNMP message")
+
+// berRead splits the first TLV off b.
+func berRead(b []byte) (tag byte, content, rest []byte, err error) {
+	if len(b) < 2 {
+		return 0, nil, nil, errBERTruncated
+	}
+	tag, n, b := b[0], int(b[1]), b[2:]
+	if n&0x80 != 0 {
+		size := n & 0x7f
+		if size == 0 || size > 4 || len(b) < size {
+			return 0, nil, nil, errBERTruncated
+		}
+		n = 0
+		for _, c := range b[:size] {
+			n = n<<8 | int(c)
+		}
+		b = b[size:]
+	}
+	if n > len(b) {
+		return 0, nil, nil, errBERTruncated
+	}
+	return tag, b[:n], b[n:], nil
+}
+
+// berExpect reads a TLV and checks its tag.
+func berExpect(b []byte, want byte) (content, rest []byte, err error) {
+	tag, content, rest, err := berRead(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tag != want {
+		return nil, nil, fmt.Errorf("unexpected BER tag 0x%02x, want 0x%02x", tag, want)
+	}
+	return content, rest, nil
+}
+
+func berDecodeInt(b []byte) int64 {
+	var v int64
+	for i, c := range b {
+		if i == 0 && c&0x80 != 0 {
+			v = -1
+		}
+		v = v<<8 | int64(c)
+	}
+	return v
+}
+
+func berDecodeUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+func berDecodeOID(b []byte) oid {
+	if len(b) == 0 {
+		return nil
+	}
+	o := oid{uint32(b[0]) / 40, uint32(b[0]) % 40}
+	var n uint32
+	for _, c := range b[1:] {
+		n = n<<7 | uint32(c&0x7f)
+		if c&0x80 == 0 {
+			o = append(o, n)
+			n = 0
+		}
+	}
+	return o
+}
+
+func berDecodeValue(tag byte, content []byte) snmpValue {
+	v := snmpValue{tag: tag}
+	switch tag {
+	case berInteger:
+		v.n = berDecodeInt(content)
+	case berCounter32, berGauge32, berTimeTicks, berCounter64:
+		v.u = berDecodeUint(content)
+	default:
+		v.b = content
+	}
+	return v
+}
+
+// encodeRequest builds an SNMPv2c message. For GetBulkRequest the two
+// fields after the request ID are non-repeaters and max-repetitions rather
+// than error status and index.
+func encodeRequest(community string, pdu byte, id int32, a, b int64, names []oid) []byte {
+	var binds []byte
+	for _, name := range names {
+		binds = append(binds, berTLV(berSequence, berEncodeOID(name), berTLV(berNull))...)
+	}
+	return berTLV(berSequence,
+		berInt(snmpVersion2c),
+		berTLV(berOctetString, []byte(community)),
+		berTLV(pdu, berInt(int64(id)), berInt(a), berInt(b), berTLV(berSequence, binds)),
+	)
+}
+
+// decodeResponse parses a Response PDU, returning its request ID and
+// variable bindings.
+func decodeResponse(msg []byte) (int32, []varbind, error) {
+	body, _, err := berExpect(msg, berSequence)
+	if err != nil {
+		return 0, nil, err
+	}
+	if _, body, err = berExpect(body, berInteger); err != nil {
+		return 0, nil, err
+	}
+	if _, body, err = berExpect(body, berOctetString); err != nil {
+		return 0, nil, err
+	}
+	pdu, _, err := berExpect(body, pduResponse)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var fields [3]int64
+	for i := range fields {
+		var content []byte
+		if content, pdu, err = berExpect(pdu, berInteger); err != nil {
+			return 0, nil, err
+		}
+		fields[i] = berDecodeInt(content)
+	}
+	if fields[1] != 0 {
+		return int32(fields[0]), nil, fmt.Errorf("SNMP error status %d at index %d", fields[1], fields[2])
+	}
+
+	list, _, err := berExpect(pdu, berSequence)
+	if err != nil {
+		return 0, nil, err
+	}
+	var binds []varbind
+	for len(list) > 0 {
+		var bind []byte
+		if bind, list, err = berExpect(list, berSequence); err != nil {
+			return 0, nil, err
+		}
+		name, rest, err := berExpect(bind, berOID)
+		if err != nil {
+			return 0, nil, err
+		}
+		tag, content, _, err := berRead(rest)
+		if err != nil {
+			return 0, nil, err
+		}
+		binds = append(binds, varbind{name: berDecodeOID(name), value: berDecodeValue(tag, content)})
+	}
+	return int32(fields[0]), binds, nil
+}
+
+// snmpSession is one UDP association with an agent.
+type snmpSession struct {
+	conn      net.Conn
+	community string
+	timeout   time.Duration
+}
+
+func dialSNMP(address, community string, timeout time.Duration) (*snmpSession, error) {
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "161")
+	}
+	conn, err := net.DialTimeout("udp", address, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SNMP agent %s: %w", address, err)
+	}
+	return &snmpSession{conn: conn, community: community, timeout: timeout}, nil
+}
+
+func (s *snmpSession) Close() error {
+	return s.conn.Close()
+}
+
+// roundTrip sends a request and waits for the response with the same ID,
+// discarding stray datagrams.
+func (s *snmpSession) roundTrip(pdu byte, a, b int64, names []oid) ([]varbind, error) {
+	id := rand.Int31()
+	if _, err := s.conn.Write(encodeRequest(s.community, pdu, id, a, b, names)); err != nil {
+		return nil, fmt.Errorf("failed to send SNMP request: %w", err)
+	}
+	s.conn.SetReadDeadline(time.Now().Add(s.timeout))
+	buf := make([]byte, 65535)
+	for {
+		n, err := s.conn.Read(buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SNMP response: %w", err)
+		}
+		got, binds, err := decodeResponse(buf[:n])
+		if got != id {
+			// A late answer to an earlier request, or garbage
+			continue
+		}
+		return binds, err
+	}
+}
+
+// get fetches single values.
+func (s *snmpSession) get(names ...oid) ([]varbind, error) {
+	return s.roundTrip(pduGetRequest, 0, 0, names)
+}
+
+// walk returns every value under root, using GetBulkRequest. An agent that
+// answers with OIDs out of order, which would walk in circles, or with more
+// than maxWalkVarbinds values is an error.
+func (s *snmpSession) walk(root oid) ([]varbind, error) {
+	var all []varbind
+	next := root
+	for {
+		binds, err := s.roundTrip(pduGetBulk, 0, maxRepetitions, []oid{next})
+		if err != nil {
+			return nil, err
+		}
+		if len(binds) == 0 {
+			return all, nil
+		}
+		for _, vb := range binds {
+			if vb.value.tag == berEndOfMibView || !vb.name.hasPrefix(root) {
+				return all, nil
+			}
+			if compareOID(vb.name, next) <= 0 {
+				return nil, fmt.Errorf("SNMP agent returned %s after %s while walking %s", vb.name, next, root)
+			}
+			if len(all) == maxWalkVarbinds {
+				return nil, fmt.Errorf("SNMP walk of %s returned more than %d values", root, maxWalkVarbinds)
+			}
+			all = append(all, vb)
+			next = vb.name
+		}
+	}
+}
+
+// snmpTable is a MIB table indexed by a single sub-identifier, such as the
+// ifIndex of a channel: rows[index][column].
+type snmpTable map[uint32]map[uint32]snmpValue
+
+// table walks a table entry OID, such as docsIfDownstreamChannelEntry.
+func (s *snmpSession) table(entry oid) (snmpTable, error) {
+	binds, err := s.walk(entry)
+	if err != nil {
+		return nil, err
+	}
+	t := snmpTable{}
+	for _, vb := range binds {
+		if len(vb.name) != len(entry)+2 {
+			continue
+		}
+		column, index := vb.name[len(entry)], vb.name[len(entry)+1]
+		if t[index] == nil {
+			t[index] = map[uint32]snmpValue{}
+		}
+		t[index][column] = vb.value
+	}
+	return t, nil
+}
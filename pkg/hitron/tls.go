@@ -0,0 +1,90 @@
+package hitron
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrCertificateMismatch is returned when the modem's certificate doesn't
+// match the fingerprint pinned in TLSOptions.
+var ErrCertificateMismatch = errors.New("modem certificate does not match the pinned fingerprint")
+
+// ErrSelfSigned is returned when the modem's certificate is verified and
+// turns out to be self-signed, as the stock certificate is. Pinning its
+// fingerprint with TLSOptions trusts it.
+var ErrSelfSigned = errors.New("modem certificate is self-signed")
+
+// TLSOptions selects how the modem's certificate is verified. The modem
+// ships with a self-signed certificate, so the zero value verifies against
+// the system roots, which only works with a certificate the user installed.
+type TLSOptions struct {
+	// CAFile is a PEM bundle used instead of the system roots.
+	CAFile string
+	// Fingerprint pins the SHA-256 fingerprint of the modem's certificate,
+	// as hex with or without colons. Chain and name checks are skipped, so
+	// a self-signed certificate can be trusted without a CA.
+	Fingerprint string
+	// Insecure accepts any certificate.
+	Insecure bool
+}
+
+// certificateError marks a failed request with ErrSelfSigned when the
+// modem's certificate failed verification for being self-signed.
+func certificateError(err error) error {
+	var authErr x509.UnknownAuthorityError
+	if errors.As(err, &authErr) && authErr.Cert != nil && bytes.Equal(authErr.Cert.RawIssuer, authErr.Cert.RawSubject) {
+		return fmt.Errorf("%w: %w", ErrSelfSigned, err)
+	}
+	return err
+}
+
+// Config returns the tls.Config for o.
+func (o TLSOptions) Config() (*tls.Config, error) {
+	if o.Insecure && (o.CAFile != "" || o.Fingerprint != "") {
+		return nil, errors.New("insecure mode can't be combined with a CA file or fingerprint")
+	}
+	if o.Insecure {
+		return &tls.Config{InsecureSkipVerify: true}, nil
+	}
+
+	cfg := &tls.Config{}
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", o.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if o.Fingerprint != "" {
+		want, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(o.Fingerprint), ":", ""))
+		if err != nil || len(want) != sha256.Size {
+			return nil, fmt.Errorf("invalid SHA-256 fingerprint %q", o.Fingerprint)
+		}
+		// The pin replaces the usual verification, which a self-signed
+		// certificate would never pass.
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return fmt.Errorf("%w: modem presented no certificate", ErrCertificateMismatch)
+			}
+			got := sha256.Sum256(rawCerts[0])
+			if !strings.EqualFold(hex.EncodeToString(got[:]), hex.EncodeToString(want)) {
+				return fmt.Errorf("%w: got %x", ErrCertificateMismatch, got)
+			}
+			return nil
+		}
+	}
+	return cfg, nil
+}
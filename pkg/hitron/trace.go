@@ -0,0 +1,40 @@
+package hitron
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/anupcshan/coda56-exporter/pkg/hitron"
+
+// WithTracerProvider makes the client record its modem requests as spans
+// with tp. Without it the global provider is used, which records nothing
+// unless the program installs one with otel.SetTracerProvider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(m *ModemClient) { m.tracerProvider = tp }
+}
+
+func (m *ModemClient) tracer() trace.Tracer {
+	if m.tracerProvider != nil {
+		return m.tracerProvider.Tracer(tracerName)
+	}
+	return otel.Tracer(tracerName)
+}
+
+// startSpan starts a client span for a request to the modem.
+func (m *ModemClient) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return m.tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endSpan records err, if any, on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
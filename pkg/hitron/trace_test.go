@@ -0,0 +1,63 @@
+package hitron
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests++; requests == 1 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "[]")
+	}))
+	defer srv.Close()
+
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	m := NewModemClient(srv.URL, time.Second, WithTracerProvider(tp), WithRetryPolicy(RetryPolicy{Retries: 1}))
+	if _, err := m.GetUpstreamInfo(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+	attrs := func(s sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+		m := map[attribute.Key]attribute.Value{}
+		for _, kv := range s.Attributes() {
+			m[kv.Key] = kv.Value
+		}
+		return m
+	}
+	fetch := spans[2]
+	if want := "fetch " + EndpointUpstream; fetch.Name() != want {
+		t.Errorf("span name %q, want %q", fetch.Name(), want)
+	}
+	if got := attrs(fetch)["hitron.retries"].AsInt64(); got != 1 {
+		t.Errorf("hitron.retries = %d, want 1", got)
+	}
+	for i, want := range []int64{http.StatusServiceUnavailable, http.StatusOK} {
+		if spans[i].Parent().SpanID() != fetch.SpanContext().SpanID() {
+			t.Errorf("request %d is not a child of the fetch", i)
+		}
+		if got := attrs(spans[i])["http.response.status_code"].AsInt64(); got != want {
+			t.Errorf("request %d status %d, want %d", i, got, want)
+		}
+	}
+	if got := attrs(spans[1])["http.response.body.size"].AsInt64(); got != 2 {
+		t.Errorf("body size %d, want 2", got)
+	}
+}
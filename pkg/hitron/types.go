@@ -0,0 +1,85 @@
+package hitron
+
+// The modem reports every value as a string, often with units attached, so
+// the structs keep the raw strings. The Parse* helpers in this package
+// convert the common formats.
+//
+// The json tags give the CODA56's key names, which are also used when the
+// structs are encoded. The alt tags list names that other firmware builds use
+// for the same column; see decodeRecords.
+
+// DownstreamInfo is one downstream SC-QAM channel from dsinfo.asp.
+type DownstreamInfo struct {
+	PortID         string `json:"portId"`
+	Frequency      string `json:"frequency"`
+	Modulation     string `json:"modulation"`
+	SignalStrength string `json:"signalStrength" alt:"power,powerLevel"`
+	SNR            string `json:"snr"`
+	DSoctets       string `json:"dsoctets" alt:"octets"`
+	Correcteds     string `json:"correcteds" alt:"corrected,correctables"`
+	Uncorrect      string `json:"uncorrect" alt:"uncorrected,uncorrectables"`
+	ChannelID      string `json:"channelId" alt:"chId"`
+}
+
+// UpstreamInfo is one upstream SC-QAM channel from usinfo.asp.
+type UpstreamInfo struct {
+	PortID         string `json:"portId"`
+	Frequency      string `json:"frequency"`
+	Bandwidth      string `json:"bandwidth" alt:"symbolRate"`
+	ModType        string `json:"modtype" alt:"modulation"`
+	ScdmaMode      string `json:"scdmaMode"`
+	SignalStrength string `json:"signalStrength" alt:"power,powerLevel"`
+	ChannelID      string `json:"channelId" alt:"chId"`
+}
+
+// SystemInfo is the modem's identity and uptime from getSysInfo.asp.
+type SystemInfo struct {
+	HWVersion    string `json:"hwVersion" alt:"hardwareVersion"`
+	SWVersion    string `json:"swVersion" alt:"softwareVersion,firmwareVersion"`
+	SerialNumber string `json:"serialNumber"`
+	RFMac        string `json:"rfMac"`
+	WanIP        string `json:"wanIp"`
+	SystemUptime string `json:"systemUptime" alt:"uptime"`
+	SystemTime   string `json:"systemTime"`
+	Timezone     string `json:"timezone"`
+	WRecPkt      string `json:"WRecPkt"`
+	WSendPkt     string `json:"WSendPkt"`
+	LanIP        string `json:"lanIp"`
+	LRecPkt      string `json:"LRecPkt"`
+	LSendPkt     string `json:"LSendPkt"`
+}
+
+// OFDMDownstreamInfo is one downstream OFDM channel from dsofdminfo.asp.
+type OFDMDownstreamInfo struct {
+	Receive          string `json:"receive"`
+	FFTType          string `json:"ffttype"`
+	Subcarr0freqFreq string `json:"Subcarr0freqFreq" alt:"subcarr0freq"`
+	PLCLock          string `json:"plclock"`
+	NCPLock          string `json:"ncplock"`
+	MDC1Lock         string `json:"mdc1lock"`
+	PLCPower         string `json:"plcpower" alt:"power"`
+	SNR              string `json:"SNR"`
+	DSoctets         string `json:"dsoctets" alt:"octets"`
+	Correcteds       string `json:"correcteds" alt:"corrected,correctables"`
+	Uncorrect        string `json:"uncorrect" alt:"uncorrected,uncorrectables"`
+}
+
+// OFDMUpstreamInfo is one upstream OFDMA channel from usofdminfo.asp.
+type OFDMUpstreamInfo struct {
+	USCHIndex   string `json:"uschindex"`
+	State       string `json:"state"`
+	Frequency   string `json:"frequency"`
+	DigAtten    string `json:"digAtten"`
+	DigAttenBo  string `json:"digAttenBo"`
+	ChannelBw   string `json:"channelBw"`
+	RepPower    string `json:"repPower" alt:"power"`
+	RepPower1_6 string `json:"repPower1_6"`
+	FFTVal      string `json:"fftVal"`
+}
+
+// LinkStatus is the Ethernet link state from getLinkStatus.asp.
+type LinkStatus struct {
+	LinkStatus string `json:"LinkStatus"`
+	LinkDuplex string `json:"LinkDuplex"`
+	LinkSpeed  string `json:"LinkSpeed"`
+}
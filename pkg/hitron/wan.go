@@ -0,0 +1,128 @@
+package hitron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DocsisWAN is the modem's DOCSIS WAN page, with the addresses the ISP
+// provisioned over DHCPv4 and DHCPv6.
+type DocsisWAN struct {
+	IPv4Address     string `json:"CmIpAddress"`
+	IPv4Netmask     string `json:"CmNetMask"`
+	IPv4Gateway     string `json:"CmGateway"`
+	IPv6Address     string `json:"CmIpv6Address"`
+	IPv6Gateway     string `json:"CmIpv6Gateway"`
+	DelegatedPrefix string `json:"CmIpv6DelegatedPrefix"`
+	// LeaseDuration is the length of the DHCPv4 lease, such as
+	// "D: 07 H: 00 M: 00 S: 00".
+	LeaseDuration string `json:"CmIpLeaseDuration"`
+	// LeaseExpire is the time left on it in the same format, or on some
+	// firmware the time it expires, such as "10/15/2026 08:14:02".
+	LeaseExpire string `json:"CmIpLeaseExpire"`
+}
+
+var (
+	leasePartRe = regexp.MustCompile(`(?i)\b([dhms])\s*:\s*([0-9]+)`)
+	leaseUnits  = map[string]time.Duration{"d": 24 * time.Hour, "h": time.Hour, "m": time.Minute, "s": time.Second}
+)
+
+// ParseLeaseTime converts a DHCP lease time, such as
+// "D: 06 H: 13 M: 22 S: 10" or "6 Days,13 Hours,22 Minutes,10 Seconds", into
+// a duration.
+func ParseLeaseTime(s string) (time.Duration, error) {
+	parts := leasePartRe.FindAllStringSubmatch(s, -1)
+	if parts == nil {
+		d, err := ParseUptime(s)
+		if err != nil {
+			return 0, fmt.Errorf("unrecognized lease time %q", s)
+		}
+		return d, nil
+	}
+	var d time.Duration
+	for _, p := range parts {
+		n, err := strconv.ParseInt(p[2], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unrecognized lease time %q: %w", s, err)
+		}
+		d += time.Duration(n) * leaseUnits[strings.ToLower(p[1])]
+	}
+	return d, nil
+}
+
+// LeaseRemaining returns the time left on the DHCPv4 lease at now, the time
+// the page was fetched, reading an expiry time in loc. A lease that has
+// expired has 0 left.
+func (w *DocsisWAN) LeaseRemaining(now time.Time, loc *time.Location) (time.Duration, error) {
+	if d, err := ParseLeaseTime(w.LeaseExpire); err == nil {
+		return d, nil
+	}
+	expire, err := ParseEventTime(w.LeaseExpire, loc)
+	if err != nil {
+		return 0, fmt.Errorf("unrecognized lease expiry %q", w.LeaseExpire)
+	}
+	return max(expire.Sub(now), 0), nil
+}
+
+// IPv4Provisioned reports whether the modem has an IPv4 address.
+func (w *DocsisWAN) IPv4Provisioned() bool {
+	return usableAddr(w.IPv4Address)
+}
+
+// IPv6Provisioned reports whether the modem has a global IPv6 address.
+func (w *DocsisWAN) IPv6Provisioned() bool {
+	if !usableAddr(w.IPv6Address) {
+		return false
+	}
+	addr, _ := parseAddr(w.IPv6Address)
+	return addr.Is6() && !addr.IsLinkLocalUnicast()
+}
+
+// PrefixDelegated reports whether the ISP delegated an IPv6 prefix.
+func (w *DocsisWAN) PrefixDelegated() bool {
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(w.DelegatedPrefix))
+	return err == nil && prefix.Addr().Is6() && !prefix.Addr().IsUnspecified()
+}
+
+// parseAddr parses an address as the modem shows it, possibly with a
+// prefix length.
+func parseAddr(s string) (netip.Addr, error) {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "/")
+	return netip.ParseAddr(s)
+}
+
+// usableAddr reports whether s is an address rather than a placeholder such
+// as "0.0.0.0", "::" or "N/A".
+func usableAddr(s string) bool {
+	addr, err := parseAddr(s)
+	return err == nil && !addr.IsUnspecified()
+}
+
+// ParseDocsisWAN decodes a DOCSIS WAN page.
+func ParseDocsisWAN(data []byte) (*DocsisWAN, error) {
+	records, err := decodeRecords[DocsisWAN](data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DOCSIS WAN JSON: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty DOCSIS WAN response")
+	}
+	slog.Debug("Parsed DOCSIS WAN")
+	return &records[0], nil
+}
+
+// GetDocsisWAN fetches and parses the DOCSIS WAN page. Not every firmware
+// serves it, so the page name is passed in.
+func (m *ModemClient) GetDocsisWAN(ctx context.Context, endpoint string) (*DocsisWAN, error) {
+	data, err := m.Get(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDocsisWAN(data)
+}
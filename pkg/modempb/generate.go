@@ -0,0 +1,5 @@
+// Package modempb holds the protobuf messages and gRPC service the exporter
+// serves with -grpc.listen-addr.
+package modempb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative modem.proto
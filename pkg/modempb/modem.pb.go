@@ -0,0 +1,1426 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        (unknown)
+// source: modem.proto
+
+// The modem data the exporter polls, for services that would rather
+// subscribe to it than scrape the metrics. The messages mirror the JSON of
+// /api/v1/status, with the strings the modem reports, and add the values
+// parsed from them as the exporter's metrics have them. A parsed value is
+// unset when the modem didn't report it or it didn't parse.
+
+package modempb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StatusRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// modem is the name of a modem in the config file. Empty selects the
+	// only modem, or the first one.
+	Modem         string `protobuf:"bytes,1,opt,name=modem,proto3" json:"modem,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusRequest) Reset() {
+	*x = StatusRequest{}
+	mi := &file_modem_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusRequest) ProtoMessage() {}
+
+func (x *StatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_modem_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusRequest.ProtoReflect.Descriptor instead.
+func (*StatusRequest) Descriptor() ([]byte, []int) {
+	return file_modem_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StatusRequest) GetModem() string {
+	if x != nil {
+		return x.Modem
+	}
+	return ""
+}
+
+// Status is everything the modem reported in one poll. A page that failed
+// keeps its data from the previous poll, and is listed in errors.
+type Status struct {
+	state          protoimpl.MessageState   `protogen:"open.v1"`
+	Modem          string                   `protobuf:"bytes,1,opt,name=modem,proto3" json:"modem,omitempty"`
+	Time           *timestamppb.Timestamp   `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Downstream     []*DownstreamChannel     `protobuf:"bytes,3,rep,name=downstream,proto3" json:"downstream,omitempty"`
+	Upstream       []*UpstreamChannel       `protobuf:"bytes,4,rep,name=upstream,proto3" json:"upstream,omitempty"`
+	OfdmDownstream []*OFDMDownstreamChannel `protobuf:"bytes,5,rep,name=ofdm_downstream,json=ofdmDownstream,proto3" json:"ofdm_downstream,omitempty"`
+	OfdmUpstream   []*OFDMUpstreamChannel   `protobuf:"bytes,6,rep,name=ofdm_upstream,json=ofdmUpstream,proto3" json:"ofdm_upstream,omitempty"`
+	System         *SystemInfo              `protobuf:"bytes,7,opt,name=system,proto3" json:"system,omitempty"`
+	Link           *LinkStatus              `protobuf:"bytes,8,opt,name=link,proto3" json:"link,omitempty"`
+	// errors holds the error of each page that failed, by endpoint.
+	Errors        map[string]string `protobuf:"bytes,9,rep,name=errors,proto3" json:"errors,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Status) Reset() {
+	*x = Status{}
+	mi := &file_modem_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Status) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Status) ProtoMessage() {}
+
+func (x *Status) ProtoReflect() protoreflect.Message {
+	mi := &file_modem_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Status.ProtoReflect.Descriptor instead.
+func (*Status) Descriptor() ([]byte, []int) {
+	return file_modem_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Status) GetModem() string {
+	if x != nil {
+		return x.Modem
+	}
+	return ""
+}
+
+func (x *Status) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Status) GetDownstream() []*DownstreamChannel {
+	if x != nil {
+		return x.Downstream
+	}
+	return nil
+}
+
+func (x *Status) GetUpstream() []*UpstreamChannel {
+	if x != nil {
+		return x.Upstream
+	}
+	return nil
+}
+
+func (x *Status) GetOfdmDownstream() []*OFDMDownstreamChannel {
+	if x != nil {
+		return x.OfdmDownstream
+	}
+	return nil
+}
+
+func (x *Status) GetOfdmUpstream() []*OFDMUpstreamChannel {
+	if x != nil {
+		return x.OfdmUpstream
+	}
+	return nil
+}
+
+func (x *Status) GetSystem() *SystemInfo {
+	if x != nil {
+		return x.System
+	}
+	return nil
+}
+
+func (x *Status) GetLink() *LinkStatus {
+	if x != nil {
+		return x.Link
+	}
+	return nil
+}
+
+func (x *Status) GetErrors() map[string]string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+// DownstreamChannel is one downstream SC-QAM channel from dsinfo.asp.
+type DownstreamChannel struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	PortId              string                 `protobuf:"bytes,1,opt,name=port_id,json=portId,proto3" json:"port_id,omitempty"`
+	Frequency           string                 `protobuf:"bytes,2,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	Modulation          string                 `protobuf:"bytes,3,opt,name=modulation,proto3" json:"modulation,omitempty"`
+	SignalStrength      string                 `protobuf:"bytes,4,opt,name=signal_strength,json=signalStrength,proto3" json:"signal_strength,omitempty"`
+	Snr                 string                 `protobuf:"bytes,5,opt,name=snr,proto3" json:"snr,omitempty"`
+	Octets              string                 `protobuf:"bytes,6,opt,name=octets,proto3" json:"octets,omitempty"`
+	Correcteds          string                 `protobuf:"bytes,7,opt,name=correcteds,proto3" json:"correcteds,omitempty"`
+	Uncorrectables      string                 `protobuf:"bytes,8,opt,name=uncorrectables,proto3" json:"uncorrectables,omitempty"`
+	ChannelId           string                 `protobuf:"bytes,9,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	PowerDbmv           *float64               `protobuf:"fixed64,10,opt,name=power_dbmv,json=powerDbmv,proto3,oneof" json:"power_dbmv,omitempty"`
+	SnrDb               *float64               `protobuf:"fixed64,11,opt,name=snr_db,json=snrDb,proto3,oneof" json:"snr_db,omitempty"`
+	FrequencyHz         *uint64                `protobuf:"varint,12,opt,name=frequency_hz,json=frequencyHz,proto3,oneof" json:"frequency_hz,omitempty"`
+	OctetsCount         *uint64                `protobuf:"varint,13,opt,name=octets_count,json=octetsCount,proto3,oneof" json:"octets_count,omitempty"`
+	CorrectedsCount     *uint64                `protobuf:"varint,14,opt,name=correcteds_count,json=correctedsCount,proto3,oneof" json:"correcteds_count,omitempty"`
+	UncorrectablesCount *uint64                `protobuf:"varint,15,opt,name=uncorrectables_count,json=uncorrectablesCount,proto3,oneof" json:"uncorrectables_count,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *DownstreamChannel) Reset() {
+	*x = DownstreamChannel{}
+	mi := &file_modem_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownstreamChannel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownstreamChannel) ProtoMessage() {}
+
+func (x *DownstreamChannel) ProtoReflect() protoreflect.Message {
+	mi := &file_modem_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownstreamChannel.ProtoReflect.Descriptor instead.
+func (*DownstreamChannel) Descriptor() ([]byte, []int) {
+	return file_modem_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DownstreamChannel) GetPortId() string {
+	if x != nil {
+		return x.PortId
+	}
+	return ""
+}
+
+func (x *DownstreamChannel) GetFrequency() string {
+	if x != nil {
+		return x.Frequency
+	}
+	return ""
+}
+
+func (x *DownstreamChannel) GetModulation() string {
+	if x != nil {
+		return x.Modulation
+	}
+	return ""
+}
+
+func (x *DownstreamChannel) GetSignalStrength() string {
+	if x != nil {
+		return x.SignalStrength
+	}
+	return ""
+}
+
+func (x *DownstreamChannel) GetSnr() string {
+	if x != nil {
+		return x.Snr
+	}
+	return ""
+}
+
+func (x *DownstreamChannel) GetOctets() string {
+	if x != nil {
+		return x.Octets
+	}
+	return ""
+}
+
+func (x *DownstreamChannel) GetCorrecteds() string {
+	if x != nil {
+		return x.Correcteds
+	}
+	return ""
+}
+
+func (x *DownstreamChannel) GetUncorrectables() string {
+	if x != nil {
+		return x.Uncorrectables
+	}
+	return ""
+}
+
+func (x *DownstreamChannel) GetChannelId() string {
+	if x != nil {
+		return x.ChannelId
+	}
+	return ""
+}
+
+func (x *DownstreamChannel) GetPowerDbmv() float64 {
+	if x != nil && x.PowerDbmv != nil {
+		return *x.PowerDbmv
+	}
+	return 0
+}
+
+func (x *DownstreamChannel) GetSnrDb() float64 {
+	if x != nil && x.SnrDb != nil {
+		return *x.SnrDb
+	}
+	return 0
+}
+
+func (x *DownstreamChannel) GetFrequencyHz() uint64 {
+	if x != nil && x.FrequencyHz != nil {
+		return *x.FrequencyHz
+	}
+	return 0
+}
+
+func (x *DownstreamChannel) GetOctetsCount() uint64 {
+	if x != nil && x.OctetsCount != nil {
+		return *x.OctetsCount
+	}
+	return 0
+}
+
+func (x *DownstreamChannel) GetCorrectedsCount() uint64 {
+	if x != nil && x.CorrectedsCount != nil {
+		return *x.CorrectedsCount
+	}
+	return 0
+}
+
+func (x *DownstreamChannel) GetUncorrectablesCount() uint64 {
+	if x != nil && x.UncorrectablesCount != nil {
+		return *x.UncorrectablesCount
+	}
+	return 0
+}
+
+// UpstreamChannel is one upstream SC-QAM channel from usinfo.asp.
+type UpstreamChannel struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PortId         string                 `protobuf:"bytes,1,opt,name=port_id,json=portId,proto3" json:"port_id,omitempty"`
+	Frequency      string                 `protobuf:"bytes,2,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	Bandwidth      string                 `protobuf:"bytes,3,opt,name=bandwidth,proto3" json:"bandwidth,omitempty"`
+	Modulation     string                 `protobuf:"bytes,4,opt,name=modulation,proto3" json:"modulation,omitempty"`
+	ScdmaMode      string                 `protobuf:"bytes,5,opt,name=scdma_mode,json=scdmaMode,proto3" json:"scdma_mode,omitempty"`
+	SignalStrength string                 `protobuf:"bytes,6,opt,name=signal_strength,json=signalStrength,proto3" json:"signal_strength,omitempty"`
+	ChannelId      string                 `protobuf:"bytes,7,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	PowerDbmv      *float64               `protobuf:"fixed64,8,opt,name=power_dbmv,json=powerDbmv,proto3,oneof" json:"power_dbmv,omitempty"`
+	FrequencyHz    *uint64                `protobuf:"varint,9,opt,name=frequency_hz,json=frequencyHz,proto3,oneof" json:"frequency_hz,omitempty"`
+	BandwidthHz    *uint64                `protobuf:"varint,10,opt,name=bandwidth_hz,json=bandwidthHz,proto3,oneof" json:"bandwidth_hz,omitempty"`
+	// scdma is true for S-CDMA and false for ATDMA.
+	Scdma         *bool `protobuf:"varint,11,opt,name=scdma,proto3,oneof" json:"scdma,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpstreamChannel) Reset() {
+	*x = UpstreamChannel{}
+	mi := &file_modem_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpstreamChannel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpstreamChannel) ProtoMessage() {}
+
+func (x *UpstreamChannel) ProtoReflect() protoreflect.Message {
+	mi := &file_modem_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpstreamChannel.ProtoReflect.Descriptor instead.
+func (*UpstreamChannel) Descriptor() ([]byte, []int) {
+	return file_modem_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpstreamChannel) GetPortId() string {
+	if x != nil {
+		return x.PortId
+	}
+	return ""
+}
+
+func (x *UpstreamChannel) GetFrequency() string {
+	if x != nil {
+		return x.Frequency
+	}
+	return ""
+}
+
+func (x *UpstreamChannel) GetBandwidth() string {
+	if x != nil {
+		return x.Bandwidth
+	}
+	return ""
+}
+
+func (x *UpstreamChannel) GetModulation() string {
+	if x != nil {
+		return x.Modulation
+	}
+	return ""
+}
+
+func (x *UpstreamChannel) GetScdmaMode() string {
+	if x != nil {
+		return x.ScdmaMode
+	}
+	return ""
+}
+
+func (x *UpstreamChannel) GetSignalStrength() string {
+	if x != nil {
+		return x.SignalStrength
+	}
+	return ""
+}
+
+func (x *UpstreamChannel) GetChannelId() string {
+	if x != nil {
+		return x.ChannelId
+	}
+	return ""
+}
+
+func (x *UpstreamChannel) GetPowerDbmv() float64 {
+	if x != nil && x.PowerDbmv != nil {
+		return *x.PowerDbmv
+	}
+	return 0
+}
+
+func (x *UpstreamChannel) GetFrequencyHz() uint64 {
+	if x != nil && x.FrequencyHz != nil {
+		return *x.FrequencyHz
+	}
+	return 0
+}
+
+func (x *UpstreamChannel) GetBandwidthHz() uint64 {
+	if x != nil && x.BandwidthHz != nil {
+		return *x.BandwidthHz
+	}
+	return 0
+}
+
+func (x *UpstreamChannel) GetScdma() bool {
+	if x != nil && x.Scdma != nil {
+		return *x.Scdma
+	}
+	return false
+}
+
+// OFDMDownstreamChannel is one downstream OFDM channel from dsofdminfo.asp.
+type OFDMDownstreamChannel struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Receive                string                 `protobuf:"bytes,1,opt,name=receive,proto3" json:"receive,omitempty"`
+	FftType                string                 `protobuf:"bytes,2,opt,name=fft_type,json=fftType,proto3" json:"fft_type,omitempty"`
+	Subcarrier0Frequency   string                 `protobuf:"bytes,3,opt,name=subcarrier0_frequency,json=subcarrier0Frequency,proto3" json:"subcarrier0_frequency,omitempty"`
+	PlcLock                string                 `protobuf:"bytes,4,opt,name=plc_lock,json=plcLock,proto3" json:"plc_lock,omitempty"`
+	NcpLock                string                 `protobuf:"bytes,5,opt,name=ncp_lock,json=ncpLock,proto3" json:"ncp_lock,omitempty"`
+	Mdc1Lock               string                 `protobuf:"bytes,6,opt,name=mdc1_lock,json=mdc1Lock,proto3" json:"mdc1_lock,omitempty"`
+	PlcPower               string                 `protobuf:"bytes,7,opt,name=plc_power,json=plcPower,proto3" json:"plc_power,omitempty"`
+	Snr                    string                 `protobuf:"bytes,8,opt,name=snr,proto3" json:"snr,omitempty"`
+	Octets                 string                 `protobuf:"bytes,9,opt,name=octets,proto3" json:"octets,omitempty"`
+	Correcteds             string                 `protobuf:"bytes,10,opt,name=correcteds,proto3" json:"correcteds,omitempty"`
+	Uncorrectables         string                 `protobuf:"bytes,11,opt,name=uncorrectables,proto3" json:"uncorrectables,omitempty"`
+	PowerDbmv              *float64               `protobuf:"fixed64,12,opt,name=power_dbmv,json=powerDbmv,proto3,oneof" json:"power_dbmv,omitempty"`
+	SnrDb                  *float64               `protobuf:"fixed64,13,opt,name=snr_db,json=snrDb,proto3,oneof" json:"snr_db,omitempty"`
+	Subcarrier0FrequencyHz *uint64                `protobuf:"varint,14,opt,name=subcarrier0_frequency_hz,json=subcarrier0FrequencyHz,proto3,oneof" json:"subcarrier0_frequency_hz,omitempty"`
+	OctetsCount            *uint64                `protobuf:"varint,15,opt,name=octets_count,json=octetsCount,proto3,oneof" json:"octets_count,omitempty"`
+	CorrectedsCount        *uint64                `protobuf:"varint,16,opt,name=correcteds_count,json=correctedsCount,proto3,oneof" json:"correcteds_count,omitempty"`
+	UncorrectablesCount    *uint64                `protobuf:"varint,17,opt,name=uncorrectables_count,json=uncorrectablesCount,proto3,oneof" json:"uncorrectables_count,omitempty"`
+	PlcLocked              bool                   `protobuf:"varint,18,opt,name=plc_locked,json=plcLocked,proto3" json:"plc_locked,omitempty"`
+	NcpLocked              bool                   `protobuf:"varint,19,opt,name=ncp_locked,json=ncpLocked,proto3" json:"ncp_locked,omitempty"`
+	Mdc1Locked             bool                   `protobuf:"varint,20,opt,name=mdc1_locked,json=mdc1Locked,proto3" json:"mdc1_locked,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *OFDMDownstreamChannel) Reset() {
+	*x = OFDMDownstreamChannel{}
+	mi := &file_modem_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OFDMDownstreamChannel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OFDMDownstreamChannel) ProtoMessage() {}
+
+func (x *OFDMDownstreamChannel) ProtoReflect() protoreflect.Message {
+	mi := &file_modem_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OFDMDownstreamChannel.ProtoReflect.Descriptor instead.
+func (*OFDMDownstreamChannel) Descriptor() ([]byte, []int) {
+	return file_modem_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OFDMDownstreamChannel) GetReceive() string {
+	if x != nil {
+		return x.Receive
+	}
+	return ""
+}
+
+func (x *OFDMDownstreamChannel) GetFftType() string {
+	if x != nil {
+		return x.FftType
+	}
+	return ""
+}
+
+func (x *OFDMDownstreamChannel) GetSubcarrier0Frequency() string {
+	if x != nil {
+		return x.Subcarrier0Frequency
+	}
+	return ""
+}
+
+func (x *OFDMDownstreamChannel) GetPlcLock() string {
+	if x != nil {
+		return x.PlcLock
+	}
+	return ""
+}
+
+func (x *OFDMDownstreamChannel) GetNcpLock() string {
+	if x != nil {
+		return x.NcpLock
+	}
+	return ""
+}
+
+func (x *OFDMDownstreamChannel) GetMdc1Lock() string {
+	if x != nil {
+		return x.Mdc1Lock
+	}
+	return ""
+}
+
+func (x *OFDMDownstreamChannel) GetPlcPower() string {
+	if x != nil {
+		return x.PlcPower
+	}
+	return ""
+}
+
+func (x *OFDMDownstreamChannel) GetSnr() string {
+	if x != nil {
+		return x.Snr
+	}
+	return ""
+}
+
+func (x *OFDMDownstreamChannel) GetOctets() string {
+	if x != nil {
+		return x.Octets
+	}
+	return ""
+}
+
+func (x *OFDMDownstreamChannel) GetCorrecteds() string {
+	if x != nil {
+		return x.Correcteds
+	}
+	return ""
+}
+
+func (x *OFDMDownstreamChannel) GetUncorrectables() string {
+	if x != nil {
+		return x.Uncorrectables
+	}
+	return ""
+}
+
+func (x *OFDMDownstreamChannel) GetPowerDbmv() float64 {
+	if x != nil && x.PowerDbmv != nil {
+		return *x.PowerDbmv
+	}
+	return 0
+}
+
+func (x *OFDMDownstreamChannel) GetSnrDb() float64 {
+	if x != nil && x.SnrDb != nil {
+		return *x.SnrDb
+	}
+	return 0
+}
+
+func (x *OFDMDownstreamChannel) GetSubcarrier0FrequencyHz() uint64 {
+	if x != nil && x.Subcarrier0FrequencyHz != nil {
+		return *x.Subcarrier0FrequencyHz
+	}
+	return 0
+}
+
+func (x *OFDMDownstreamChannel) GetOctetsCount() uint64 {
+	if x != nil && x.OctetsCount != nil {
+		return *x.OctetsCount
+	}
+	return 0
+}
+
+func (x *OFDMDownstreamChannel) GetCorrectedsCount() uint64 {
+	if x != nil && x.CorrectedsCount != nil {
+		return *x.CorrectedsCount
+	}
+	return 0
+}
+
+func (x *OFDMDownstreamChannel) GetUncorrectablesCount() uint64 {
+	if x != nil && x.UncorrectablesCount != nil {
+		return *x.UncorrectablesCount
+	}
+	return 0
+}
+
+func (x *OFDMDownstreamChannel) GetPlcLocked() bool {
+	if x != nil {
+		return x.PlcLocked
+	}
+	return false
+}
+
+func (x *OFDMDownstreamChannel) GetNcpLocked() bool {
+	if x != nil {
+		return x.NcpLocked
+	}
+	return false
+}
+
+func (x *OFDMDownstreamChannel) GetMdc1Locked() bool {
+	if x != nil {
+		return x.Mdc1Locked
+	}
+	return false
+}
+
+// OFDMUpstreamChannel is one upstream OFDMA channel from usofdminfo.asp.
+type OFDMUpstreamChannel struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	ChannelIndex              string                 `protobuf:"bytes,1,opt,name=channel_index,json=channelIndex,proto3" json:"channel_index,omitempty"`
+	State                     string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Frequency                 string                 `protobuf:"bytes,3,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	DigitalAttenuation        string                 `protobuf:"bytes,4,opt,name=digital_attenuation,json=digitalAttenuation,proto3" json:"digital_attenuation,omitempty"`
+	DigitalAttenuationBackoff string                 `protobuf:"bytes,5,opt,name=digital_attenuation_backoff,json=digitalAttenuationBackoff,proto3" json:"digital_attenuation_backoff,omitempty"`
+	ChannelBandwidth          string                 `protobuf:"bytes,6,opt,name=channel_bandwidth,json=channelBandwidth,proto3" json:"channel_bandwidth,omitempty"`
+	ReportedPower             string                 `protobuf:"bytes,7,opt,name=reported_power,json=reportedPower,proto3" json:"reported_power,omitempty"`
+	ReportedPower_1_6         string                 `protobuf:"bytes,8,opt,name=reported_power_1_6,json=reportedPower16,proto3" json:"reported_power_1_6,omitempty"`
+	FftSize                   string                 `protobuf:"bytes,9,opt,name=fft_size,json=fftSize,proto3" json:"fft_size,omitempty"`
+	// operating is true for a channel in the OPERATE state. The frequency,
+	// power and bandwidth are only set for channels in use.
+	Operating          bool     `protobuf:"varint,10,opt,name=operating,proto3" json:"operating,omitempty"`
+	FrequencyHz        *uint64  `protobuf:"varint,11,opt,name=frequency_hz,json=frequencyHz,proto3,oneof" json:"frequency_hz,omitempty"`
+	PowerDbmv          *float64 `protobuf:"fixed64,12,opt,name=power_dbmv,json=powerDbmv,proto3,oneof" json:"power_dbmv,omitempty"`
+	ChannelBandwidthHz *uint64  `protobuf:"varint,13,opt,name=channel_bandwidth_hz,json=channelBandwidthHz,proto3,oneof" json:"channel_bandwidth_hz,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *OFDMUpstreamChannel) Reset() {
+	*x = OFDMUpstreamChannel{}
+	mi := &file_modem_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OFDMUpstreamChannel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OFDMUpstreamChannel) ProtoMessage() {}
+
+func (x *OFDMUpstreamChannel) ProtoReflect() protoreflect.Message {
+	mi := &file_modem_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OFDMUpstreamChannel.ProtoReflect.Descriptor instead.
+func (*OFDMUpstreamChannel) Descriptor() ([]byte, []int) {
+	return file_modem_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OFDMUpstreamChannel) GetChannelIndex() string {
+	if x != nil {
+		return x.ChannelIndex
+	}
+	return ""
+}
+
+func (x *OFDMUpstreamChannel) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *OFDMUpstreamChannel) GetFrequency() string {
+	if x != nil {
+		return x.Frequency
+	}
+	return ""
+}
+
+func (x *OFDMUpstreamChannel) GetDigitalAttenuation() string {
+	if x != nil {
+		return x.DigitalAttenuation
+	}
+	return ""
+}
+
+func (x *OFDMUpstreamChannel) GetDigitalAttenuationBackoff() string {
+	if x != nil {
+		return x.DigitalAttenuationBackoff
+	}
+	return ""
+}
+
+func (x *OFDMUpstreamChannel) GetChannelBandwidth() string {
+	if x != nil {
+		return x.ChannelBandwidth
+	}
+	return ""
+}
+
+func (x *OFDMUpstreamChannel) GetReportedPower() string {
+	if x != nil {
+		return x.ReportedPower
+	}
+	return ""
+}
+
+func (x *OFDMUpstreamChannel) GetReportedPower_1_6() string {
+	if x != nil {
+		return x.ReportedPower_1_6
+	}
+	return ""
+}
+
+func (x *OFDMUpstreamChannel) GetFftSize() string {
+	if x != nil {
+		return x.FftSize
+	}
+	return ""
+}
+
+func (x *OFDMUpstreamChannel) GetOperating() bool {
+	if x != nil {
+		return x.Operating
+	}
+	return false
+}
+
+func (x *OFDMUpstreamChannel) GetFrequencyHz() uint64 {
+	if x != nil && x.FrequencyHz != nil {
+		return *x.FrequencyHz
+	}
+	return 0
+}
+
+func (x *OFDMUpstreamChannel) GetPowerDbmv() float64 {
+	if x != nil && x.PowerDbmv != nil {
+		return *x.PowerDbmv
+	}
+	return 0
+}
+
+func (x *OFDMUpstreamChannel) GetChannelBandwidthHz() uint64 {
+	if x != nil && x.ChannelBandwidthHz != nil {
+		return *x.ChannelBandwidthHz
+	}
+	return 0
+}
+
+// SystemInfo is the modem's identity and uptime from getSysInfo.asp.
+type SystemInfo struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	HwVersion          string                 `protobuf:"bytes,1,opt,name=hw_version,json=hwVersion,proto3" json:"hw_version,omitempty"`
+	SwVersion          string                 `protobuf:"bytes,2,opt,name=sw_version,json=swVersion,proto3" json:"sw_version,omitempty"`
+	SerialNumber       string                 `protobuf:"bytes,3,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	RfMac              string                 `protobuf:"bytes,4,opt,name=rf_mac,json=rfMac,proto3" json:"rf_mac,omitempty"`
+	WanIp              string                 `protobuf:"bytes,5,opt,name=wan_ip,json=wanIp,proto3" json:"wan_ip,omitempty"`
+	SystemUptime       string                 `protobuf:"bytes,6,opt,name=system_uptime,json=systemUptime,proto3" json:"system_uptime,omitempty"`
+	SystemTime         string                 `protobuf:"bytes,7,opt,name=system_time,json=systemTime,proto3" json:"system_time,omitempty"`
+	Timezone           string                 `protobuf:"bytes,8,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	WanReceivedPackets string                 `protobuf:"bytes,9,opt,name=wan_received_packets,json=wanReceivedPackets,proto3" json:"wan_received_packets,omitempty"`
+	WanSentPackets     string                 `protobuf:"bytes,10,opt,name=wan_sent_packets,json=wanSentPackets,proto3" json:"wan_sent_packets,omitempty"`
+	LanIp              string                 `protobuf:"bytes,11,opt,name=lan_ip,json=lanIp,proto3" json:"lan_ip,omitempty"`
+	LanReceivedPackets string                 `protobuf:"bytes,12,opt,name=lan_received_packets,json=lanReceivedPackets,proto3" json:"lan_received_packets,omitempty"`
+	LanSentPackets     string                 `protobuf:"bytes,13,opt,name=lan_sent_packets,json=lanSentPackets,proto3" json:"lan_sent_packets,omitempty"`
+	Uptime             *durationpb.Duration   `protobuf:"bytes,14,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	// The *_packets fields count bytes, despite their name.
+	WanReceivedBytes *uint64 `protobuf:"varint,15,opt,name=wan_received_bytes,json=wanReceivedBytes,proto3,oneof" json:"wan_received_bytes,omitempty"`
+	WanSentBytes     *uint64 `protobuf:"varint,16,opt,name=wan_sent_bytes,json=wanSentBytes,proto3,oneof" json:"wan_sent_bytes,omitempty"`
+	LanReceivedBytes *uint64 `protobuf:"varint,17,opt,name=lan_received_bytes,json=lanReceivedBytes,proto3,oneof" json:"lan_received_bytes,omitempty"`
+	LanSentBytes     *uint64 `protobuf:"varint,18,opt,name=lan_sent_bytes,json=lanSentBytes,proto3,oneof" json:"lan_sent_bytes,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SystemInfo) Reset() {
+	*x = SystemInfo{}
+	mi := &file_modem_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemInfo) ProtoMessage() {}
+
+func (x *SystemInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_modem_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemInfo.ProtoReflect.Descriptor instead.
+func (*SystemInfo) Descriptor() ([]byte, []int) {
+	return file_modem_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SystemInfo) GetHwVersion() string {
+	if x != nil {
+		return x.HwVersion
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetSwVersion() string {
+	if x != nil {
+		return x.SwVersion
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetRfMac() string {
+	if x != nil {
+		return x.RfMac
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetWanIp() string {
+	if x != nil {
+		return x.WanIp
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetSystemUptime() string {
+	if x != nil {
+		return x.SystemUptime
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetSystemTime() string {
+	if x != nil {
+		return x.SystemTime
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetWanReceivedPackets() string {
+	if x != nil {
+		return x.WanReceivedPackets
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetWanSentPackets() string {
+	if x != nil {
+		return x.WanSentPackets
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetLanIp() string {
+	if x != nil {
+		return x.LanIp
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetLanReceivedPackets() string {
+	if x != nil {
+		return x.LanReceivedPackets
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetLanSentPackets() string {
+	if x != nil {
+		return x.LanSentPackets
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetUptime() *durationpb.Duration {
+	if x != nil {
+		return x.Uptime
+	}
+	return nil
+}
+
+func (x *SystemInfo) GetWanReceivedBytes() uint64 {
+	if x != nil && x.WanReceivedBytes != nil {
+		return *x.WanReceivedBytes
+	}
+	return 0
+}
+
+func (x *SystemInfo) GetWanSentBytes() uint64 {
+	if x != nil && x.WanSentBytes != nil {
+		return *x.WanSentBytes
+	}
+	return 0
+}
+
+func (x *SystemInfo) GetLanReceivedBytes() uint64 {
+	if x != nil && x.LanReceivedBytes != nil {
+		return *x.LanReceivedBytes
+	}
+	return 0
+}
+
+func (x *SystemInfo) GetLanSentBytes() uint64 {
+	if x != nil && x.LanSentBytes != nil {
+		return *x.LanSentBytes
+	}
+	return 0
+}
+
+// LinkStatus is the Ethernet link state from getLinkStatus.asp.
+type LinkStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LinkStatus    string                 `protobuf:"bytes,1,opt,name=link_status,json=linkStatus,proto3" json:"link_status,omitempty"`
+	LinkDuplex    string                 `protobuf:"bytes,2,opt,name=link_duplex,json=linkDuplex,proto3" json:"link_duplex,omitempty"`
+	LinkSpeed     string                 `protobuf:"bytes,3,opt,name=link_speed,json=linkSpeed,proto3" json:"link_speed,omitempty"`
+	LinkUp        bool                   `protobuf:"varint,4,opt,name=link_up,json=linkUp,proto3" json:"link_up,omitempty"`
+	LinkSpeedBps  *uint64                `protobuf:"varint,5,opt,name=link_speed_bps,json=linkSpeedBps,proto3,oneof" json:"link_speed_bps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkStatus) Reset() {
+	*x = LinkStatus{}
+	mi := &file_modem_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkStatus) ProtoMessage() {}
+
+func (x *LinkStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_modem_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkStatus.ProtoReflect.Descriptor instead.
+func (*LinkStatus) Descriptor() ([]byte, []int) {
+	return file_modem_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *LinkStatus) GetLinkStatus() string {
+	if x != nil {
+		return x.LinkStatus
+	}
+	return ""
+}
+
+func (x *LinkStatus) GetLinkDuplex() string {
+	if x != nil {
+		return x.LinkDuplex
+	}
+	return ""
+}
+
+func (x *LinkStatus) GetLinkSpeed() string {
+	if x != nil {
+		return x.LinkSpeed
+	}
+	return ""
+}
+
+func (x *LinkStatus) GetLinkUp() bool {
+	if x != nil {
+		return x.LinkUp
+	}
+	return false
+}
+
+func (x *LinkStatus) GetLinkSpeedBps() uint64 {
+	if x != nil && x.LinkSpeedBps != nil {
+		return *x.LinkSpeedBps
+	}
+	return 0
+}
+
+var File_modem_proto protoreflect.FileDescriptor
+
+var file_modem_proto_rawDesc = string([]byte{
+	0x0a, 0x0b, 0x6d, 0x6f, 0x64, 0x65, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x63,
+	0x6f, 0x64, 0x61, 0x35, 0x36, 0x2e, 0x76, 0x31, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x25, 0x0a, 0x0d, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f,
+	0x64, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6d,
+	0x22, 0xa0, 0x04, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6d,
+	0x6f, 0x64, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65,
+	0x6d, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d,
+	0x65, 0x12, 0x3c, 0x0a, 0x0a, 0x64, 0x6f, 0x77, 0x6e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x6f, 0x64, 0x61, 0x35, 0x36, 0x2e, 0x76,
+	0x31, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x52, 0x0a, 0x64, 0x6f, 0x77, 0x6e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12,
+	0x36, 0x0a, 0x08, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x6f, 0x64, 0x61, 0x35, 0x36, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70,
+	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x08, 0x75,
+	0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x49, 0x0a, 0x0f, 0x6f, 0x66, 0x64, 0x6d, 0x5f,
+	0x64, 0x6f, 0x77, 0x6e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x20, 0x2e, 0x63, 0x6f, 0x64, 0x61, 0x35, 0x36, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x46, 0x44,
+	0x4d, 0x44, 0x6f, 0x77, 0x6e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x52, 0x0e, 0x6f, 0x66, 0x64, 0x6d, 0x44, 0x6f, 0x77, 0x6e, 0x73, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x12, 0x43, 0x0a, 0x0d, 0x6f, 0x66, 0x64, 0x6d, 0x5f, 0x75, 0x70, 0x73, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x63, 0x6f, 0x64, 0x61,
+	0x35, 0x36, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x46, 0x44, 0x4d, 0x55, 0x70, 0x73, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x0c, 0x6f, 0x66, 0x64, 0x6d, 0x55,
+	0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65,
+	0x6d, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x6f, 0x64, 0x61, 0x35, 0x36,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06,
+	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x29, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x6b, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x6f, 0x64, 0x61, 0x35, 0x36, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x69, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x04, 0x6c, 0x69, 0x6e,
+	0x6b, 0x12, 0x35, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1d, 0x2e, 0x63, 0x6f, 0x64, 0x61, 0x35, 0x36, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0x86, 0x05, 0x0a, 0x11, 0x44, 0x6f, 0x77, 0x6e, 0x73, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x72,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x72, 0x74,
+	0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79,
+	0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x27, 0x0a, 0x0f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x6c, 0x53, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x6e, 0x72,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x6e, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x6f,
+	0x63, 0x74, 0x65, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x63, 0x74,
+	0x65, 0x74, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x75, 0x6e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74,
+	0x61, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x75, 0x6e, 0x63,
+	0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x63,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0a, 0x70, 0x6f,
+	0x77, 0x65, 0x72, 0x5f, 0x64, 0x62, 0x6d, 0x76, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00,
+	0x52, 0x09, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x44, 0x62, 0x6d, 0x76, 0x88, 0x01, 0x01, 0x12, 0x1a,
+	0x0a, 0x06, 0x73, 0x6e, 0x72, 0x5f, 0x64, 0x62, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01,
+	0x52, 0x05, 0x73, 0x6e, 0x72, 0x44, 0x62, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x66, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x68, 0x7a, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x04,
+	0x48, 0x02, 0x52, 0x0b, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x48, 0x7a, 0x88,
+	0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x6f, 0x63, 0x74, 0x65, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x04, 0x48, 0x03, 0x52, 0x0b, 0x6f, 0x63, 0x74, 0x65,
+	0x74, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x2e, 0x0a, 0x10, 0x63, 0x6f,
+	0x72, 0x72, 0x65, 0x63, 0x74, 0x65, 0x64, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x04, 0x48, 0x04, 0x52, 0x0f, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x36, 0x0a, 0x14, 0x75, 0x6e,
+	0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x04, 0x48, 0x05, 0x52, 0x13, 0x75, 0x6e, 0x63, 0x6f,
+	0x72, 0x72, 0x65, 0x63, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x88,
+	0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x64, 0x62, 0x6d,
+	0x76, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x6e, 0x72, 0x5f, 0x64, 0x62, 0x42, 0x0f, 0x0a, 0x0d,
+	0x5f, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x68, 0x7a, 0x42, 0x0f, 0x0a,
+	0x0d, 0x5f, 0x6f, 0x63, 0x74, 0x65, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x13,
+	0x0a, 0x11, 0x5f, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x65, 0x64, 0x73, 0x5f, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x42, 0x17, 0x0a, 0x15, 0x5f, 0x75, 0x6e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63,
+	0x74, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xb7, 0x03, 0x0a,
+	0x0f, 0x55, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x70, 0x6f, 0x72, 0x74, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x64, 0x77,
+	0x69, 0x64, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62, 0x61, 0x6e, 0x64,
+	0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x6f, 0x64, 0x75, 0x6c,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x64, 0x6d, 0x61, 0x5f, 0x6d,
+	0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x63, 0x64, 0x6d, 0x61,
+	0x4d, 0x6f, 0x64, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x5f, 0x73,
+	0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73,
+	0x69, 0x67, 0x6e, 0x61, 0x6c, 0x53, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x1d, 0x0a,
+	0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0a,
+	0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x64, 0x62, 0x6d, 0x76, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01,
+	0x48, 0x00, 0x52, 0x09, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x44, 0x62, 0x6d, 0x76, 0x88, 0x01, 0x01,
+	0x12, 0x26, 0x0a, 0x0c, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x68, 0x7a,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x48, 0x01, 0x52, 0x0b, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x79, 0x48, 0x7a, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x62, 0x61, 0x6e, 0x64,
+	0x77, 0x69, 0x64, 0x74, 0x68, 0x5f, 0x68, 0x7a, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x48, 0x02,
+	0x52, 0x0b, 0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x48, 0x7a, 0x88, 0x01, 0x01,
+	0x12, 0x19, 0x0a, 0x05, 0x73, 0x63, 0x64, 0x6d, 0x61, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x48,
+	0x03, 0x52, 0x05, 0x73, 0x63, 0x64, 0x6d, 0x61, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f,
+	0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x64, 0x62, 0x6d, 0x76, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x66,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x68, 0x7a, 0x42, 0x0f, 0x0a, 0x0d, 0x5f,
+	0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x5f, 0x68, 0x7a, 0x42, 0x08, 0x0a, 0x06,
+	0x5f, 0x73, 0x63, 0x64, 0x6d, 0x61, 0x22, 0xc7, 0x06, 0x0a, 0x15, 0x4f, 0x46, 0x44, 0x4d, 0x44,
+	0x6f, 0x77, 0x6e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x66, 0x66,
+	0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x66, 0x66,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x33, 0x0a, 0x15, 0x73, 0x75, 0x62, 0x63, 0x61, 0x72, 0x72,
+	0x69, 0x65, 0x72, 0x30, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x73, 0x75, 0x62, 0x63, 0x61, 0x72, 0x72, 0x69, 0x65, 0x72,
+	0x30, 0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x6c,
+	0x63, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x6c,
+	0x63, 0x4c, 0x6f, 0x63, 0x6b, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x63, 0x70, 0x5f, 0x6c, 0x6f, 0x63,
+	0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x63, 0x70, 0x4c, 0x6f, 0x63, 0x6b,
+	0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x64, 0x63, 0x31, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x64, 0x63, 0x31, 0x4c, 0x6f, 0x63, 0x6b, 0x12, 0x1b, 0x0a,
+	0x09, 0x70, 0x6c, 0x63, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x70, 0x6c, 0x63, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x6e,
+	0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x6e, 0x72, 0x12, 0x16, 0x0a, 0x06,
+	0x6f, 0x63, 0x74, 0x65, 0x74, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x63,
+	0x74, 0x65, 0x74, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x75, 0x6e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63,
+	0x74, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x75, 0x6e,
+	0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x12, 0x22, 0x0a, 0x0a,
+	0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x64, 0x62, 0x6d, 0x76, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x01,
+	0x48, 0x00, 0x52, 0x09, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x44, 0x62, 0x6d, 0x76, 0x88, 0x01, 0x01,
+	0x12, 0x1a, 0x0a, 0x06, 0x73, 0x6e, 0x72, 0x5f, 0x64, 0x62, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x01,
+	0x48, 0x01, 0x52, 0x05, 0x73, 0x6e, 0x72, 0x44, 0x62, 0x88, 0x01, 0x01, 0x12, 0x3d, 0x0a, 0x18,
+	0x73, 0x75, 0x62, 0x63, 0x61, 0x72, 0x72, 0x69, 0x65, 0x72, 0x30, 0x5f, 0x66, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x68, 0x7a, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x04, 0x48, 0x02,
+	0x52, 0x16, 0x73, 0x75, 0x62, 0x63, 0x61, 0x72, 0x72, 0x69, 0x65, 0x72, 0x30, 0x46, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x48, 0x7a, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x6f,
+	0x63, 0x74, 0x65, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28,
+	0x04, 0x48, 0x03, 0x52, 0x0b, 0x6f, 0x63, 0x74, 0x65, 0x74, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x2e, 0x0a, 0x10, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x04, 0x48, 0x04, 0x52,
+	0x0f, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x65, 0x64, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x36, 0x0a, 0x14, 0x75, 0x6e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74,
+	0x61, 0x62, 0x6c, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28,
+	0x04, 0x48, 0x05, 0x52, 0x13, 0x75, 0x6e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x0a, 0x70,
+	0x6c, 0x63, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x12, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x09, 0x70, 0x6c, 0x63, 0x4c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x63,
+	0x70, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x13, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09,
+	0x6e, 0x63, 0x70, 0x4c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x64, 0x63,
+	0x31, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x14, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x6d, 0x64, 0x63, 0x31, 0x4c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x70,
+	0x6f, 0x77, 0x65, 0x72, 0x5f, 0x64, 0x62, 0x6d, 0x76, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x6e,
+	0x72, 0x5f, 0x64, 0x62, 0x42, 0x1b, 0x0a, 0x19, 0x5f, 0x73, 0x75, 0x62, 0x63, 0x61, 0x72, 0x72,
+	0x69, 0x65, 0x72, 0x30, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x68,
+	0x7a, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6f, 0x63, 0x74, 0x65, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x17, 0x0a, 0x15, 0x5f, 0x75, 0x6e, 0x63, 0x6f,
+	0x72, 0x72, 0x65, 0x63, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x22, 0xd5, 0x04, 0x0a, 0x13, 0x4f, 0x46, 0x44, 0x4d, 0x55, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x14, 0x0a,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x79, 0x12, 0x2f, 0x0a, 0x13, 0x64, 0x69, 0x67, 0x69, 0x74, 0x61, 0x6c, 0x5f, 0x61, 0x74, 0x74,
+	0x65, 0x6e, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12,
+	0x64, 0x69, 0x67, 0x69, 0x74, 0x61, 0x6c, 0x41, 0x74, 0x74, 0x65, 0x6e, 0x75, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x3e, 0x0a, 0x1b, 0x64, 0x69, 0x67, 0x69, 0x74, 0x61, 0x6c, 0x5f, 0x61, 0x74,
+	0x74, 0x65, 0x6e, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66,
+	0x66, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x19, 0x64, 0x69, 0x67, 0x69, 0x74, 0x61, 0x6c,
+	0x41, 0x74, 0x74, 0x65, 0x6e, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x61, 0x63, 0x6b, 0x6f,
+	0x66, 0x66, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x62, 0x61,
+	0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x63,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12,
+	0x25, 0x0a, 0x0e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x70, 0x6f, 0x77, 0x65,
+	0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65,
+	0x64, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x2b, 0x0a, 0x12, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74,
+	0x65, 0x64, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x31, 0x5f, 0x36, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0f, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x50, 0x6f, 0x77, 0x65,
+	0x72, 0x31, 0x36, 0x12, 0x19, 0x0a, 0x08, 0x66, 0x66, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x66, 0x66, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1c,
+	0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x26, 0x0a, 0x0c,
+	0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x68, 0x7a, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x04, 0x48, 0x00, 0x52, 0x0b, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x48,
+	0x7a, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x64, 0x62,
+	0x6d, 0x76, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x09, 0x70, 0x6f, 0x77, 0x65,
+	0x72, 0x44, 0x62, 0x6d, 0x76, 0x88, 0x01, 0x01, 0x12, 0x35, 0x0a, 0x14, 0x63, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x5f, 0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x5f, 0x68, 0x7a,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x04, 0x48, 0x02, 0x52, 0x12, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x48, 0x7a, 0x88, 0x01, 0x01, 0x42,
+	0x0f, 0x0a, 0x0d, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x68, 0x7a,
+	0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x64, 0x62, 0x6d, 0x76, 0x42,
+	0x17, 0x0a, 0x15, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x62, 0x61, 0x6e, 0x64,
+	0x77, 0x69, 0x64, 0x74, 0x68, 0x5f, 0x68, 0x7a, 0x22, 0x91, 0x06, 0x0a, 0x0a, 0x53, 0x79, 0x73,
+	0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x77, 0x5f, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x68, 0x77, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x77, 0x5f, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x77, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x5f,
+	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x65,
+	0x72, 0x69, 0x61, 0x6c, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x15, 0x0a, 0x06, 0x72, 0x66,
+	0x5f, 0x6d, 0x61, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x66, 0x4d, 0x61,
+	0x63, 0x12, 0x15, 0x0a, 0x06, 0x77, 0x61, 0x6e, 0x5f, 0x69, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x77, 0x61, 0x6e, 0x49, 0x70, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x79, 0x73, 0x74,
+	0x65, 0x6d, 0x5f, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x55, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x1f, 0x0a,
+	0x0b, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x12, 0x30, 0x0a, 0x14, 0x77, 0x61,
+	0x6e, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x5f, 0x70, 0x61, 0x63, 0x6b, 0x65,
+	0x74, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x77, 0x61, 0x6e, 0x52, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x65, 0x64, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12, 0x28, 0x0a, 0x10,
+	0x77, 0x61, 0x6e, 0x5f, 0x73, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x73,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x77, 0x61, 0x6e, 0x53, 0x65, 0x6e, 0x74, 0x50,
+	0x61, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x6c, 0x61, 0x6e, 0x5f, 0x69, 0x70,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x6e, 0x49, 0x70, 0x12, 0x30, 0x0a,
+	0x14, 0x6c, 0x61, 0x6e, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x5f, 0x70, 0x61,
+	0x63, 0x6b, 0x65, 0x74, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x6c, 0x61, 0x6e,
+	0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12,
+	0x28, 0x0a, 0x10, 0x6c, 0x61, 0x6e, 0x5f, 0x73, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x63, 0x6b,
+	0x65, 0x74, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x6c, 0x61, 0x6e, 0x53, 0x65,
+	0x6e, 0x74, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12, 0x31, 0x0a, 0x06, 0x75, 0x70, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x31, 0x0a, 0x12,
+	0x77, 0x61, 0x6e, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x10, 0x77, 0x61, 0x6e, 0x52,
+	0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12,
+	0x29, 0x0a, 0x0e, 0x77, 0x61, 0x6e, 0x5f, 0x73, 0x65, 0x6e, 0x74, 0x5f, 0x62, 0x79, 0x74, 0x65,
+	0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x04, 0x48, 0x01, 0x52, 0x0c, 0x77, 0x61, 0x6e, 0x53, 0x65,
+	0x6e, 0x74, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x31, 0x0a, 0x12, 0x6c, 0x61,
+	0x6e, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73,
+	0x18, 0x11, 0x20, 0x01, 0x28, 0x04, 0x48, 0x02, 0x52, 0x10, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a,
+	0x0e, 0x6c, 0x61, 0x6e, 0x5f, 0x73, 0x65, 0x6e, 0x74, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18,
+	0x12, 0x20, 0x01, 0x28, 0x04, 0x48, 0x03, 0x52, 0x0c, 0x6c, 0x61, 0x6e, 0x53, 0x65, 0x6e, 0x74,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x77, 0x61, 0x6e,
+	0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x42,
+	0x11, 0x0a, 0x0f, 0x5f, 0x77, 0x61, 0x6e, 0x5f, 0x73, 0x65, 0x6e, 0x74, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x6c, 0x61, 0x6e, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69,
+	0x76, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x6c, 0x61,
+	0x6e, 0x5f, 0x73, 0x65, 0x6e, 0x74, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x22, 0xc4, 0x01, 0x0a,
+	0x0a, 0x4c, 0x69, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6c,
+	0x69, 0x6e, 0x6b, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x6c, 0x69, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x64, 0x75, 0x70, 0x6c, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x6c, 0x69, 0x6e, 0x6b, 0x44, 0x75, 0x70, 0x6c, 0x65, 0x78, 0x12, 0x1d, 0x0a,
+	0x0a, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x6c, 0x69, 0x6e, 0x6b, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x17, 0x0a, 0x07,
+	0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x75, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6c,
+	0x69, 0x6e, 0x6b, 0x55, 0x70, 0x12, 0x29, 0x0a, 0x0e, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x73, 0x70,
+	0x65, 0x65, 0x64, 0x5f, 0x62, 0x70, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52,
+	0x0c, 0x6c, 0x69, 0x6e, 0x6b, 0x53, 0x70, 0x65, 0x65, 0x64, 0x42, 0x70, 0x73, 0x88, 0x01, 0x01,
+	0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x5f,
+	0x62, 0x70, 0x73, 0x32, 0x80, 0x01, 0x0a, 0x0c, 0x4d, 0x6f, 0x64, 0x65, 0x6d, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x18, 0x2e, 0x63, 0x6f, 0x64, 0x61, 0x35, 0x36, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x63, 0x6f,
+	0x64, 0x61, 0x35, 0x36, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x36,
+	0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x18, 0x2e, 0x63, 0x6f, 0x64, 0x61, 0x35, 0x36,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x11, 0x2e, 0x63, 0x6f, 0x64, 0x61, 0x35, 0x36, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x30, 0x01, 0x42, 0x32, 0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x6e, 0x75, 0x70, 0x63, 0x73, 0x68, 0x61, 0x6e, 0x2f, 0x63,
+	0x6f, 0x64, 0x61, 0x35, 0x36, 0x2d, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2f, 0x70,
+	0x6b, 0x67, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6d, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+})
+
+var (
+	file_modem_proto_rawDescOnce sync.Once
+	file_modem_proto_rawDescData []byte
+)
+
+func file_modem_proto_rawDescGZIP() []byte {
+	file_modem_proto_rawDescOnce.Do(func() {
+		file_modem_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_modem_proto_rawDesc), len(file_modem_proto_rawDesc)))
+	})
+	return file_modem_proto_rawDescData
+}
+
+var file_modem_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_modem_proto_goTypes = []any{
+	(*StatusRequest)(nil),         // 0: coda56.v1.StatusRequest
+	(*Status)(nil),                // 1: coda56.v1.Status
+	(*DownstreamChannel)(nil),     // 2: coda56.v1.DownstreamChannel
+	(*UpstreamChannel)(nil),       // 3: coda56.v1.UpstreamChannel
+	(*OFDMDownstreamChannel)(nil), // 4: coda56.v1.OFDMDownstreamChannel
+	(*OFDMUpstreamChannel)(nil),   // 5: coda56.v1.OFDMUpstreamChannel
+	(*SystemInfo)(nil),            // 6: coda56.v1.SystemInfo
+	(*LinkStatus)(nil),            // 7: coda56.v1.LinkStatus
+	nil,                           // 8: coda56.v1.Status.ErrorsEntry
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 10: google.protobuf.Duration
+}
+var file_modem_proto_depIdxs = []int32{
+	9,  // 0: coda56.v1.Status.time:type_name -> google.protobuf.Timestamp
+	2,  // 1: coda56.v1.Status.downstream:type_name -> coda56.v1.DownstreamChannel
+	3,  // 2: coda56.v1.Status.upstream:type_name -> coda56.v1.UpstreamChannel
+	4,  // 3: coda56.v1.Status.ofdm_downstream:type_name -> coda56.v1.OFDMDownstreamChannel
+	5,  // 4: coda56.v1.Status.ofdm_upstream:type_name -> coda56.v1.OFDMUpstreamChannel
+	6,  // 5: coda56.v1.Status.system:type_name -> coda56.v1.SystemInfo
+	7,  // 6: coda56.v1.Status.link:type_name -> coda56.v1.LinkStatus
+	8,  // 7: coda56.v1.Status.errors:type_name -> coda56.v1.Status.ErrorsEntry
+	10, // 8: coda56.v1.SystemInfo.uptime:type_name -> google.protobuf.Duration
+	0,  // 9: coda56.v1.ModemService.GetStatus:input_type -> coda56.v1.StatusRequest
+	0,  // 10: coda56.v1.ModemService.Watch:input_type -> coda56.v1.StatusRequest
+	1,  // 11: coda56.v1.ModemService.GetStatus:output_type -> coda56.v1.Status
+	1,  // 12: coda56.v1.ModemService.Watch:output_type -> coda56.v1.Status
+	11, // [11:13] is the sub-list for method output_type
+	9,  // [9:11] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_modem_proto_init() }
+func file_modem_proto_init() {
+	if File_modem_proto != nil {
+		return
+	}
+	file_modem_proto_msgTypes[2].OneofWrappers = []any{}
+	file_modem_proto_msgTypes[3].OneofWrappers = []any{}
+	file_modem_proto_msgTypes[4].OneofWrappers = []any{}
+	file_modem_proto_msgTypes[5].OneofWrappers = []any{}
+	file_modem_proto_msgTypes[6].OneofWrappers = []any{}
+	file_modem_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_modem_proto_rawDesc), len(file_modem_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_modem_proto_goTypes,
+		DependencyIndexes: file_modem_proto_depIdxs,
+		MessageInfos:      file_modem_proto_msgTypes,
+	}.Build()
+	File_modem_proto = out.File
+	file_modem_proto_goTypes = nil
+	file_modem_proto_depIdxs = nil
+}
@@ -0,0 +1,190 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: modem.proto
+
+// The modem data the exporter polls, for services that would rather
+// subscribe to it than scrape the metrics. The messages mirror the JSON of
+// /api/v1/status, with the strings the modem reports, and add the values
+// parsed from them as the exporter's metrics have them. A parsed value is
+// unset when the modem didn't report it or it didn't parse.
+
+package modempb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	ModemService_GetStatus_FullMethodName = "/coda56.v1.ModemService/GetStatus"
+	ModemService_Watch_FullMethodName     = "/coda56.v1.ModemService/Watch"
+)
+
+// ModemServiceClient is the client API for ModemService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ModemServiceClient interface {
+	// GetStatus returns the data of the most recent poll, polling first when
+	// the exporter polls on scrape.
+	GetStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*Status, error)
+	// Watch sends the data of the most recent poll, if any, and then the data
+	// of every later poll until the call is cancelled.
+	Watch(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (ModemService_WatchClient, error)
+}
+
+type modemServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewModemServiceClient(cc grpc.ClientConnInterface) ModemServiceClient {
+	return &modemServiceClient{cc}
+}
+
+func (c *modemServiceClient) GetStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*Status, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Status)
+	err := c.cc.Invoke(ctx, ModemService_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *modemServiceClient) Watch(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (ModemService_WatchClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ModemService_ServiceDesc.Streams[0], ModemService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &modemServiceWatchClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ModemService_WatchClient interface {
+	Recv() (*Status, error)
+	grpc.ClientStream
+}
+
+type modemServiceWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *modemServiceWatchClient) Recv() (*Status, error) {
+	m := new(Status)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ModemServiceServer is the server API for ModemService service.
+// All implementations must embed UnimplementedModemServiceServer
+// for forward compatibility
+type ModemServiceServer interface {
+	// GetStatus returns the data of the most recent poll, polling first when
+	// the exporter polls on scrape.
+	GetStatus(context.Context, *StatusRequest) (*Status, error)
+	// Watch sends the data of the most recent poll, if any, and then the data
+	// of every later poll until the call is cancelled.
+	Watch(*StatusRequest, ModemService_WatchServer) error
+	mustEmbedUnimplementedModemServiceServer()
+}
+
+// UnimplementedModemServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedModemServiceServer struct {
+}
+
+func (UnimplementedModemServiceServer) GetStatus(context.Context, *StatusRequest) (*Status, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedModemServiceServer) Watch(*StatusRequest, ModemService_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedModemServiceServer) mustEmbedUnimplementedModemServiceServer() {}
+
+// UnsafeModemServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ModemServiceServer will
+// result in compilation errors.
+type UnsafeModemServiceServer interface {
+	mustEmbedUnimplementedModemServiceServer()
+}
+
+func RegisterModemServiceServer(s grpc.ServiceRegistrar, srv ModemServiceServer) {
+	s.RegisterService(&ModemService_ServiceDesc, srv)
+}
+
+func _ModemService_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModemServiceServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ModemService_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModemServiceServer).GetStatus(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ModemService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ModemServiceServer).Watch(m, &modemServiceWatchServer{ServerStream: stream})
+}
+
+type ModemService_WatchServer interface {
+	Send(*Status) error
+	grpc.ServerStream
+}
+
+type modemServiceWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *modemServiceWatchServer) Send(m *Status) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ModemService_ServiceDesc is the grpc.ServiceDesc for ModemService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ModemService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "coda56.v1.ModemService",
+	HandlerType: (*ModemServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatus",
+			Handler:    _ModemService_GetStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _ModemService_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "modem.proto",
+}